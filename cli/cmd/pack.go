@@ -1,51 +1,99 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 
 	"github.com/apex/log"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/modules"
 	"github.com/tarantool/tt/cli/pack"
 	"github.com/tarantool/tt/cli/util"
 	"github.com/tarantool/tt/lib/integrity"
+	"golang.org/x/exp/slices"
 )
 
 // packCtx contains information for tt pack command.
 var packCtx = &pack.PackCtx{}
 
+// Exit codes of tt pack command.
+const (
+	// packExitFailure is an exit code of other pack failures.
+	packExitFailure = 1
+	// packExitConfig is an exit code of invalid options or environment configuration.
+	packExitConfig = 2
+	// packExitUnsupportedType is an exit code of unsupported package type.
+	packExitUnsupportedType = 3
+	// packExitBinaryNotFound is an exit code of missing binaries to include.
+	packExitBinaryNotFound = 4
+	// packExitIO is an exit code of the package write errors, for example, full disk.
+	packExitIO = 5
+)
+
+// errUnknownPackType is reported if the package type argument is not supported.
+var errUnknownPackType = errors.New("unknown package type")
+
+// packConfigError is an error of the pack options or the environment configuration check.
+type packConfigError struct {
+	err error
+}
+
+// Error returns the check error message.
+func (configErr *packConfigError) Error() string {
+	return configErr.err.Error()
+}
+
+// Unwrap returns the check error.
+func (configErr *packConfigError) Unwrap() error {
+	return configErr.err
+}
+
 func NewPackCmd() *cobra.Command {
 	var packCmd = &cobra.Command{Use: "pack TYPE [flags] ..",
 		Short: "Pack application into a distributable bundle",
 		Long: `Pack application into a distributable bundle
 
-The supported types are: tgz, deb, rpm`,
-		ValidArgs: []string{"tgz", "deb", "rpm"},
-		Run: func(cmd *cobra.Command, args []string) {
-			err := cobra.ExactArgs(1)(cmd, args)
-			if err != nil {
-				err = fmt.Errorf("incorrect combination of command parameters: %s", err.Error())
-				log.Fatalf(err.Error())
+The supported types are: ` + strings.Join(packTypeNames(), ", ") + `.
+Several comma-separated types can be passed to build the packages with the same content,
+for example: tt pack tgz,rpm
+
+The applications having a rockspec are rebuilt in the bundle. Use --no-rebuild to pack
+the applications built by tt build as is.
+
+Use tt pack verify FILE to check and describe an existing package.
+
+Exit codes: 1 - pack failure, 2 - invalid options or environment configuration,
+3 - unsupported package type, 4 - missing tarantool or tt binary, 5 - package write
+error, for example, not enough disk space.`,
+		ValidArgs: packTypeNames(),
+		ValidArgsFunction: func(
+			cmd *cobra.Command,
+			args []string,
+			toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
 			}
-			err = cobra.OnlyValidArgs(cmd, args)
+			return completeCommaList(toComplete, packTypeNames()),
+				cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			err := checkPackArgs(cmd, args)
 			if err != nil {
-				err = fmt.Errorf("incorrect combination of command parameters: %s", err.Error())
-				log.Fatalf(err.Error())
+				err = &packConfigError{err}
+			} else {
+				cmdCtx.CommandName = cmd.Name()
+				err = modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+					internalPackModule, args)
 			}
-			if packCtx.CartridgeCompat && args[0] != "tgz" {
-				err = fmt.Errorf("cartridge-compat flag can only be used while packing tgz bundle")
-				log.Fatalf(err.Error())
-			}
-			if packCtx.TarantoolVersion != "" && !packCtx.UseDocker {
-				err = fmt.Errorf("tarantool-version argument can only be " +
-					"used while packing in docker")
-				log.Fatalf(err.Error())
-			}
-			cmdCtx.CommandName = cmd.Name()
-			err = modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo, internalPackModule, args)
-			util.HandleCmdErr(cmd, err)
+			handlePackErr(cmd, err)
 		},
 	}
 
@@ -54,28 +102,188 @@ The supported types are: tgz, deb, rpm`,
 		"Package name")
 	packCmd.Flags().StringVar(&packCtx.Version, "version", packCtx.Version,
 		"Package version")
+	packCmd.Flags().BoolVar(&packCtx.VersionFromGit, "version-from-git", packCtx.VersionFromGit,
+		"Get package version from the latest git tag")
+	packCmd.Flags().BoolVar(&packCtx.AllowDirty, "allow-dirty", packCtx.AllowDirty,
+		"Don't add +dirty suffix to the version got from git if there are uncommitted changes")
+	packCmd.MarkFlagsMutuallyExclusive("version", "version-from-git")
 	packCmd.Flags().StringSliceVar(&packCtx.AppList, "app-list", packCtx.AppList,
 		"List of applications for packaging")
+	packCmd.Flags().BoolVar(&packCtx.AllowDuplicateApps, "allow-duplicate-apps",
+		packCtx.AllowDuplicateApps, "Don't fail if the applications from different sources"+
+			" have the same name, one of them overwrites another in the bundle")
 	packCmd.Flags().StringVar(&packCtx.FileName, "filename", packCtx.FileName,
-		"Explicitly set filename of the bundle")
+		"Explicitly set filename of the bundle. The {name}, {version}, {type}, {arch} and"+
+			" {date} placeholders are substituted")
+	packCmd.Flags().StringVar(&packCtx.OutputDir, "output-dir", packCtx.OutputDir,
+		"Directory to write the result package to. It is created if it does not exist")
 	packCmd.Flags().BoolVar(&packCtx.WithoutBinaries, "without-binaries",
 		packCtx.WithoutBinaries, "Don't include tarantool and tt binaries to the result package")
-	packCmd.Flags().BoolVar(&packCtx.WithBinaries, "with-binaries", packCtx.WithoutBinaries,
+	packCmd.Flags().BoolVar(&packCtx.WithBinaries, "with-binaries", packCtx.WithBinaries,
 		"Include tarantool and tt binaries to the result package")
+	packCmd.Flags().BoolVar(&packCtx.StripDebug, "strip-debug", packCtx.StripDebug,
+		"Strip debug symbols from tarantool and tt binaries included to the result package")
+	packCmd.Flags().StringVar(&packCtx.BinariesLock, "binaries-lock", packCtx.BinariesLock,
+		"File with expected SHA256 checksums of tarantool and tt binaries in sha256sum "+
+			"format. Packing fails if the included binaries do not match")
 	packCmd.Flags().BoolVar(&packCtx.CartridgeCompat, "cartridge-compat", false,
 		"Pack cartridge cli compatible archive (only for tgz type)")
 	packCmd.Flags().BoolVar(&packCtx.WithoutModules, "without-modules",
 		packCtx.WithoutModules, "Don't include external modules to the result package")
+	packCmd.Flags().BoolVar(&packCtx.WithoutRocks, "without-rocks",
+		packCtx.WithoutRocks, "Don't include the applications .rocks directories to the result"+
+			" package and don't build the rocks. Rocks are included by default")
+	packCmd.Flags().BoolVar(&packCtx.NoRebuild, "no-rebuild", packCtx.NoRebuild,
+		"Pack the applications as is without building the rocks. By default the applications"+
+			" having a rockspec are rebuilt in the bundle")
+	packCmd.Flags().StringVar(&packCtx.ConfigOverlay, "config-overlay", packCtx.ConfigOverlay,
+		"Config file to write to the packed application cluster config")
+	packCmd.Flags().StringVar(&packCtx.ConfigOverlayMode, "config-overlay-mode",
+		packCtx.ConfigOverlayMode, "How to apply the config overlay: replace the application"+
+			" config or merge the overlay into it (default replace)")
+	packCmd.Flags().StringVar(&packCtx.Since, "since", packCtx.Since,
+		"Git ref to pack only the application files changed since it, for hotfix bundles")
+	packCmd.Flags().BoolVar(&packCtx.AllowEmpty, "allow-empty", packCtx.AllowEmpty,
+		"Do not fail if there are no applications to pack or the include patterns leave "+
+			"the applications without files")
+	packCmd.Flags().BoolVar(&packCtx.NormalizePermissions, "normalize-permissions",
+		packCtx.NormalizePermissions, "Set 0755 mode for the directories and the executables"+
+			" detected by a shebang or an ELF header and 0644 mode for other files of the package")
+	packCmd.Flags().BoolVar(&packCtx.PruneEmptyDirs, "prune-empty-dirs", packCtx.PruneEmptyDirs,
+		"Remove the directories left without files after the excludes, .packignore and the"+
+			" skipped runtime artifacts are applied")
+	packCmd.Flags().BoolVar(&packCtx.FollowConfig, "follow-config", packCtx.FollowConfig,
+		"Pack the applications as they are configured for running: the instance scripts of"+
+			" the multi-instance applications not defined by their instances.yml or the app.file"+
+			" option of the cluster config are skipped")
+	packCmd.Flags().BoolVar(&packCtx.IncludeRuntimeDirs, "include-runtime-dirs",
+		packCtx.IncludeRuntimeDirs, "Include the log, run and data directories found in the "+
+			"applications sources to the result package. They are skipped by default")
+	packCmd.Flags().BoolVar(&packCtx.WithChecksum, "with-checksum", packCtx.WithChecksum,
+		"Write SHA256 checksum file next to the result package")
+	packCmd.Flags().StringVar(&packCtx.SourceDir, "source-dir", packCtx.SourceDir,
+		"Prebuilt bundle directory to pack as is, applications discovery is skipped")
+	packCmd.Flags().StringVar(&packCtx.GitURL, "git-url", packCtx.GitURL,
+		"URL of the git repository to clone shallowly and pack instead of the current"+
+			" environment. The git credential helpers are used for authentication")
+	packCmd.Flags().StringVar(&packCtx.GitRef, "git-ref", packCtx.GitRef,
+		"Branch, tag or commit hash of the --git-url repository to pack (default branch"+
+			" of the repository)")
+	packCmd.Flags().BoolVar(&packCtx.ConfigFree, "config-free", packCtx.ConfigFree,
+		"Do not use tt environment configuration, pack the --source-dir bundle using flags only")
+	packCmd.Flags().BoolVar(&packCtx.AllowExternalSymlinks, "allow-external-symlinks",
+		packCtx.AllowExternalSymlinks,
+		"Allow packing of symlinks pointing outside of the application directory")
+	packCmd.Flags().StringVar(&packCtx.TargetArch, "target-arch", packCtx.TargetArch,
+		"Architecture the packed binaries must be built for (default host architecture)")
+	packCmd.Flags().StringVar(&packCtx.PostPackHook, "post-pack-hook", packCtx.PostPackHook,
+		"Executable to run after the package is created. The package path is passed"+
+			" as the first argument")
+	packCmd.Flags().StringVar(&packCtx.Destination, "destination", packCtx.Destination,
+		"Remote location to upload the result package to: scp://[user@]host[:port][/path]."+
+			" The local package is kept")
+	packCmd.Flags().StringVar(&packCtx.Identity, "identity", packCtx.Identity,
+		"Private key file to authenticate on the destination host (default ssh agent)")
+	packCmd.Flags().StringArrayVar(&packCtx.Exclude, "exclude", packCtx.Exclude,
+		"Pattern of bundle-relative paths of application files to skip while packing"+
+			" (gitignore syntax), e.g. app/tmp/** or *.log. Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.Include, "include", packCtx.Include,
+		"Glob of bundle-relative paths to pack, other paths are skipped. Excluded files are"+
+			" not packed even if they match. Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.Labels, "label", packCtx.Labels,
+		"Metadata as key=value to write into the bundle manifest and the Deb control file."+
+			" Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.AppVersions, "app-version", packCtx.AppVersions,
+		"Version of the packed application as app=version to write into the bundle manifest."+
+			" The package version is used for other applications. Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.ExtraFiles, "extra-file", packCtx.ExtraFiles,
+		"File to copy into the bundle as src:dst, dst is relative to the bundle root. Can be"+
+			" specified multiple times")
+	packCmd.Flags().BoolVar(&packCtx.Force, "force", packCtx.Force,
+		"Allow the extra files to overwrite the existing bundle files")
+	packCmd.Flags().StringVar(&packCtx.ArtifactMode, "artifact-mode", packCtx.ArtifactMode,
+		"Octal permission bits of the result package file, e.g. 0664 (default is set by"+
+			" umask)")
+	packCmd.Flags().StringVar(&packCtx.WarnSize, "warn-size", packCtx.WarnSize,
+		"Log a warning if the result package file exceeds the size, e.g. 500MB or 1GiB")
+	packCmd.Flags().StringVar(&packCtx.MaxSize, "max-size", packCtx.MaxSize,
+		"Fail if the result package file exceeds the size, e.g. 500MB or 1GiB."+
+			" The package is not written. The volumes of the split tarball are checked")
+	packCmd.Flags().IntVar(&packCtx.Jobs, "jobs", runtime.NumCPU(),
+		"Number of workers collecting the files to pack (0 means the number of CPUs)")
+	packCmd.Flags().IntVar(&packCtx.IORetries, "io-retries", pack.DefaultIORetries,
+		"Number of retries of the binaries copying failed with a transient I/O error")
+	packCmd.Flags().StringVar(&packCtx.TmpDir, "tmp-dir", packCtx.TmpDir,
+		"Directory for the intermediate files of packing (default TMPDIR or /tmp). "+
+			"The temporary package file is written to it if it is on the output directory "+
+			"file system")
+	packCmd.Flags().StringVar(&packCtx.CacheDir, "cache-dir", packCtx.CacheDir,
+		"Directory to keep the applications sources between packs. Only the changed "+
+			"application files are copied on the next pack")
+	packCmd.Flags().StringVar(&packCtx.OutputFormat, "output", pack.OutputText,
+		"Output format of the pack result: text or json. In json mode the result is printed "+
+			"to stdout as JSON object with an array of the created packages, logs are printed "+
+			"to stderr")
+	packCmd.Flags().BoolVar(&packCtx.Quiet, "quiet", packCtx.Quiet,
+		"Report the ignored flags at debug level instead of warnings")
+	packCmd.Flags().BoolVar(&packCtx.DryRun, "dry-run", packCtx.DryRun,
+		"Print the list of files to be packed with their sizes without creating a package")
+	packCmd.Flags().BoolVar(&packCtx.ListApps, "list-apps", packCtx.ListApps,
+		"Print the applications to be packed with their source paths without creating"+
+			" a package")
+	packCmd.Flags().BoolVar(&packCtx.PrintSize, "print-size", packCtx.PrintSize,
+		"Print the uncompressed and compressed sizes of the package content without"+
+			" creating a package. The payload size is printed for deb and rpm packages")
+	packCmd.MarkFlagsMutuallyExclusive("dry-run", "list-apps", "print-size")
 
 	// TarGZ flags.
 	packCmd.Flags().BoolVar(&packCtx.Archive.All, "all", packCtx.Archive.All,
 		"Pack all included artifacts")
+	packCmd.Flags().IntVar(&packCtx.Archive.CompressionLevel, "compression-level",
+		pack.DefaultCompressionLevel,
+		"Compression level: from 0 (no compression) to 9 (best compression) for gzip, "+
+			"from 1 to 22 for zstd. Only for tgz packing.")
+	packCmd.Flags().StringVar(&packCtx.Archive.Compressor, "compressor", pack.CompressorGzip,
+		"Tarball compressor: gzip or zstd. Only for tgz packing.")
+	packCmd.Flags().BoolVar(&packCtx.Archive.PreserveSymlinks, "preserve-symlinks",
+		packCtx.Archive.PreserveSymlinks,
+		"Keep application symlinks as links instead of copying the target contents. "+
+			"Symlinks pointing outside of the application directory are still dereferenced. "+
+			"Only for tgz and zip packing.")
+	packCmd.Flags().StringVar(&packCtx.Archive.BundleRoot, "bundle-root",
+		packCtx.Archive.BundleRoot, "Top-level directory name of the tgz and zip bundle content"+
+			" (default the content is placed into the archive root)")
+	packCmd.Flags().BoolVar(&packCtx.Archive.Dedup, "dedup", packCtx.Archive.Dedup,
+		"Pack the byte-identical duplicate files of tgz as hardlinks")
+	packCmd.Flags().StringVar(&packCtx.Archive.DedupMinSize, "dedup-min-size",
+		packCtx.Archive.DedupMinSize, "Size of the smallest file to deduplicate, for example: "+
+			"16KiB (default 4KiB)")
+	packCmd.Flags().StringArrayVar(&packCtx.Archive.StoreGlobs, "store-glob",
+		packCtx.Archive.StoreGlobs, "Glob of the zip entry paths to store without compression,"+
+			" for example, already compressed datasets. It saves CPU, but not space. Tarballs"+
+			" are compressed as a whole and cannot store files uncompressed. Can be specified"+
+			" multiple times")
+	packCmd.Flags().StringVar(&packCtx.Archive.SplitSize, "split-size",
+		packCtx.Archive.SplitSize, "Split the tgz package into volumes of at most the size,"+
+			" for example: 2GB. The volumes are written with an index and are joined by"+
+			" concatenation or with tt pack join")
+	packCmd.Flags().StringVar(&packCtx.Archive.BaseTgz, "base-tgz", packCtx.Archive.BaseTgz,
+		"Existing tarball to layer the package onto. The package files override the"+
+			" conflicting files of the base tarball. Only for tgz packing.")
+	packCmd.Flags().StringVar(&packCtx.Archive.DeltaAgainst, "delta-against",
+		packCtx.Archive.DeltaAgainst, "Previous tarball to create the delta against. The delta"+
+			" with the changed files is written next to the tarball with .delta suffix."+
+			" Only for tgz packing.")
 
 	// RPMDeb flags.
 	packCmd.Flags().StringVar(&packCtx.RpmDeb.PreInst, "preinst", packCtx.RpmDeb.PreInst,
 		"preinst file path. Only for for RPM and Deb packing.")
 	packCmd.Flags().StringVar(&packCtx.RpmDeb.PostInst, "postinst", packCtx.RpmDeb.PostInst,
 		"postinst file path. Only for for RPM and Deb packing.")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.PreRm, "prerm", packCtx.RpmDeb.PreRm,
+		"prerm file path. Only for RPM and Deb packing.")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.PostRm, "postrm", packCtx.RpmDeb.PostRm,
+		"postrm file path. Only for RPM and Deb packing.")
 	packCmd.Flags().StringVar(&packCtx.RpmDeb.DepsFile, "deps-file", packCtx.RpmDeb.DepsFile,
 		"Path to the file that contains dependencies for the RPM and DEB packages")
 	packCmd.Flags().BoolVar(&packCtx.RpmDeb.WithTarantoolDeps, "with-tarantool-deps",
@@ -83,73 +291,879 @@ The supported types are: tgz, deb, rpm`,
 		"Add tarantool and tt as dependencies to the result package")
 	packCmd.Flags().StringSliceVar(&packCtx.RpmDeb.Deps, "deps", packCtx.RpmDeb.Deps,
 		"Dependencies for the RPM and DEB packages")
+	packCmd.Flags().UintVar(&packCtx.RpmDeb.RpmEpoch, "rpm-epoch", packCtx.RpmDeb.RpmEpoch,
+		"Epoch of the RPM package")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.RpmRelease, "rpm-release",
+		packCtx.RpmDeb.RpmRelease, "Release of the RPM package (default 1)")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.RpmScriptletInterpreter,
+		"rpm-scriptlet-interpreter", packCtx.RpmDeb.RpmScriptletInterpreter,
+		"Interpreter of the RPM install and remove scripts: /bin/sh, /bin/bash or lua for "+
+			"the rpm embedded Lua (default /bin/sh)")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.DebArch, "deb-arch", packCtx.RpmDeb.DebArch,
+		"Architecture of the Deb package, e.g. arm64 or all (default host architecture)."+
+			" Comma-separated list builds a package for each architecture")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.RpmArch, "rpm-arch", packCtx.RpmDeb.RpmArch,
+		"Architecture of the RPM package, e.g. aarch64 or noarch (default host architecture)."+
+			" Comma-separated list builds a package for each architecture")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.Changelog, "changelog", packCtx.RpmDeb.Changelog,
+		"Path to the changelog file in RPM or debian changelog format depending on"+
+			" the package type")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.Maintainer, "maintainer",
+		packCtx.RpmDeb.Maintainer, "Maintainer of the RPM and DEB packages in \"Name <email>\""+
+			" format (default \"Tarantool developer\")")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.Homepage, "homepage", packCtx.RpmDeb.Homepage,
+		"Home page URL of the RPM and DEB packages")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.License, "license", packCtx.RpmDeb.License,
+		"License of the RPM and DEB packages (default N/A for RPM)")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.Description, "description",
+		packCtx.RpmDeb.Description, "Description of the RPM and DEB packages, the first"+
+			" line is a summary (default \"Tarantool environment: <name>\")")
+	packCmd.Flags().StringArrayVar(&packCtx.RpmDeb.Conflicts, "conflicts",
+		packCtx.RpmDeb.Conflicts, "Packages conflicting with the RPM and DEB packages."+
+			" Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.RpmDeb.Provides, "provides",
+		packCtx.RpmDeb.Provides, "Virtual packages provided by the RPM and DEB packages."+
+			" Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.RpmDeb.FileModes, "file-mode",
+		packCtx.RpmDeb.FileModes, "Mode and ownership of the RPM and DEB package files in"+
+			" <glob>=<mode>:<owner>:<group> format, the glob is matched against the installed"+
+			" path. Empty mode, owner or group keeps the default. Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.RpmDeb.RpmConfigFiles, "rpm-config-file",
+		packCtx.RpmDeb.RpmConfigFiles, "Glob of the RPM package files marked as"+
+			" %config(noreplace), the glob is matched against the installed path."+
+			" Can be specified multiple times")
+	packCmd.Flags().StringArrayVar(&packCtx.RpmDeb.DebConffiles, "deb-conffile",
+		packCtx.RpmDeb.DebConffiles, "Glob of the Deb package files listed in conffiles,"+
+			" the glob is matched against the installed path. Can be specified multiple times")
+	packCmd.Flags().BoolVar(&packCtx.RpmDeb.CreateRuntimeDirs, "create-runtime-dirs",
+		packCtx.RpmDeb.CreateRuntimeDirs, "Add the data, log and run directories of the"+
+			" environment and its applications owned by tarantool user to the RPM and DEB packages")
+	packCmd.Flags().DurationVar(&packCtx.Timeout, "timeout", packCtx.Timeout,
+		"Maximum duration of the pack operation, e.g. 10m. The operation is not limited"+
+			" if it is not set")
+	packCmd.Flags().BoolVar(&packCtx.Sbom, "sbom", packCtx.Sbom,
+		"Write CycloneDX JSON SBOM of the bundled applications, rocks and binaries to the"+
+			" bundle root as sbom.cdx.json")
+	packCmd.Flags().BoolVar(&packCtx.SbomArtifact, "sbom-artifact", packCtx.SbomArtifact,
+		"Also write the SBOM next to the package with .cdx.json suffix")
+	packCmd.Flags().BoolVar(&packCtx.NoLock, "no-lock", packCtx.NoLock,
+		"Do not lock the package path while the package is written. By default the pack"+
+			" operations writing the same package wait for each other")
+	packCmd.Flags().DurationVar(&packCtx.LockTimeout, "lock-timeout", pack.DefaultLockTimeout,
+		"Time to wait for the package being written by another pack operation. Zero means"+
+			" to fail immediately")
 	packCmd.Flags().BoolVar(&packCtx.UseDocker, "use-docker",
 		packCtx.UseDocker,
 		"Use docker for building a package.")
 	packCmd.Flags().StringVar(&packCtx.TarantoolVersion, "tarantool-version",
 		packCtx.TarantoolVersion,
-		"Version of the tarantool for pack in docker (only with --use-docker flag).")
+		"Version of the tarantool to include into the package. The version must be installed"+
+			" in the environment with tt install unless --use-docker flag is set. It overrides"+
+			" the version pinned in the applications .tarantool.yml files.")
+	packCmd.Flags().StringVar(&packCtx.TarantoolBinary, "tarantool-binary",
+		packCtx.TarantoolBinary, "Path to the tarantool executable to include into the package"+
+			" instead of the environment one. Version resolution is skipped")
+	packCmd.Flags().StringVar(&packCtx.TtBinary, "tt-binary", packCtx.TtBinary,
+		"Path to the tt executable to include into the package instead of the running one")
 	packCmd.Flags().StringVar(&packCtx.RpmDeb.SystemdUnitParamsFile, "unit-params-file",
 		packCtx.RpmDeb.SystemdUnitParamsFile,
 		"Path to the file that contains systemd unit params")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.SystemdUnitTemplateFile, "systemd-unit-template",
+		packCtx.RpmDeb.SystemdUnitTemplateFile,
+		"Path to the text/template file of systemd unit to use instead of the built-in one")
+	packCmd.Flags().StringVar(&packCtx.AppListFile, "app-list-file", packCtx.AppListFile,
+		"File with applications for packaging, one name per line. Lines starting with #"+
+			" are comments")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.InstallPrefix, "install-prefix",
+		packCtx.RpmDeb.InstallPrefix,
+		"Path where the environment is installed by RPM or DEB package"+
+			" (default /usr/share/tarantool)")
+	packCmd.Flags().StringVar(&packCtx.RpmDeb.SignKey, "sign-key", packCtx.RpmDeb.SignKey,
+		"GPG key id or path to the key file to sign RPM or DEB package with")
+
+	// Docker image flags.
+	packCmd.Flags().StringVar(&packCtx.Image.From, "from", pack.DefaultImageBase,
+		"Base image for docker image packing")
+	packCmd.Flags().StringVar(&packCtx.Image.Tag, "tag", packCtx.Image.Tag,
+		"Result docker image name (default <name>:<version>)")
 
 	// Integrity flags.
 	integrity.RegisterWithIntegrityFlag(packCmd.Flags(), &packCtx.IntegrityPrivateKey)
+	// The integrity library may register the flag signing the hashes with a private key.
+	if packCmd.Flags().Lookup("with-integrity-check") == nil {
+		packCmd.Flags().BoolVar(&packCtx.WithIntegrityIndex, "with-integrity-check",
+			packCtx.WithIntegrityIndex, "Write "+pack.IntegrityIndexFileName+" file with"+
+				" sha256 checksums of the bundle files into the bundle root. The bundle is"+
+				" verified with tt pack verify DIR or sha256sum -c")
+	}
+
+	// The flag is registered above, so the registration cannot fail.
+	_ = packCmd.RegisterFlagCompletionFunc("app-list", completeAppList)
 
+	packCmd.AddCommand(newPackVerifyCmd())
+	packCmd.AddCommand(newPackApplyDeltaCmd())
+	packCmd.AddCommand(newPackJoinCmd())
 	return packCmd
 }
 
-// internalPackModule is a default pack module.
-func internalPackModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
-	if !isConfigExist(cmdCtx) {
-		return errNoConfig
+// verifyOutputFormat is an output format of the pack verify command.
+var verifyOutputFormat string
+
+// newPackVerifyCmd creates a command to verify the existing package.
+func newPackVerifyCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify FILE|DIR",
+		Short: "Check the package integrity and show its content",
+		Long: `Check the package integrity and show its content
+
+The whole tgz, zip, deb or rpm package is read to detect corruption. The applications,
+versions and bundled binaries are taken from the package manifest or inferred from
+the package structure if there is no manifest.
+
+If DIR is passed, it is an unpacked or installed bundle packed with --with-integrity-check.
+Its files are checked against the checksums of the .integrity file in DIR, the modified
+and missing files are listed.
+
+With --output json every package entry is listed with its size, mode and sha256 checksum,
+the payload entries are listed for deb and rpm. The listing is written while the package
+is read.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalPackVerifyModule, args)
+			util.HandleCmdErr(cmd, err)
+		},
 	}
+	verifyCmd.Flags().StringVar(&verifyOutputFormat, "output", pack.OutputText,
+		"Output format: text or json. In json mode the package description and the list of "+
+			"all package entries are printed")
+	return verifyCmd
+}
 
-	err := pack.FillCtx(cmdCtx, packCtx, cliOpts, args)
+// internalPackVerifyModule is a default pack verify module.
+func internalPackVerifyModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.IsDir(args[0]) {
+		if verifyOutputFormat != pack.OutputText {
+			return fmt.Errorf("--output flag is not supported for the bundle directory")
+		}
+		return pack.VerifyIntegrity(args[0], os.Stdout)
+	}
+	switch verifyOutputFormat {
+	case pack.OutputText:
+	case pack.OutputJSON:
+		return pack.WritePackageInventory(args[0], os.Stdout)
+	default:
+		return fmt.Errorf("invalid output format %q: must be %s or %s",
+			verifyOutputFormat, pack.OutputText, pack.OutputJSON)
+	}
+	info, err := pack.VerifyPackage(args[0])
 	if err != nil {
 		return err
 	}
+	pack.PrintPackageInfo(info, os.Stdout)
+	return nil
+}
 
-	if err := checkFlags(packCtx); err != nil {
+// newPackApplyDeltaCmd creates a command to reconstruct the tarball from the delta.
+func newPackApplyDeltaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply-delta BASE DELTA [OUTPUT]",
+		Short: "Reconstruct the tarball from the previous tarball and the delta",
+		Long: `Reconstruct the tarball from the previous tarball and the delta
+
+The delta is created by tt pack tgz with --delta-against option. The files not changed
+since the previous tarball are taken from it, the checksums of the taken files are checked.
+The tarball is written to OUTPUT or next to the delta with the original tarball name.`,
+		Args: cobra.RangeArgs(2, 3),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalPackApplyDeltaModule, args)
+			util.HandleCmdErr(cmd, err)
+		},
+	}
+}
+
+// internalPackApplyDeltaModule is a default pack apply-delta module.
+func internalPackApplyDeltaModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	outputPath := ""
+	if len(args) > 2 {
+		outputPath = args[2]
+	}
+	tarballPath, err := pack.ApplyDelta(args[0], args[1], outputPath)
+	if err != nil {
 		return err
 	}
+	log.Infof("Tarball is reconstructed to %s.", tarballPath)
+	return nil
+}
 
-	if packCtx.UseDocker {
-		return pack.PackInDocker(cmdCtx, packCtx, *cliOpts, os.Args)
+// newPackJoinCmd creates a command to reassemble the split tarball.
+func newPackJoinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "join INDEX [OUTPUT]",
+		Short: "Reassemble the tarball split into volumes",
+		Long: `Reassemble the tarball split into volumes
+
+The volumes are created by tt pack tgz with --split-size option. They are looked up next
+to the INDEX file, their sizes and checksums are checked. The tarball is the concatenation
+of the volumes, it is written to OUTPUT or next to the index with the original name.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalPackJoinModule, args)
+			util.HandleCmdErr(cmd, err)
+		},
 	}
+}
+
+// internalPackJoinModule is a default pack join module.
+func internalPackJoinModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	outputPath := ""
+	if len(args) > 1 {
+		outputPath = args[1]
+	}
+	tarballPath, err := pack.JoinVolumes(args[0], outputPath)
+	if err != nil {
+		return err
+	}
+	log.Infof("Tarball is joined to %s.", tarballPath)
+	return nil
+}
 
-	packer := pack.CreatePacker(packCtx)
-	if packer == nil {
-		return fmt.Errorf("incorrect type of package. Available types: rpm, deb, tgz")
+// completeAppList completes the application names for --app-list flag.
+func completeAppList(cmd *cobra.Command, args []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	if cliOpts == nil || cliOpts.Env == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	appList, err := pack.DiscoverApps(&cmdCtx, cliOpts)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeCommaList(toComplete, appList), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCommaList completes the last element of the comma-separated list. The choices
+// already present in the list are not suggested.
+func completeCommaList(toComplete string, choices []string) []string {
+	prefix := ""
+	if pos := strings.LastIndex(toComplete, ","); pos >= 0 {
+		prefix = toComplete[:pos+1]
+	}
+	passed := strings.Split(prefix, ",")
+	completions := []string{}
+	for _, choice := range choices {
+		if strings.HasPrefix(prefix+choice, toComplete) && !slices.Contains(passed, choice) {
+			completions = append(completions, prefix+choice)
+		}
+	}
+	return completions
+}
+
+// checkPackArgs checks the command arguments and the flags depending on them.
+func checkPackArgs(cmd *cobra.Command, args []string) error {
+	err := cobra.ExactArgs(1)(cmd, args)
+	if err != nil {
+		return fmt.Errorf("incorrect combination of command parameters: %s", err.Error())
+	}
+	err = checkPackTypes(cmd, args[0])
+	if err != nil {
+		return fmt.Errorf("incorrect combination of command parameters: %w", err)
+	}
+	if packCtx.CartridgeCompat && args[0] != pack.Tgz {
+		return fmt.Errorf("cartridge-compat flag can only be used while packing tgz bundle")
+	}
+	if packCtx.GitURL != "" && packCtx.UseDocker {
+		return fmt.Errorf("--git-url flag cannot be used with --use-docker flag")
+	}
+	if packCtx.GitURL != "" && packCtx.SourceDir != "" {
+		return fmt.Errorf("--git-url flag cannot be used with --source-dir flag")
+	}
+	if packCtx.GitURL != "" && packCtx.ConfigFree {
+		return fmt.Errorf("--git-url flag cannot be used with --config-free flag")
+	}
+	if packCtx.OutputFormat != pack.OutputText && packCtx.OutputFormat != pack.OutputJSON {
+		return fmt.Errorf("invalid output format %q: must be %s or %s",
+			packCtx.OutputFormat, pack.OutputText, pack.OutputJSON)
+	}
+	return nil
+}
+
+// isWriteError returns true if the error is a failure of writing or renaming a file.
+func isWriteError(err error) bool {
+	var pathErr *fs.PathError
+	var linkErr *os.LinkError
+	return (errors.As(err, &pathErr) && pathErr.Op == "write") ||
+		(errors.As(err, &linkErr) && linkErr.Op == "rename")
+}
+
+// getPackExitCode returns the exit code of the pack command failed with the error.
+func getPackExitCode(err error) int {
+	var configErr *packConfigError
+	switch {
+	case errors.Is(err, errUnknownPackType) || errors.Is(err, pack.ErrUnsupportedType):
+		return packExitUnsupportedType
+	case errors.Is(err, pack.ErrBinaryNotFound):
+		return packExitBinaryNotFound
+	case errors.As(err, &configErr):
+		return packExitConfig
+	case errors.Is(err, pack.ErrNoSpace) || isWriteError(err):
+		return packExitIO
+	}
+	return packExitFailure
+}
+
+// handlePackErr reports the pack command error and exits with the exit code of the error
+// cause. The error is printed as JSON object to stdout in JSON output mode.
+func handlePackErr(cmd *cobra.Command, err error) {
+	if err == nil {
+		return
+	}
+	exitCode := getPackExitCode(err)
+	if packCtx.OutputFormat == pack.OutputJSON {
+		printPackJSON(struct {
+			Error string `json:"error"`
+		}{err.Error()})
+		os.Exit(exitCode)
+	}
+	if exitCode == packExitFailure {
+		util.HandleCmdErr(cmd, err)
+		return
+	}
+	log.Error(err.Error())
+	os.Exit(exitCode)
+}
+
+// printPackJSON prints the value as JSON object to stdout.
+func printPackJSON(value interface{}) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		log.Fatalf("failed to encode JSON output: %s", err)
+	}
+	fmt.Println(string(content))
+}
+
+// internalPackModule is a default pack module.
+func internalPackModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	cliOpts := cliOpts
+	if packCtx.ConfigFree {
+		var err error
+		if cliOpts, err = pack.ConfigFreeOpts(cmdCtx, packCtx); err != nil {
+			return &packConfigError{err}
+		}
+	}
+	if packCtx.GitURL != "" {
+		cloneDir, removeClone, err := pack.CloneGitSource(packCtx)
+		if err != nil {
+			return err
+		}
+		defer removeClone()
+		if cliOpts, err = pack.GitSourceOpts(cmdCtx, cloneDir); err != nil {
+			return &packConfigError{err}
+		}
+	}
+	// Prebuilt bundle is packed as is, tt environment configuration is not required.
+	// The cloned repository is a single application if it has no configuration.
+	if packCtx.SourceDir == "" && packCtx.GitURL == "" && !isConfigExist(cmdCtx) {
+		return &packConfigError{errNoConfig}
+	}
+
+	packCtx.ProgressReporter = pack.NopProgressReporter{}
+	if isatty.IsTerminal(os.Stderr.Fd()) {
+		packCtx.ProgressReporter = pack.NewTerminalProgressReporter(os.Stderr)
+	}
+
+	// Each package type gets its own context filled from the same flags.
+	packTypes := strings.Split(args[0], ",")
+	typeCtxs := make([]*pack.PackCtx, 0, len(packTypes))
+	for i, packType := range packTypes {
+		// A package is built for each of RPM and Deb architectures.
+		archCtxs, err := pack.ExpandPackageArches(packCtx, packType, cliOpts)
+		if err != nil {
+			return &packConfigError{err}
+		}
+		otherTypes := append(append([]string{}, packTypes[:i]...), packTypes[i+1:]...)
+		for j := range archCtxs {
+			typeCtx := &archCtxs[j]
+			if err := pack.FillCtx(cmdCtx, typeCtx, cliOpts, []string{packType}); err != nil {
+				return &packConfigError{err}
+			}
+			if err := checkFlags(typeCtx, otherTypes...); err != nil {
+				return &packConfigError{err}
+			}
+			typeCtxs = append(typeCtxs, typeCtx)
+		}
+	}
+
+	if packCtx.ListApps {
+		// Applications discovery does not depend on the package type.
+		return pack.ListApps(typeCtxs[0], os.Stdout)
+	}
+
+	if packCtx.DryRun {
+		// The packages share the same content, so it is listed once.
+		return pack.DryRun(cmdCtx, typeCtxs[0], cliOpts, os.Stdout)
+	}
+
+	if packCtx.PrintSize {
+		// The packages share the same content, so it is prepared once.
+		return pack.PrintSize(cmdCtx, typeCtxs, cliOpts, os.Stdout)
+	}
+
+	if packCtx.UseDocker {
+		// All requested types are built by tt running in the container.
+		return pack.PackInDocker(cmdCtx, typeCtxs[0], *cliOpts, os.Args)
 	}
 
-	err = packer.Run(cmdCtx, packCtx, cliOpts)
+	if len(typeCtxs) > 1 {
+		defer pack.ShareBundleContent(typeCtxs...)()
+	}
+	results := make([]pack.PackResult, 0, len(typeCtxs))
+	err := pack.RunPackOperation(packCtx.Timeout, typeCtxs, func() error {
+		for _, typeCtx := range typeCtxs {
+			if _, err := pack.Pack(cmdCtx, typeCtx, cliOpts); err != nil {
+				return fmt.Errorf("failed to pack: %w", err)
+			}
+			if err := pack.UploadPackage(typeCtx); err != nil {
+				return err
+			}
+			if err := pack.RunPostPackHook(typeCtx, cliOpts); err != nil {
+				return err
+			}
+			if packCtx.OutputFormat == pack.OutputJSON {
+				result, err := pack.GetPackResult(typeCtx, cliOpts)
+				if err != nil {
+					return err
+				}
+				results = append(results, result)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to pack: %v", err)
+		return err
+	}
+
+	// A single JSON object is printed for all packages.
+	if packCtx.OutputFormat == pack.OutputJSON {
+		printPackJSON(pack.PackResults{Packages: results})
+	}
+	return nil
+}
+
+// checkPackTypes checks the comma-separated package types argument.
+func checkPackTypes(cmd *cobra.Command, typesArg string) error {
+	packTypes := strings.Split(typesArg, ",")
+	for i, packType := range packTypes {
+		if !slices.Contains(cmd.ValidArgs, packType) {
+			return fmt.Errorf("%w %q for %q, supported types: %s", errUnknownPackType,
+				packType, cmd.CommandPath(), strings.Join(cmd.ValidArgs, ", "))
+		}
+		if slices.Contains(packTypes[:i], packType) {
+			return fmt.Errorf("package type %q is passed several times", packType)
+		}
 	}
 	return nil
 }
 
-func checkFlags(packCtx *pack.PackCtx) error {
+// packTypeNames returns the names of the supported package types.
+func packTypeNames() []string {
+	supportedTypes := pack.SupportedTypes()
+	names := make([]string, 0, len(supportedTypes))
+	for _, packType := range supportedTypes {
+		names = append(names, packType.String())
+	}
+	return names
+}
+
+// packsAnyOf checks if any of the package types is built.
+func packsAnyOf(packTypes []string, types ...string) bool {
+	for _, packType := range packTypes {
+		if slices.Contains(types, packType) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFlags checks the flags for the package type. otherTypes are the other package types
+// built in the same invocation: the flags used by them are not reported as ignored.
+func checkFlags(packCtx *pack.PackCtx, otherTypes ...string) error {
 	switch pack.PackageType(packCtx.Type) {
-	case pack.Tgz:
-		if len(packCtx.RpmDeb.Deps) > 0 {
-			log.Warnf("You specified the --deps flag," +
-				" but you are not packaging RPM or DEB. Flag will be ignored")
+	case pack.Tgz, pack.Zip, pack.AppImage:
+		if !packsAnyOf(otherTypes, pack.Rpm, pack.Deb) {
+			if len(packCtx.RpmDeb.Deps) > 0 {
+				pack.WarnIgnored(packCtx, "You specified the --deps flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if len(packCtx.RpmDeb.Conflicts) > 0 {
+				pack.WarnIgnored(packCtx, "You specified the --conflicts flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if len(packCtx.RpmDeb.Provides) > 0 {
+				pack.WarnIgnored(packCtx, "You specified the --provides flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.Changelog != "" {
+				pack.WarnIgnored(packCtx, "You specified the --changelog flag,"+
+					" but you are not packaging RPM or DEB. Changelog will be ignored")
+			}
+			if packCtx.RpmDeb.PreInst != "" {
+				pack.WarnIgnored(packCtx, "You specified the --preinst flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.PostInst != "" {
+				pack.WarnIgnored(packCtx, "You specified the --postinst flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.PreRm != "" {
+				pack.WarnIgnored(packCtx, "You specified the --prerm flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.PostRm != "" {
+				pack.WarnIgnored(packCtx, "You specified the --postrm flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.InstallPrefix != "" {
+				pack.WarnIgnored(packCtx, "You specified the --install-prefix flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.SignKey != "" {
+				pack.WarnIgnored(packCtx, "You specified the --sign-key flag,"+
+					" but you are not packaging RPM or DEB. Signing will be ignored")
+			}
+			if len(packCtx.RpmDeb.FileModes) > 0 {
+				pack.WarnIgnored(packCtx, "You specified the --file-mode flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.CreateRuntimeDirs {
+				pack.WarnIgnored(packCtx, "You specified the --create-runtime-dirs flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.Maintainer != "" {
+				pack.WarnIgnored(packCtx, "You specified the --maintainer flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.Homepage != "" {
+				pack.WarnIgnored(packCtx, "You specified the --homepage flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.License != "" {
+				pack.WarnIgnored(packCtx, "You specified the --license flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+			if packCtx.RpmDeb.Description != "" {
+				pack.WarnIgnored(packCtx, "You specified the --description flag,"+
+					" but you are not packaging RPM or DEB. Flag will be ignored")
+			}
+		}
+		if packCtx.Type != pack.Tgz && !packsAnyOf(otherTypes, pack.Tgz) &&
+			packCtx.Archive.CompressionLevel != pack.DefaultCompressionLevel {
+			pack.WarnIgnored(packCtx, "You specified the --compression-level flag,"+
+				" but you are not packaging tgz. Flag will be ignored")
 		}
-		if packCtx.RpmDeb.PreInst != "" {
-			log.Warnf("You specified the --preinst flag," +
-				" but you are not packaging RPM or DEB. Flag will be ignored")
+		if packCtx.Type != pack.Tgz && !packsAnyOf(otherTypes, pack.Tgz) &&
+			packCtx.Archive.Compressor != "" && packCtx.Archive.Compressor != pack.CompressorGzip {
+			pack.WarnIgnored(packCtx, "You specified the --compressor flag,"+
+				" but you are not packaging tgz. Flag will be ignored")
 		}
-		if packCtx.RpmDeb.PostInst != "" {
-			log.Warnf("You specified the --postinst flag," +
-				" but you are not packaging RPM or DEB. Flag will be ignored")
+		if packCtx.Type != pack.Tgz && !packsAnyOf(otherTypes, pack.Tgz) &&
+			packCtx.Archive.BaseTgz != "" {
+			pack.WarnIgnored(packCtx, "You specified the --base-tgz flag,"+
+				" but you are not packaging tgz. Flag will be ignored")
+		}
+		if packCtx.Type != pack.Tgz && !packsAnyOf(otherTypes, pack.Tgz) &&
+			packCtx.Archive.DeltaAgainst != "" {
+			pack.WarnIgnored(packCtx, "You specified the --delta-against flag,"+
+				" but you are not packaging tgz. Flag will be ignored")
 		}
 	case pack.Rpm, pack.Deb:
-		if packCtx.Archive.All == true {
-			log.Warnf("You specified the --all flag," +
+		if packCtx.Archive.All == true && !packsAnyOf(otherTypes, pack.Tgz, pack.Zip) {
+			pack.WarnIgnored(packCtx, "You specified the --all flag,"+
+				" but you are not packaging a tarball. Flag will be ignored")
+		}
+		if packCtx.Archive.CompressionLevel != pack.DefaultCompressionLevel &&
+			!packsAnyOf(otherTypes, pack.Tgz) {
+			pack.WarnIgnored(packCtx, "You specified the --compression-level flag,"+
+				" but you are not packaging a tarball. Flag will be ignored")
+		}
+		if packCtx.Archive.Compressor != "" &&
+			packCtx.Archive.Compressor != pack.CompressorGzip &&
+			!packsAnyOf(otherTypes, pack.Tgz) {
+			pack.WarnIgnored(packCtx, "You specified the --compressor flag,"+
+				" but you are not packaging a tarball. Flag will be ignored")
+		}
+		if packCtx.Archive.PreserveSymlinks && !packsAnyOf(otherTypes, pack.Tgz, pack.Zip) {
+			pack.WarnIgnored(packCtx, "You specified the --preserve-symlinks flag,"+
 				" but you are not packaging a tarball. Flag will be ignored")
 		}
+		if packCtx.Archive.BaseTgz != "" && !packsAnyOf(otherTypes, pack.Tgz) {
+			pack.WarnIgnored(packCtx, "You specified the --base-tgz flag,"+
+				" but you are not packaging a tarball. Flag will be ignored")
+		}
+		if packCtx.Archive.DeltaAgainst != "" && !packsAnyOf(otherTypes, pack.Tgz) {
+			pack.WarnIgnored(packCtx, "You specified the --delta-against flag,"+
+				" but you are not packaging a tarball. Flag will be ignored")
+		}
+		if packCtx.RpmDeb.InstallPrefix != "" && !filepath.IsAbs(packCtx.RpmDeb.InstallPrefix) {
+			return fmt.Errorf("install prefix %q must be an absolute path",
+				packCtx.RpmDeb.InstallPrefix)
+		}
+	case pack.Docker:
+		if packCtx.UseDocker {
+			return fmt.Errorf("--use-docker flag cannot be used while packing docker image")
+		}
+		if packCtx.Destination != "" {
+			return fmt.Errorf("--destination flag cannot be used while packing docker image")
+		}
+		if packCtx.WithChecksum && len(otherTypes) == 0 {
+			pack.WarnIgnored(packCtx, "You specified the --with-checksum flag,"+
+				" but you are packaging docker image. Flag will be ignored")
+		}
+		if packCtx.ArtifactMode != "" && len(otherTypes) == 0 {
+			pack.WarnIgnored(packCtx, "You specified the --artifact-mode flag,"+
+				" but you are packaging docker image. Flag will be ignored")
+		}
+		if packCtx.WarnSize != "" && len(otherTypes) == 0 {
+			pack.WarnIgnored(packCtx, "You specified the --warn-size flag,"+
+				" but you are packaging docker image. Flag will be ignored")
+		}
+		if packCtx.MaxSize != "" && len(otherTypes) == 0 {
+			pack.WarnIgnored(packCtx, "You specified the --max-size flag,"+
+				" but you are packaging docker image. Flag will be ignored")
+		}
+	}
+	if packCtx.Type == pack.AppImage {
+		if packCtx.UseDocker {
+			return fmt.Errorf("--use-docker flag cannot be used while packing AppImage")
+		}
+		if packCtx.WithoutBinaries {
+			return fmt.Errorf("--without-binaries flag cannot be used while packing AppImage")
+		}
+	}
+	if packCtx.Type == pack.Rpm {
+		// Version from git is checked on getting it. Its build metadata suffix, e.g.
+		// "+dirty", is moved into the RPM release.
+		if strings.Contains(packCtx.Version, "-") && !packCtx.VersionFromGit {
+			return fmt.Errorf("invalid RPM version %q: dashes are not allowed",
+				packCtx.Version)
+		}
+		if strings.ContainsAny(packCtx.RpmDeb.RpmRelease, "- \t") {
+			return fmt.Errorf("invalid RPM release %q: dashes and spaces are not allowed",
+				packCtx.RpmDeb.RpmRelease)
+		}
+	} else if !packsAnyOf(otherTypes, pack.Rpm) {
+		if packCtx.RpmDeb.RpmEpoch > 0 {
+			pack.WarnIgnored(packCtx, "You specified the --rpm-epoch flag,"+
+				" but you are not packaging RPM. Flag will be ignored")
+		}
+		if packCtx.RpmDeb.RpmRelease != "" {
+			pack.WarnIgnored(packCtx, "You specified the --rpm-release flag,"+
+				" but you are not packaging RPM. Flag will be ignored")
+		}
+		if packCtx.RpmDeb.RpmArch != "" {
+			pack.WarnIgnored(packCtx, "You specified the --rpm-arch flag,"+
+				" but you are not packaging RPM. Flag will be ignored")
+		}
+		if packCtx.RpmDeb.RpmScriptletInterpreter != "" {
+			pack.WarnIgnored(packCtx, "You specified the --rpm-scriptlet-interpreter flag,"+
+				" but you are not packaging RPM. Flag will be ignored")
+		}
+		if len(packCtx.RpmDeb.RpmConfigFiles) > 0 {
+			pack.WarnIgnored(packCtx, "You specified the --rpm-config-file flag,"+
+				" but you are not packaging RPM. Flag will be ignored")
+		}
+	}
+	if packCtx.Archive.BundleRoot != "" && packCtx.Type != pack.Tgz && packCtx.Type != pack.Zip &&
+		!packsAnyOf(otherTypes, pack.Tgz, pack.Zip) {
+		pack.WarnIgnored(packCtx, "You specified the --bundle-root flag,"+
+			" but you are not packaging tgz or zip. Flag will be ignored")
+	}
+	if len(packCtx.Archive.StoreGlobs) > 0 && packCtx.Type != pack.Zip &&
+		!packsAnyOf(otherTypes, pack.Zip) {
+		pack.WarnIgnored(packCtx, "You specified the --store-glob flag,"+
+			" but you are not packaging zip. Tarball is compressed as a whole. Flag will be"+
+			" ignored")
+	}
+	if packCtx.Archive.Dedup && packCtx.Type != pack.Tgz && !packsAnyOf(otherTypes, pack.Tgz) {
+		pack.WarnIgnored(packCtx, "You specified the --dedup flag,"+
+			" but you are not packaging tgz. Flag will be ignored")
+	}
+	if packCtx.Archive.SplitSize != "" && packCtx.Type != pack.Tgz &&
+		!packsAnyOf(otherTypes, pack.Tgz) {
+		pack.WarnIgnored(packCtx, "You specified the --split-size flag,"+
+			" but you are not packaging tgz. Flag will be ignored")
+	}
+	if packCtx.Archive.DedupMinSize != "" && !packCtx.Archive.Dedup {
+		pack.WarnIgnored(packCtx, "You specified the --dedup-min-size flag,"+
+			" but dedup is not enabled. Flag will be ignored")
+	}
+	if packCtx.RpmDeb.DebArch != "" && packCtx.Type != pack.Deb &&
+		!packsAnyOf(otherTypes, pack.Deb) {
+		pack.WarnIgnored(packCtx, "You specified the --deb-arch flag,"+
+			" but you are not packaging Deb. Flag will be ignored")
+	}
+	if len(packCtx.RpmDeb.DebConffiles) > 0 && packCtx.Type != pack.Deb &&
+		!packsAnyOf(otherTypes, pack.Deb) {
+		pack.WarnIgnored(packCtx, "You specified the --deb-conffile flag,"+
+			" but you are not packaging Deb. Flag will be ignored")
+	}
+	if err := pack.CheckCompression(packCtx.Archive); err != nil {
+		return err
+	}
+	if packCtx.RpmDeb.SignKey != "" && packCtx.UseDocker {
+		return fmt.Errorf("package signing is not supported with --use-docker flag")
+	}
+	if packCtx.AllowDirty && !packCtx.VersionFromGit {
+		pack.WarnIgnored(packCtx, "You specified the --allow-dirty flag,"+
+			" but the version is not got from git. Flag will be ignored")
+	}
+	if packCtx.VersionFromGit && packCtx.UseDocker {
+		return fmt.Errorf("--version-from-git flag cannot be used with --use-docker flag")
+	}
+	if packCtx.PostPackHook != "" && packCtx.UseDocker {
+		return fmt.Errorf("--post-pack-hook flag cannot be used with --use-docker flag")
+	}
+	if packCtx.OutputFormat == pack.OutputJSON && packCtx.UseDocker {
+		return fmt.Errorf("--output json cannot be used with --use-docker flag")
+	}
+	if packCtx.OutputFormat == pack.OutputJSON && packCtx.DryRun {
+		return fmt.Errorf("--output json cannot be used with --dry-run flag")
+	}
+	if packCtx.OutputFormat == pack.OutputJSON && packCtx.PrintSize {
+		return fmt.Errorf("--output json cannot be used with --print-size flag")
+	}
+	if packCtx.PrintSize && packCtx.UseDocker {
+		return fmt.Errorf("--print-size flag cannot be used with --use-docker flag")
+	}
+	if packCtx.PrintSize && packCtx.Type != pack.Tgz && packCtx.Type != pack.Zip &&
+		packCtx.Type != pack.Deb && packCtx.Type != pack.Rpm {
+		return fmt.Errorf("--print-size flag cannot be used while packing %s",
+			packCtx.Type)
+	}
+	if packCtx.OutputFormat == pack.OutputJSON && packCtx.ListApps {
+		return fmt.Errorf("--output json cannot be used with --list-apps flag")
+	}
+	if packCtx.Archive.BaseTgz != "" && packCtx.UseDocker {
+		return fmt.Errorf("--base-tgz flag cannot be used with --use-docker flag")
+	}
+	if packCtx.Archive.DeltaAgainst != "" && packCtx.UseDocker {
+		return fmt.Errorf("--delta-against flag cannot be used with --use-docker flag")
+	}
+	if packCtx.SourceDir != "" && packCtx.UseDocker {
+		return fmt.Errorf("--source-dir flag cannot be used with --use-docker flag")
+	}
+	if packCtx.Since != "" && packCtx.UseDocker {
+		return fmt.Errorf("--since flag cannot be used with --use-docker flag")
+	}
+	if packCtx.Destination != "" && packCtx.UseDocker {
+		return fmt.Errorf("--destination flag cannot be used with --use-docker flag")
+	}
+	if packCtx.Identity != "" && packCtx.Destination == "" {
+		pack.WarnIgnored(packCtx, "You specified the --identity flag,"+
+			" but the destination is not set. Flag will be ignored")
+	}
+	if packCtx.TarantoolBinary != "" && packCtx.UseDocker {
+		return fmt.Errorf("--tarantool-binary flag cannot be used with --use-docker flag")
+	}
+	if packCtx.TarantoolBinary != "" && packCtx.TarantoolVersion != "" {
+		return fmt.Errorf("--tarantool-binary flag cannot be used with --tarantool-version flag")
+	}
+	if packCtx.TtBinary != "" && packCtx.UseDocker {
+		return fmt.Errorf("--tt-binary flag cannot be used with --use-docker flag")
+	}
+	if packCtx.TarantoolBinary != "" && packCtx.WithoutBinaries {
+		pack.WarnIgnored(packCtx, "You specified the --tarantool-binary flag,"+
+			" but the binaries are not included. Flag will be ignored")
+	}
+	if packCtx.TtBinary != "" && packCtx.WithoutBinaries {
+		pack.WarnIgnored(packCtx, "You specified the --tt-binary flag,"+
+			" but the binaries are not included. Flag will be ignored")
+	}
+	if packCtx.BinariesLock != "" && packCtx.UseDocker {
+		return fmt.Errorf("--binaries-lock flag cannot be used with --use-docker flag")
+	}
+	if packCtx.StripDebug && packCtx.WithoutBinaries {
+		pack.WarnIgnored(packCtx, "You specified the --strip-debug flag,"+
+			" but the binaries are not included. Flag will be ignored")
+	}
+	if packCtx.BinariesLock != "" && packCtx.WithoutBinaries {
+		pack.WarnIgnored(packCtx, "You specified the --binaries-lock flag,"+
+			" but the binaries are not included. Flag will be ignored")
+	}
+	if packCtx.CacheDir != "" && packCtx.UseDocker {
+		return fmt.Errorf("--cache-dir flag cannot be used with --use-docker flag")
+	}
+	if len(packCtx.ExtraFiles) > 0 && packCtx.UseDocker {
+		return fmt.Errorf("--extra-file flag cannot be used with --use-docker flag")
+	}
+	if packCtx.NoRebuild && packCtx.WithoutRocks {
+		pack.WarnIgnored(packCtx, "You specified the --no-rebuild flag,"+
+			" but the rocks are not included. Flag will be ignored")
+	}
+	if packCtx.Force && len(packCtx.ExtraFiles) == 0 {
+		pack.WarnIgnored(packCtx, "You specified the --force flag,"+
+			" but no extra files are set. Flag will be ignored")
+	}
+	if packCtx.AppListFile != "" && packCtx.SourceDir != "" {
+		pack.WarnIgnored(packCtx, "You specified the --app-list-file flag,"+
+			" but you are packing a prebuilt bundle. Flag will be ignored")
+	}
+	if packCtx.GitRef != "" && packCtx.GitURL == "" {
+		pack.WarnIgnored(packCtx, "You specified the --git-ref flag,"+
+			" but the --git-url is not set. Flag will be ignored")
+	}
+	if len(packCtx.AppVersions) > 0 && packCtx.SourceDir != "" {
+		pack.WarnIgnored(packCtx, "You specified the --app-version flag,"+
+			" but you are packing a prebuilt bundle. Flag will be ignored")
+	}
+	if packCtx.CacheDir != "" && packCtx.SourceDir != "" {
+		pack.WarnIgnored(packCtx, "You specified the --cache-dir flag,"+
+			" but you are packing a prebuilt bundle. Flag will be ignored")
+	}
+	if packCtx.Since != "" && packCtx.SourceDir != "" {
+		pack.WarnIgnored(packCtx, "You specified the --since flag,"+
+			" but you are packing a prebuilt bundle. Flag will be ignored")
+	}
+	if packCtx.ConfigOverlay != "" && packCtx.SourceDir != "" {
+		pack.WarnIgnored(packCtx, "You specified the --config-overlay flag,"+
+			" but you are packing a prebuilt bundle. Flag will be ignored")
+	}
+	if packCtx.ConfigOverlayMode != "" && packCtx.ConfigOverlay == "" {
+		pack.WarnIgnored(packCtx, "You specified the --config-overlay-mode flag,"+
+			" but no config overlay is set. Flag will be ignored")
+	}
+	if packCtx.FollowConfig && packCtx.SourceDir != "" {
+		pack.WarnIgnored(packCtx, "You specified the --follow-config flag,"+
+			" but you are packing a prebuilt bundle. Flag will be ignored")
+	}
+	if packCtx.WithBinaries && packCtx.WithoutBinaries {
+		return fmt.Errorf("--with-binaries and --without-binaries flags cannot be used together")
+	}
+	if packCtx.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %s: must not be negative", packCtx.Timeout)
+	}
+	if packCtx.LockTimeout < 0 {
+		return fmt.Errorf("invalid lock timeout %s: must not be negative", packCtx.LockTimeout)
+	}
+	if packCtx.Sbom && packCtx.SourceDir != "" {
+		pack.WarnIgnored(packCtx, "You specified the --sbom flag,"+
+			" but you are packing a prebuilt bundle. Flag will be ignored")
+	}
+	if packCtx.SbomArtifact && !packCtx.Sbom {
+		pack.WarnIgnored(packCtx, "You specified the --sbom-artifact flag,"+
+			" but SBOM is not enabled with --sbom. Flag will be ignored")
+	}
+	if packCtx.NoLock && packCtx.LockTimeout != pack.DefaultLockTimeout {
+		pack.WarnIgnored(packCtx, "You specified the --lock-timeout flag,"+
+			" but locking is disabled with --no-lock. Flag will be ignored")
+	}
+	if packCtx.Jobs < 0 {
+		return fmt.Errorf("invalid jobs count %d: must not be negative", packCtx.Jobs)
+	}
+	if packCtx.IORetries < 0 {
+		return fmt.Errorf("invalid I/O retries count %d: must not be negative",
+			packCtx.IORetries)
 	}
 	// Check if --with-integrity-check and --without-binaries flags are provided
 	// simultaneously. If this is the case, return an error for safety reasons.
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/pack"
+)
+
+func TestCheckFlags(t *testing.T) {
+	cases := []struct {
+		name        string
+		packCtx     pack.PackCtx
+		expectedErr string
+	}{
+		{
+			name: "default compression level",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+		},
+		{
+			name:    "no compression",
+			packCtx: pack.PackCtx{Type: pack.Tgz, Archive: pack.ArchiveCtx{CompressionLevel: 0}},
+		},
+		{
+			name:    "best compression",
+			packCtx: pack.PackCtx{Type: pack.Tgz, Archive: pack.ArchiveCtx{CompressionLevel: 9}},
+		},
+		{
+			name: "negative compression level",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{CompressionLevel: -1}},
+			expectedErr: "invalid compression level -1: must be in range from 0 to 9",
+		},
+		{
+			name: "too big compression level",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{CompressionLevel: 10}},
+			expectedErr: "invalid compression level 10: must be in range from 0 to 9",
+		},
+		{
+			name: "zstd best compression",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{Compressor: pack.CompressorZstd, CompressionLevel: 22}},
+		},
+		{
+			name: "zstd no compression",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{Compressor: pack.CompressorZstd, CompressionLevel: 0}},
+			expectedErr: "invalid compression level 0: must be in range from 1 to 22",
+		},
+		{
+			name: "unknown compressor",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{Compressor: "xz", CompressionLevel: 6}},
+			expectedErr: `unknown compressor "xz", supported: gzip, zstd`,
+		},
+		{
+			name: "sign key for tarball",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel},
+				RpmDeb:  pack.RpmDebCtx{SignKey: "packager@example.com"}},
+		},
+		{
+			name: "conflicts and provides for tarball",
+			packCtx: pack.PackCtx{Type: pack.Tgz,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel},
+				RpmDeb: pack.RpmDebCtx{Conflicts: []string{"old_app"},
+					Provides: []string{"app"}}},
+		},
+		{
+			name: "rpm version with dash",
+			packCtx: pack.PackCtx{Type: pack.Rpm, Version: "1.0.0-1",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: `invalid RPM version "1.0.0-1": dashes are not allowed`,
+		},
+		{
+			name: "rpm release with dash",
+			packCtx: pack.PackCtx{Type: pack.Rpm, Version: "1.0.0",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel},
+				RpmDeb:  pack.RpmDebCtx{RpmEpoch: 2, RpmRelease: "2-beta"}},
+			expectedErr: `invalid RPM release "2-beta": dashes and spaces are not allowed`,
+		},
+		{
+			name: "rpm epoch and release for deb",
+			packCtx: pack.PackCtx{Type: pack.Deb, Version: "1.0.0-1",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel},
+				RpmDeb:  pack.RpmDebCtx{RpmEpoch: 2, RpmRelease: "3"}},
+		},
+		{
+			name: "negative jobs count",
+			packCtx: pack.PackCtx{Type: pack.Tgz, Jobs: -1,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "invalid jobs count -1: must not be negative",
+		},
+		{
+			name: "negative io retries count",
+			packCtx: pack.PackCtx{Type: pack.Tgz, IORetries: -1,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "invalid I/O retries count -1: must not be negative",
+		},
+		{
+			name: "negative timeout",
+			packCtx: pack.PackCtx{Type: pack.Tgz, Timeout: -time.Second,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "invalid timeout -1s: must not be negative",
+		},
+		{
+			name: "docker image in docker",
+			packCtx: pack.PackCtx{Type: pack.Docker, UseDocker: true,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--use-docker flag cannot be used while packing docker image",
+		},
+		{
+			name: "json output in dry run",
+			packCtx: pack.PackCtx{Type: pack.Tgz, DryRun: true, OutputFormat: pack.OutputJSON,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--output json cannot be used with --dry-run flag",
+		},
+		{
+			name: "json output in list apps",
+			packCtx: pack.PackCtx{Type: pack.Tgz, ListApps: true, OutputFormat: pack.OutputJSON,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--output json cannot be used with --list-apps flag",
+		},
+		{
+			name: "print size of docker image",
+			packCtx: pack.PackCtx{Type: pack.Docker, PrintSize: true,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--print-size flag cannot be used while packing docker",
+		},
+		{
+			name: "AppImage without binaries",
+			packCtx: pack.PackCtx{Type: pack.AppImage, WithoutBinaries: true,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--without-binaries flag cannot be used while packing AppImage",
+		},
+		{
+			name: "source dir in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true, SourceDir: "bundle",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--source-dir flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "since in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true, Since: "v1.0.0",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--since flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "tarantool binary with version",
+			packCtx: pack.PackCtx{Type: pack.Tgz, TarantoolBinary: "/opt/tarantool",
+				TarantoolVersion: "3.1.0",
+				Archive:          pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--tarantool-binary flag cannot be used with --tarantool-version flag",
+		},
+		{
+			name: "with and without binaries",
+			packCtx: pack.PackCtx{Type: pack.Tgz, WithBinaries: true, WithoutBinaries: true,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--with-binaries and --without-binaries flags cannot be used together",
+		},
+		{
+			name: "destination in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true,
+				Destination: "scp://host/srv",
+				Archive:     pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--destination flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "destination for docker image",
+			packCtx: pack.PackCtx{Type: pack.Docker, Destination: "scp://host/srv",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--destination flag cannot be used while packing docker image",
+		},
+		{
+			name: "binaries lock in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true, BinariesLock: "bin.lock",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--binaries-lock flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "cache dir in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true, CacheDir: "cache",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--cache-dir flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "extra file in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true,
+				ExtraFiles: []string{"README:README"},
+				Archive:    pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--extra-file flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "base tarball in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel,
+					BaseTgz: "base.tar.gz"}},
+			expectedErr: "--base-tgz flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "delta in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel,
+					DeltaAgainst: "prev.tar.gz"}},
+			expectedErr: "--delta-against flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "post-pack hook in docker",
+			packCtx: pack.PackCtx{Type: pack.Tgz, UseDocker: true, PostPackHook: "./hook.sh",
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel}},
+			expectedErr: "--post-pack-hook flag cannot be used with --use-docker flag",
+		},
+		{
+			name: "relative install prefix",
+			packCtx: pack.PackCtx{Type: pack.Deb,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel},
+				RpmDeb:  pack.RpmDebCtx{InstallPrefix: "opt/tarantool"}},
+			expectedErr: `install prefix "opt/tarantool" must be an absolute path`,
+		},
+		{
+			name: "absolute install prefix",
+			packCtx: pack.PackCtx{Type: pack.Rpm,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel},
+				RpmDeb:  pack.RpmDebCtx{InstallPrefix: "/opt/company/tarantool"}},
+		},
+		{
+			name: "sign key in docker",
+			packCtx: pack.PackCtx{Type: pack.Rpm, UseDocker: true,
+				Archive: pack.ArchiveCtx{CompressionLevel: pack.DefaultCompressionLevel},
+				RpmDeb:  pack.RpmDebCtx{SignKey: "packager@example.com"}},
+			expectedErr: "package signing is not supported with --use-docker flag",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkFlags(&tc.packCtx)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCheckPackTypes(t *testing.T) {
+	cmd := NewPackCmd()
+	assert.NoError(t, checkPackTypes(cmd, "tgz"))
+	assert.NoError(t, checkPackTypes(cmd, "tgz,rpm,deb"))
+	assert.EqualError(t, checkPackTypes(cmd, "tgz,exe"), `unknown package type "exe" for "pack", `+
+		`supported types: tgz, zip, deb, rpm, docker, appimage`)
+	assert.EqualError(t, checkPackTypes(cmd, "tgz,"), `unknown package type "" for "pack", `+
+		`supported types: tgz, zip, deb, rpm, docker, appimage`)
+	assert.EqualError(t, checkPackTypes(cmd, "rpm,tgz,rpm"),
+		`package type "rpm" is passed several times`)
+}
+
+func TestGetPackExitCode(t *testing.T) {
+	cmd := NewPackCmd()
+	cases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"other failure", errors.New("failed to pack"), packExitFailure},
+		{"config error", &packConfigError{errors.New("invalid option")}, packExitConfig},
+		{"unknown type argument", checkPackArgs(cmd, []string{"exe"}), packExitUnsupportedType},
+		{"unsupported type", fmt.Errorf("failed to pack: %w", pack.ErrUnsupportedType),
+			packExitUnsupportedType},
+		{"binary not found", &packConfigError{fmt.Errorf("cannot include tt: %w",
+			pack.ErrBinaryNotFound)}, packExitBinaryNotFound},
+		{"no space", fmt.Errorf("failed to pack: %w", pack.ErrNoSpace), packExitIO},
+		{"write error", fmt.Errorf("failed to pack: %w",
+			&fs.PathError{Op: "write", Path: "app.tgz", Err: fs.ErrClosed}), packExitIO},
+		{"rename error", fmt.Errorf("failed to pack: %w",
+			&os.LinkError{Op: "rename", Old: "app.tgz.tmp", New: "app.tgz", Err: fs.ErrClosed}),
+			packExitIO},
+		{"missing file", fmt.Errorf("failed to pack: %w",
+			&fs.PathError{Op: "open", Path: "init.lua", Err: fs.ErrNotExist}), packExitFailure},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getPackExitCode(tc.err))
+		})
+	}
+}
+
+func TestGetPackExitCodeBinaryNotFound(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "instances.enabled", "app")
+	require.NoError(t, os.MkdirAll(appDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, ".tarantool.yml"),
+		[]byte("tarantool: 3.0.0\n"), 0644))
+	notExecutable := filepath.Join(envDir, "tt")
+	require.NoError(t, os.WriteFile(notExecutable, nil, 0644))
+
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{
+		InstancesEnabled: filepath.Join(envDir, "instances.enabled"),
+		BinDir:           filepath.Join(envDir, "bin")}}
+	cases := []struct {
+		name    string
+		packCtx pack.PackCtx
+	}{
+		{"missing tarantool binary",
+			pack.PackCtx{TarantoolBinary: filepath.Join(envDir, "missing")}},
+		{"not executable tt binary", pack.PackCtx{TtBinary: notExecutable}},
+		{"pinned tarantool is not installed", pack.PackCtx{}},
+		{"tarantool version is not installed", pack.PackCtx{TarantoolVersion: "2.11.1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmdCtx := cmdcontext.CmdCtx{}
+			cmdCtx.Cli.ConfigDir = envDir
+			err := pack.FillCtx(&cmdCtx, &tc.packCtx, cliOpts, []string{pack.Tgz})
+			require.Error(t, err)
+			assert.Equal(t, packExitBinaryNotFound, getPackExitCode(&packConfigError{err}))
+		})
+	}
+}
+
+func TestPacksAnyOf(t *testing.T) {
+	assert.True(t, packsAnyOf([]string{pack.Tgz, pack.Rpm}, pack.Rpm, pack.Deb))
+	assert.False(t, packsAnyOf([]string{pack.Zip}, pack.Rpm, pack.Deb))
+	assert.False(t, packsAnyOf(nil, pack.Tgz))
+}
+
+func TestCompleteCommaList(t *testing.T) {
+	choices := []string{"tgz", "zip", "deb", "rpm"}
+	assert.Equal(t, choices, completeCommaList("", choices))
+	assert.Equal(t, []string{"tgz"}, completeCommaList("t", choices))
+	assert.Equal(t, []string{"tgz,zip", "tgz,deb", "tgz,rpm"}, completeCommaList("tgz,", choices))
+	assert.Equal(t, []string{"tgz,deb,rpm"}, completeCommaList("tgz,deb,r", choices))
+	assert.Empty(t, completeCommaList("exe", choices))
+}
+
+func TestPackValidArgsFunction(t *testing.T) {
+	cmd := NewPackCmd()
+	completions, directive := cmd.ValidArgsFunction(cmd, nil, "d")
+	assert.Equal(t, []string{"deb", "docker"}, completions)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	completions, _ = cmd.ValidArgsFunction(cmd, []string{"tgz"}, "")
+	assert.Empty(t, completions)
+}
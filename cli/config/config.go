@@ -20,6 +20,16 @@ package config
 //    distfiles: path
 //  ee:
 //    credential_path: path
+//  pack:
+//    name: name
+//    version: version
+//    deps: [dependency]
+//    deps_file: path
+//    preinst: path
+//    postinst: path
+//    output_dir: path
+//    install_prefix: path
+//    exclude: [pattern]
 
 // ModuleOpts is used to store all module options.
 type ModulesOpts struct {
@@ -83,6 +93,34 @@ type RepoOpts struct {
 	Install string `mapstructure:"distfiles" yaml:"distfiles"`
 }
 
+// PackOpts is used to store default options of tt pack command.
+// Command line flags take precedence over these options.
+type PackOpts struct {
+	// Name is a package name.
+	Name string `mapstructure:"name" yaml:"name,omitempty"`
+	// Version is a package version.
+	Version string `mapstructure:"version" yaml:"version,omitempty"`
+	// Deps is a list of package dependencies.
+	Deps []string `mapstructure:"deps" yaml:"deps,omitempty"`
+	// DepsFile is a path to a file of package dependencies.
+	DepsFile string `mapstructure:"deps_file" yaml:"deps_file,omitempty"`
+	// PreInst is a path to pre-install script.
+	PreInst string `mapstructure:"preinst" yaml:"preinst,omitempty"`
+	// PostInst is a path to post-install script.
+	PostInst string `mapstructure:"postinst" yaml:"postinst,omitempty"`
+	// PreRm is a path to pre-remove script.
+	PreRm string `mapstructure:"prerm" yaml:"prerm,omitempty"`
+	// PostRm is a path to post-remove script.
+	PostRm string `mapstructure:"postrm" yaml:"postrm,omitempty"`
+	// OutputDir is a directory to write the result package to.
+	OutputDir string `mapstructure:"output_dir" yaml:"output_dir,omitempty"`
+	// InstallPrefix is a directory where the environment is installed by rpm and deb packages.
+	InstallPrefix string `mapstructure:"install_prefix" yaml:"install_prefix,omitempty"`
+	// Exclude is a list of gitignore-style patterns of bundle-relative paths of application
+	// files to skip.
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+}
+
 // CliOpts is used to store modules and app options.
 type CliOpts struct {
 	// Env is struct describing tt environment options.
@@ -97,4 +135,6 @@ type CliOpts struct {
 	Templates []TemplateOpts
 	// Repo is a struct used to store paths to local files.
 	Repo *RepoOpts
+	// Pack is a struct that contains default tt pack options.
+	Pack *PackOpts `yaml:"pack,omitempty"`
 }
@@ -217,6 +217,19 @@ func updateCliOpts(cliOpts *config.CliOpts, configDir string) error {
 		}
 	}
 
+	if cliOpts.Pack != nil {
+		for _, path := range []*string{&cliOpts.Pack.DepsFile, &cliOpts.Pack.PreInst,
+			&cliOpts.Pack.PostInst, &cliOpts.Pack.PreRm, &cliOpts.Pack.PostRm,
+			&cliOpts.Pack.OutputDir} {
+			if *path == "" {
+				continue
+			}
+			if *path, err = adjustPathWithConfigLocation(*path, configDir, ""); err != nil {
+				return err
+			}
+		}
+	}
+
 	for i := range cliOpts.Templates {
 		if cliOpts.Templates[i].Path, err = adjustPathWithConfigLocation(
 			cliOpts.Templates[i].Path, configDir, "."); err != nil {
@@ -337,3 +337,36 @@ func TestUpdateCliOpts(t *testing.T) {
 	assert.Equal(t, filepath.Join(configDir, ModulesPath), cliOpts.Modules.Directory)
 	assert.Equal(t, configDir, cliOpts.Env.InstancesEnabled)
 }
+
+func TestGetCliOptsPackSection(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, ConfigName)
+	require.NoError(t, os.WriteFile(configPath, []byte(`pack:
+  name: bundle
+  deps:
+    - tarantool>=2.10
+  preinst: scripts/preinst.sh
+  postinst: /opt/postinst.sh
+  prerm: scripts/prerm.sh
+  exclude:
+    - "*.log"
+`), 0644))
+
+	repository := newMockRepository()
+	cliOpts, _, err := GetCliOpts(configPath, &repository)
+	require.NoError(t, err)
+	require.NotNil(t, cliOpts.Pack)
+	assert.Equal(t, "bundle", cliOpts.Pack.Name)
+	assert.Equal(t, []string{"tarantool>=2.10"}, cliOpts.Pack.Deps)
+	assert.Equal(t, filepath.Join(configDir, "scripts", "preinst.sh"), cliOpts.Pack.PreInst)
+	assert.Equal(t, "/opt/postinst.sh", cliOpts.Pack.PostInst)
+	assert.Equal(t, filepath.Join(configDir, "scripts", "prerm.sh"), cliOpts.Pack.PreRm)
+	assert.Equal(t, "", cliOpts.Pack.DepsFile)
+	assert.Equal(t, []string{"*.log"}, cliOpts.Pack.Exclude)
+
+	// Missing section.
+	require.NoError(t, os.WriteFile(configPath, []byte("env:\n  bin_dir: bin\n"), 0644))
+	cliOpts, _, err = GetCliOpts(configPath, &repository)
+	require.NoError(t, err)
+	assert.Nil(t, cliOpts.Pack)
+}
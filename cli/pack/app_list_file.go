@@ -0,0 +1,58 @@
+package pack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tarantool/tt/cli/util"
+)
+
+// loadAppListFile reads the application names from the file. Each line contains one
+// name, empty lines and lines starting with # are skipped.
+func loadAppListFile(appListFile string) ([]string, error) {
+	file, err := os.Open(appListFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read application list file %q: %s", appListFile, err)
+	}
+	defer file.Close()
+
+	appList := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		appList = append(appList, line)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read application list file %q: %s", appListFile, err)
+	}
+	return appList, nil
+}
+
+// mergeAppListFile adds the applications from the application list file to the
+// application list. Duplicate names are removed.
+func mergeAppListFile(packCtx *PackCtx) error {
+	fileApps, err := loadAppListFile(packCtx.AppListFile)
+	if err != nil {
+		return err
+	}
+	appList := []string{}
+	packCtx.appListFileApps = map[string]bool{}
+	for _, appName := range packCtx.AppList {
+		if util.Find(appList, appName) == -1 {
+			appList = append(appList, appName)
+		}
+	}
+	for _, appName := range fileApps {
+		packCtx.appListFileApps[appName] = true
+		if util.Find(appList, appName) == -1 {
+			appList = append(appList, appName)
+		}
+	}
+	packCtx.AppList = appList
+	return nil
+}
@@ -0,0 +1,52 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_loadAppListFile(t *testing.T) {
+	appListFile := filepath.Join(t.TempDir(), "apps.txt")
+	require.NoError(t, os.WriteFile(appListFile,
+		[]byte("# Applications.\napp1\n\n  app2  \n#app3\napp1\n"), 0644))
+	appList, err := loadAppListFile(appListFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app1", "app2", "app1"}, appList)
+
+	_, err = loadAppListFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.ErrorContains(t, err, "cannot read application list file")
+}
+
+func Test_mergeAppListFile(t *testing.T) {
+	appListFile := filepath.Join(t.TempDir(), "apps.txt")
+	require.NoError(t, os.WriteFile(appListFile, []byte("app2\napp3\napp2\n"), 0644))
+
+	packCtx := PackCtx{AppList: []string{"app1", "app2", "app1"}, AppListFile: appListFile}
+	require.NoError(t, mergeAppListFile(&packCtx))
+	assert.Equal(t, []string{"app1", "app2", "app3"}, packCtx.AppList)
+	assert.Equal(t, map[string]bool{"app2": true, "app3": true}, packCtx.appListFileApps)
+}
+
+func Test_initAppsInfoMissingListedApps(t *testing.T) {
+	envDir := t.TempDir()
+	appsDir := filepath.Join(envDir, "instances.enabled")
+	require.NoError(t, os.MkdirAll(filepath.Join(appsDir, "app1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appsDir, "app1", "init.lua"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appsDir, "notes.txt"), nil, 0644))
+
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = envDir
+	packCtx := PackCtx{AppList: []string{"app1", "missing", "notes.txt"},
+		AppListFile:     "apps.txt",
+		appListFileApps: map[string]bool{"missing": true, "notes.txt": true}}
+	err := initAppsInfo(&config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: appsDir}},
+		&cmdCtx, &packCtx)
+	assert.EqualError(t, err, `applications listed in "apps.txt" are not found: missing, `+
+		"notes.txt")
+}
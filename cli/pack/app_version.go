@@ -0,0 +1,39 @@
+package pack
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseAppVersions parses app=version overrides of the packed applications versions.
+// The applications must be packed and the overrides must be unique.
+func parseAppVersions(packCtx *PackCtx) error {
+	if len(packCtx.AppVersions) == 0 {
+		return nil
+	}
+	packCtx.appVersions = make(map[string]string, len(packCtx.AppVersions))
+	for _, override := range packCtx.AppVersions {
+		appName, appVersion, found := strings.Cut(override, "=")
+		if !found || appName == "" || appVersion == "" {
+			return fmt.Errorf("invalid application version %q: expected app=version", override)
+		}
+		if _, found := packCtx.AppsInfo[appName]; !found {
+			return fmt.Errorf("invalid application version %q: application %q is not packed",
+				override, appName)
+		}
+		if _, found := packCtx.appVersions[appName]; found {
+			return fmt.Errorf("version of application %q is passed several times", appName)
+		}
+		packCtx.appVersions[appName] = appVersion
+	}
+	return nil
+}
+
+// getAppVersion returns the version of the packed application. The package version is
+// used if the application version is not overridden.
+func getAppVersion(packCtx *PackCtx, appName, packageVersion string) string {
+	if appVersion, found := packCtx.appVersions[appName]; found {
+		return appVersion
+	}
+	return packageVersion
+}
@@ -0,0 +1,35 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func Test_parseAppVersions(t *testing.T) {
+	appsInfo := map[string][]running.InstanceCtx{
+		"app1": {{AppName: "app1"}},
+		"app2": {{AppName: "app2"}},
+	}
+	packCtx := &PackCtx{AppsInfo: appsInfo, AppVersions: []string{"app1=1.2.3"}}
+	require.NoError(t, parseAppVersions(packCtx))
+	assert.Equal(t, "1.2.3", getAppVersion(packCtx, "app1", "0.1.0"))
+	assert.Equal(t, "0.1.0", getAppVersion(packCtx, "app2", "0.1.0"))
+
+	for override, expectedErr := range map[string]string{
+		"app1":   `invalid application version "app1": expected app=version`,
+		"app1=":  `invalid application version "app1=": expected app=version`,
+		"=1.0.0": `invalid application version "=1.0.0": expected app=version`,
+		"other=1.0.0": `invalid application version "other=1.0.0": ` +
+			`application "other" is not packed`,
+	} {
+		packCtx = &PackCtx{AppsInfo: appsInfo, AppVersions: []string{override}}
+		assert.EqualError(t, parseAppVersions(packCtx), expectedErr)
+	}
+
+	packCtx = &PackCtx{AppsInfo: appsInfo, AppVersions: []string{"app1=1.0.0", "app1=2.0.0"}}
+	assert.EqualError(t, parseAppVersions(packCtx),
+		`version of application "app1" is passed several times`)
+}
@@ -0,0 +1,154 @@
+package pack
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/configure"
+	"github.com/tarantool/tt/cli/templates"
+	"github.com/tarantool/tt/cli/util"
+)
+
+//go:embed templates/AppRun.appimage
+var appImageAppRun string
+
+//go:embed templates/appimage.desktop
+var appImageDesktop string
+
+//go:embed templates/appimage_icon.svg
+var appImageIcon []byte
+
+// appImagePacker is a structure that implements Packer interface
+// with specific AppImage packing behavior.
+type appImagePacker struct {
+}
+
+// getAppImageSuffix returns the suffix of the AppImage file.
+func getAppImageSuffix() (string, error) {
+	arch, err := util.GetArch()
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{"", arch, "AppImage"}, "."), nil
+}
+
+// createAppDir turns the bundle directory into AppDir: adds AppRun entry point,
+// desktop entry and icon files.
+func createAppDir(bundlePath string, packCtx *PackCtx, opts *config.CliOpts) error {
+	envPath := "."
+	if opts.Env.InstancesEnabled == "." || packCtx.CartridgeCompat {
+		envPath = packCtx.Name
+	}
+
+	engine := templates.NewDefaultEngine()
+	appRun, err := engine.RenderText(appImageAppRun, map[string]string{
+		"env_path":    envPath,
+		"config_name": configure.ConfigName,
+	})
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(bundlePath, "AppRun"), []byte(appRun), 0755); err != nil {
+		return fmt.Errorf("failed to write AppRun: %s", err)
+	}
+
+	desktop, err := engine.RenderText(appImageDesktop, map[string]string{"name": packCtx.Name})
+	if err != nil {
+		return err
+	}
+	desktopPath := filepath.Join(bundlePath, packCtx.Name+".desktop")
+	if err = os.WriteFile(desktopPath, []byte(desktop), 0644); err != nil {
+		return fmt.Errorf("failed to write desktop entry: %s", err)
+	}
+
+	iconPath := filepath.Join(bundlePath, packCtx.Name+".svg")
+	if err = os.WriteFile(iconPath, appImageIcon, 0644); err != nil {
+		return fmt.Errorf("failed to write icon: %s", err)
+	}
+	return nil
+}
+
+// Run of appImagePacker packs the bundle into AppImage using appimagetool.
+func (packer *appImagePacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
+	opts *config.CliOpts) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("AppImage can be built only on Linux")
+	}
+	if err := util.CheckRequiredBinaries("appimagetool"); err != nil {
+		return fmt.Errorf("appimagetool is required to build an AppImage: %s", err)
+	}
+
+	// The AppImage must be self-contained, so it always includes tarantool and tt.
+	appImagePackCtx := *packCtx
+	appImagePackCtx.WithBinaries = true
+
+	bundlePath, err := prepareBundle(cmdCtx, &appImagePackCtx, opts, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := os.RemoveAll(bundlePath)
+		if err != nil {
+			log.Warnf("Failed to remove a temporary directory %s: %s",
+				bundlePath, err.Error())
+		}
+	}()
+
+	log.Debugf("The package structure is created in: %s", bundlePath)
+
+	if err = createAppDir(bundlePath, &appImagePackCtx, opts); err != nil {
+		return err
+	}
+
+	appImageSuffix, err := getAppImageSuffix()
+	if err != nil {
+		return err
+	}
+	appImageName, err := getPackageFileName(packCtx, opts, appImageSuffix, true)
+	if err != nil {
+		return err
+	}
+	if appImageName, err = getPackageFilePath(packCtx, appImageName); err != nil {
+		return err
+	}
+	if err = lockArtifact(packCtx, appImageName); err != nil {
+		return err
+	}
+
+	log.Infof("Creating AppImage.")
+
+	arch, err := util.GetArch()
+	if err != nil {
+		return err
+	}
+	err = writePackageFile(packCtx, appImageName, func(tmpPath string) error {
+		buildCmd := exec.CommandContext(packCtx.operation.context(), "appimagetool",
+			bundlePath, tmpPath)
+		buildCmd.Env = append(os.Environ(), "ARCH="+arch)
+		buildCmd.Stdout = getCmdStdout(packCtx)
+		buildCmd.Stderr = os.Stderr
+		if err := buildCmd.Run(); err != nil {
+			return fmt.Errorf("failed to build AppImage: %s", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	packCtx.artifactPath = appImageName
+	log.Infof("Bundle is packed successfully to %s.", appImageName)
+
+	if packCtx.WithChecksum {
+		return writeChecksumFile(appImageName)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_createAppDir(t *testing.T) {
+	bundlePath := t.TempDir()
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	require.NoError(t, createAppDir(bundlePath, &PackCtx{Name: "bundle"}, opts))
+
+	stat, err := os.Stat(filepath.Join(bundlePath, "AppRun"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), stat.Mode().Perm())
+	appRun, err := os.ReadFile(filepath.Join(bundlePath, "AppRun"))
+	require.NoError(t, err)
+	assert.Contains(t, string(appRun), `ENV_PATH="$APPDIR/."`)
+	assert.Contains(t, string(appRun), `--cfg "$ENV_PATH/tt.yaml"`)
+
+	desktop, err := os.ReadFile(filepath.Join(bundlePath, "bundle.desktop"))
+	require.NoError(t, err)
+	assert.Contains(t, string(desktop), "Name=bundle\n")
+	assert.Contains(t, string(desktop), "Icon=bundle\n")
+	assert.FileExists(t, filepath.Join(bundlePath, "bundle.svg"))
+
+	// Single application environment is placed in the application sub-directory.
+	bundlePath = t.TempDir()
+	opts.Env.InstancesEnabled = "."
+	require.NoError(t, createAppDir(bundlePath, &PackCtx{Name: "app"}, opts))
+	appRun, err = os.ReadFile(filepath.Join(bundlePath, "AppRun"))
+	require.NoError(t, err)
+	assert.Contains(t, string(appRun), `ENV_PATH="$APPDIR/app"`)
+}
+
+func Test_appImagePackerNoAppimagetool(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("AppImage can be built only on Linux")
+	}
+	t.Setenv("PATH", t.TempDir())
+	packer := appImagePacker{}
+	err := packer.Run(&cmdcontext.CmdCtx{}, &PackCtx{}, &config.CliOpts{})
+	assert.ErrorContains(t, err, "appimagetool is required to build an AppImage")
+}
@@ -0,0 +1,148 @@
+package pack
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/apex/log"
+)
+
+// knownArches contains Go names of the architectures the binaries can be checked for.
+var knownArches = []string{"amd64", "arm64", "386", "arm", "ppc64", "ppc64le", "riscv64",
+	"s390x"}
+
+// archAliases maps architecture names used by packaging tools to Go architecture names.
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+	"i386":    "386",
+	"i686":    "386",
+	"armhf":   "arm",
+	"armel":   "arm",
+	"armv7hl": "arm",
+	"ppc64el": "ppc64le",
+}
+
+// elfArches maps ELF machine types to Go architecture names.
+var elfArches = map[elf.Machine]string{
+	elf.EM_X86_64:  "amd64",
+	elf.EM_AARCH64: "arm64",
+	elf.EM_386:     "386",
+	elf.EM_ARM:     "arm",
+	elf.EM_S390:    "s390x",
+	elf.EM_RISCV:   "riscv64",
+}
+
+// machoArches maps Mach-O CPU types to Go architecture names.
+var machoArches = map[macho.Cpu]string{
+	macho.CpuAmd64: "amd64",
+	macho.CpuArm64: "arm64",
+	macho.Cpu386:   "386",
+	macho.CpuArm:   "arm",
+}
+
+// debArches contains the Debian package architecture names.
+var debArches = []string{"amd64", "arm64", "i386", "armhf", "armel", "ppc64el", "ppc64",
+	"riscv64", "s390x", "all"}
+
+// rpmArches contains the RPM package architecture names.
+var rpmArches = []string{"x86_64", "aarch64", "i386", "i686", "armv7hl", "ppc64le", "ppc64",
+	"riscv64", "s390x", "noarch"}
+
+// errUnknownBinaryFormat is returned if the binary is neither ELF nor Mach-O file.
+var errUnknownBinaryFormat = errors.New("unknown binary format")
+
+// normalizeArch returns Go architecture name for the passed architecture.
+// Host architecture is returned for the empty string.
+func normalizeArch(arch string) (string, error) {
+	if arch == "" {
+		return runtime.GOARCH, nil
+	}
+	arch = strings.ToLower(arch)
+	if goArch, found := archAliases[arch]; found {
+		return goArch, nil
+	}
+	for _, known := range knownArches {
+		if known == arch {
+			return arch, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported target architecture %q", arch)
+}
+
+// checkPackageArch checks the package architecture is one of the known architecture names
+// of the package format.
+func checkPackageArch(format, arch string, known []string) error {
+	for _, knownArch := range known {
+		if knownArch == arch {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported %s package architecture %q, expected one of: %s", format,
+		arch, strings.Join(known, ", "))
+}
+
+// getBinaryArches returns Go architecture names of the ELF or Mach-O binary.
+// Universal Mach-O binary may contain several architectures.
+func getBinaryArches(binaryPath string) ([]string, error) {
+	if elfFile, err := elf.Open(binaryPath); err == nil {
+		defer elfFile.Close()
+		if elfFile.Machine == elf.EM_PPC64 {
+			if elfFile.Data == elf.ELFDATA2LSB {
+				return []string{"ppc64le"}, nil
+			}
+			return []string{"ppc64"}, nil
+		}
+		if arch, found := elfArches[elfFile.Machine]; found {
+			return []string{arch}, nil
+		}
+		return []string{elfFile.Machine.String()}, nil
+	}
+
+	if machoFile, err := macho.Open(binaryPath); err == nil {
+		defer machoFile.Close()
+		return []string{machoArchName(machoFile.Cpu)}, nil
+	}
+
+	if fatFile, err := macho.OpenFat(binaryPath); err == nil {
+		defer fatFile.Close()
+		arches := make([]string, 0, len(fatFile.Arches))
+		for _, fatArch := range fatFile.Arches {
+			arches = append(arches, machoArchName(fatArch.Cpu))
+		}
+		return arches, nil
+	}
+	return nil, errUnknownBinaryFormat
+}
+
+// machoArchName returns Go architecture name for the Mach-O CPU type.
+func machoArchName(cpu macho.Cpu) string {
+	if arch, found := machoArches[cpu]; found {
+		return arch
+	}
+	return cpu.String()
+}
+
+// checkBinaryArch checks the binary to be packed is built for the target architecture.
+// The check is skipped with a warning if the binary format is unknown.
+func checkBinaryArch(binaryPath, targetArch string) error {
+	arches, err := getBinaryArches(binaryPath)
+	if err != nil {
+		if errors.Is(err, errUnknownBinaryFormat) {
+			log.Warnf("Cannot detect architecture of %s: %s. Skip the check.", binaryPath, err)
+			return nil
+		}
+		return err
+	}
+	for _, arch := range arches {
+		if arch == targetArch {
+			return nil
+		}
+	}
+	return fmt.Errorf("binary %s architecture mismatch: expected %s, actual %s", binaryPath,
+		targetArch, strings.Join(arches, ", "))
+}
@@ -0,0 +1,79 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_normalizeArch(t *testing.T) {
+	tests := []struct {
+		arch     string
+		expected string
+		err      string
+	}{
+		{"", runtime.GOARCH, ""},
+		{"amd64", "amd64", ""},
+		{"x86_64", "amd64", ""},
+		{"AArch64", "arm64", ""},
+		{"ppc64le", "ppc64le", ""},
+		{"sparc", "", `unsupported target architecture "sparc"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arch, func(t *testing.T) {
+			arch, err := normalizeArch(tt.arch)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, arch)
+		})
+	}
+}
+
+func Test_checkPackageArch(t *testing.T) {
+	assert.NoError(t, checkPackageArch("Deb", "arm64", debArches))
+	assert.NoError(t, checkPackageArch("Deb", "all", debArches))
+	assert.NoError(t, checkPackageArch("RPM", "noarch", rpmArches))
+	assert.EqualError(t, checkPackageArch("RPM", "arm64", rpmArches),
+		`unsupported RPM package architecture "arm64", expected one of: x86_64, aarch64, `+
+			"i386, i686, armv7hl, ppc64le, ppc64, riscv64, s390x, noarch")
+}
+
+func Test_getDebArch(t *testing.T) {
+	assert.Equal(t, runtime.GOARCH, getDebArch(&PackCtx{}))
+	assert.Equal(t, "all", getDebArch(&PackCtx{RpmDeb: RpmDebCtx{DebArch: "all"}}))
+
+	suffix, err := getDebSuffix(&PackCtx{RpmDeb: RpmDebCtx{DebArch: "arm64"}})
+	require.NoError(t, err)
+	assert.Equal(t, "-1_arm64.deb", suffix)
+}
+
+func Test_checkBinaryArch(t *testing.T) {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	arches, err := getBinaryArches(executable)
+	require.NoError(t, err)
+	assert.Equal(t, []string{runtime.GOARCH}, arches)
+
+	assert.NoError(t, checkBinaryArch(executable, runtime.GOARCH))
+
+	otherArch := "arm64"
+	if runtime.GOARCH == otherArch {
+		otherArch = "amd64"
+	}
+	assert.EqualError(t, checkBinaryArch(executable, otherArch),
+		"binary "+executable+" architecture mismatch: expected "+otherArch+", actual "+
+			runtime.GOARCH)
+
+	// Unknown format is not checked.
+	scriptPath := filepath.Join(t.TempDir(), "tarantool")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\n"), 0755))
+	assert.NoError(t, checkBinaryArch(scriptPath, otherArch))
+}
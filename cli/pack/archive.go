@@ -2,6 +2,7 @@ package pack
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,16 @@ import (
 // archivePacker is a structure that implements Packer interface
 // with specific archive packing behavior.
 type archivePacker struct {
+	// writer is a writer to write the tarball to. The tarball is written to the package
+	// file in the output directory if it is not set.
+	writer io.Writer
+}
+
+// NewTgzPacker creates a packer writing the tarball to the passed writer instead of
+// the package file.
+func NewTgzPacker(writer io.Writer, packCtx *PackCtx) Packer {
+	packCtx.Type = Tgz
+	return &archivePacker{writer: writer}
 }
 
 // Run of ArchivePacker packs the bundle into tarball.
@@ -34,11 +45,15 @@ func (packer *archivePacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 
 	log.Debugf("The package structure is created in: %s", bundlePath)
 
-	tgzSuffix, err := getTgzSuffix()
+	compressor, err := getCompressor(packCtx.Archive.Compressor)
+	if err != nil {
+		return err
+	}
+	tarSuffix, err := getTarballSuffix(compressor.ext)
 	if err != nil {
 		return err
 	}
-	tarName, err := getPackageFileName(packCtx, opts, tgzSuffix, true)
+	tarName, err := getPackageFileName(packCtx, opts, tarSuffix, true)
 	if err != nil {
 		return err
 	}
@@ -55,22 +70,80 @@ func (packer *archivePacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		}
 	}
 
+	if packCtx.Archive.BaseTgz != "" {
+		if err = applyBaseTgz(packCtx, bundlePath); err != nil {
+			return err
+		}
+	}
+
+	if bundlePath, err = applyBundleRoot(packCtx, opts, bundlePath); err != nil {
+		return err
+	}
+
 	log.Infof("Creating tarball.")
 
-	currentDir, err := os.Getwd()
-	if err != nil {
+	if packer.writer != nil {
+		if err = writeCompressedTar(bundlePath, packer.writer, packCtx); err != nil {
+			return err
+		}
+		packCtx.progress.done()
+		log.Infof("Bundle is packed successfully.")
+		if packCtx.WithChecksum {
+			WarnIgnored(packCtx,
+				"Checksum file is not written for the tarball written to a stream.")
+		}
+		if packCtx.Archive.DeltaAgainst != "" {
+			WarnIgnored(packCtx,
+				"Delta is not written for the tarball written to a stream.")
+		}
+		if packCtx.Archive.SplitSize != "" {
+			WarnIgnored(packCtx,
+				"Tarball written to a stream is not split into volumes.")
+		}
+		if packCtx.SbomArtifact {
+			WarnIgnored(packCtx,
+				"SBOM file is not written for the tarball written to a stream.")
+		}
+		return nil
+	}
+
+	if tarName, err = getPackageFilePath(packCtx, tarName); err != nil {
+		return err
+	}
+	if err = lockArtifact(packCtx, tarName); err != nil {
 		return err
 	}
-	tarName = filepath.Join(currentDir, tarName)
 
-	err = writeTgzArchive(bundlePath, tarName, *packCtx)
+	if packCtx.Archive.splitSize > 0 {
+		packCtx.Archive.splitTarballPath = tarName
+	}
+	err = writePackageFile(packCtx, tarName, func(tmpPath string) error {
+		return writeTarballFile(bundlePath, tmpPath, packCtx)
+	})
 	if err != nil {
-		if err := os.Remove(tarName); err != nil {
-			log.Warnf("Failed to remove a tarball file %s: %s", tarName, err)
+		return err
+	}
+	packCtx.progress.done()
+	packCtx.artifactPath = tarName
+	// The split tarball is removed, so the volumes are reported after splitting.
+	if packCtx.Archive.splitSize == 0 {
+		log.Infof("Bundle is packed successfully to %s.", tarName)
+	}
+
+	if packCtx.Archive.DeltaAgainst != "" {
+		if _, err = writeDeltaFile(packCtx, tarName); err != nil {
+			return err
+		}
+	}
+	if packCtx.WithChecksum {
+		if err = writeChecksumFile(tarName); err != nil {
+			return err
 		}
+	}
+	if packCtx.Archive.splitSize > 0 {
+		packCtx.artifactPath, packCtx.Archive.volumes, err = splitTarball(packCtx, tarName)
 		return err
 	}
-	log.Infof("Bundle is packed successfully to %s.", tarName)
 	return nil
 }
 
@@ -140,12 +213,17 @@ func generateVersionFile(bundlePath string, cmdCtx *cmdcontext.CmdCtx, packCtx *
 	return nil
 }
 
-// getTgzSuffix returns suffix for a tarball.
+// getTgzSuffix returns suffix for a gzip tarball.
 func getTgzSuffix() (string, error) {
+	return getTarballSuffix("gz")
+}
+
+// getTarballSuffix returns suffix for a tarball compressed by the compressor with
+// the passed file name extension.
+func getTarballSuffix(ext string) (string, error) {
 	arch, err := util.GetArch()
 	if err != nil {
 		return "", err
 	}
-	tgzSuffix := strings.Join([]string{"", arch, "tar", "gz"}, ".")
-	return tgzSuffix, nil
+	return strings.Join([]string{"", arch, "tar", ext}, "."), nil
 }
@@ -1,10 +1,17 @@
 package pack
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/util"
 )
@@ -91,3 +98,63 @@ func TestGetTarPackageName(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTgzPacker(t *testing.T) {
+	sourceDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "app", "init.lua"),
+		[]byte("print(1)"), 0644))
+	outputDir := t.TempDir()
+
+	var buf bytes.Buffer
+	packCtx := PackCtx{Name: "bundle", SourceDir: sourceDir, OutputDir: outputDir,
+		Archive: ArchiveCtx{CompressionLevel: DefaultCompressionLevel}}
+	packer := NewTgzPacker(&buf, &packCtx)
+	assert.Equal(t, Tgz, packCtx.Type)
+	require.NoError(t, packer.Run(&cmdcontext.CmdCtx{}, &packCtx, &config.CliOpts{
+		Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"},
+	}))
+
+	gzipReader, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	files := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	assert.Equal(t, "print(1)", files["app/init.lua"])
+
+	// Nothing is written to the output directory.
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestPack(t *testing.T) {
+	arch, err := util.GetArch()
+	require.NoError(t, err)
+	sourceDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "app", "init.lua"),
+		[]byte("print(1)"), 0644))
+	outputDir := t.TempDir()
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+
+	packCtx := PackCtx{Type: Tgz, Name: "bundle", Version: "1.0.0", SourceDir: sourceDir,
+		OutputDir: outputDir, Archive: ArchiveCtx{CompressionLevel: DefaultCompressionLevel}}
+	packagePath, err := Pack(&cmdcontext.CmdCtx{}, &packCtx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(outputDir, "bundle-1.0.0."+arch+".tar.gz"), packagePath)
+	assert.FileExists(t, packagePath)
+
+	packagePath, err = Pack(&cmdcontext.CmdCtx{}, &PackCtx{Type: "exe"}, opts)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.Empty(t, packagePath)
+}
@@ -0,0 +1,148 @@
+package pack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+)
+
+// initBaseTgz checks the base tarball to layer the package onto.
+func initBaseTgz(packCtx *PackCtx) error {
+	baseTgz, err := filepath.Abs(packCtx.Archive.BaseTgz)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of the base tarball %q: %s",
+			packCtx.Archive.BaseTgz, err)
+	}
+	stat, err := os.Stat(baseTgz)
+	if err != nil {
+		return fmt.Errorf("cannot use the base tarball %q: %s", packCtx.Archive.BaseTgz, err)
+	}
+	if !stat.Mode().IsRegular() {
+		return fmt.Errorf("cannot use the base tarball %q: not a regular file",
+			packCtx.Archive.BaseTgz)
+	}
+	packCtx.Archive.BaseTgz = baseTgz
+	return nil
+}
+
+// overlayBaseTgz extracts the base tarball into the bundle directory. The bundle content
+// is an overlay on top of the base tarball: the base entries conflicting with the bundle
+// paths are skipped. Returns the relative paths of the overridden base entries.
+func overlayBaseTgz(baseTgz, bundlePath string) ([]string, error) {
+	archive, err := os.Open(baseTgz)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the base tarball %q: %s", baseTgz, err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	// extracted are the entries created from the base tarball. They may be overwritten
+	// by the following entries of the tarball.
+	extracted := map[string]bool{}
+	overridden := []string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read the base tarball %q: %s", baseTgz, err)
+		}
+
+		relPath := filepath.Clean(header.Name)
+		if relPath == "." {
+			continue
+		}
+		dstPath := filepath.Join(bundlePath, relPath)
+		if filepath.IsAbs(relPath) || !isSubPath(bundlePath, dstPath) {
+			return nil, fmt.Errorf("base tarball entry %q points outside of the package",
+				header.Name)
+		}
+		if !extracted[relPath] &&
+			isOverlaidPath(bundlePath, relPath, header.Typeflag == tar.TypeDir) {
+			overridden = append(overridden, relPath)
+			continue
+		}
+		if err = extractTarEntry(tarReader, header, dstPath); err != nil {
+			return nil, fmt.Errorf("failed to extract %q from the base tarball: %s",
+				header.Name, err)
+		}
+		extracted[relPath] = true
+	}
+	return overridden, nil
+}
+
+// isOverlaidPath returns true if the base tarball entry conflicts with the bundle
+// content: the entry path or any of its parent directories exists in the bundle and is
+// not a directory. Directories existing in both are merged.
+func isOverlaidPath(bundlePath, relPath string, isDir bool) bool {
+	parts := strings.Split(relPath, string(filepath.Separator))
+	path := bundlePath
+	for i, part := range parts {
+		path = filepath.Join(path, part)
+		stat, err := os.Lstat(path)
+		if err != nil {
+			return false
+		}
+		if !stat.IsDir() || (i == len(parts)-1 && !isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractTarEntry creates the tarball entry at the destination path.
+func extractTarEntry(reader io.Reader, header *tar.Header, dstPath string) error {
+	if header.Typeflag != tar.TypeDir {
+		if err := os.MkdirAll(filepath.Dir(dstPath), dirPermissions); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(dstPath); err != nil {
+			return err
+		}
+	}
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(dstPath, header.FileInfo().Mode().Perm())
+	case tar.TypeReg:
+		file, err := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC,
+			header.FileInfo().Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(file, reader); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	case tar.TypeSymlink:
+		return os.Symlink(header.Linkname, dstPath)
+	default:
+		return fmt.Errorf("unsupported entry type %q", string(header.Typeflag))
+	}
+}
+
+// applyBaseTgz layers the bundle onto the base tarball content and logs the overridden
+// base files.
+func applyBaseTgz(packCtx *PackCtx, bundlePath string) error {
+	log.Infof("Layering the package onto the base tarball %s", packCtx.Archive.BaseTgz)
+	overridden, err := overlayBaseTgz(packCtx.Archive.BaseTgz, bundlePath)
+	if err != nil {
+		return err
+	}
+	if len(overridden) > 0 {
+		log.Infof("Base tarball entries overridden by the package (%d): %s",
+			len(overridden), strings.Join(overridden, ", "))
+	}
+	return nil
+}
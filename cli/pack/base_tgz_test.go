@@ -0,0 +1,108 @@
+package pack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestTgz writes the tarball with the passed entries. Entries with names ending
+// with "/" are directories, entries with values starting with "->" are symlinks.
+func writeTestTgz(t *testing.T, entries [][2]string) string {
+	tgzPath := filepath.Join(t.TempDir(), "base.tar.gz")
+	file, err := os.Create(tgzPath)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		name, content := entry[0], entry[1]
+		header := tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeReg,
+			Size: int64(len(content))}
+		if name[len(name)-1] == '/' {
+			header = tar.Header{Name: name, Mode: 0755, Typeflag: tar.TypeDir}
+		} else if len(content) > 2 && content[:2] == "->" {
+			header = tar.Header{Name: name, Typeflag: tar.TypeSymlink, Linkname: content[2:]}
+		}
+		require.NoError(t, tarWriter.WriteHeader(&header))
+		if header.Typeflag == tar.TypeReg {
+			_, err = tarWriter.Write([]byte(content))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return tgzPath
+}
+
+func Test_overlayBaseTgz(t *testing.T) {
+	baseTgz := writeTestTgz(t, [][2]string{
+		{"bin/", ""},
+		{"bin/tarantool", "base tarantool"},
+		{"common/", ""},
+		{"common/lib.lua", "base lib"},
+		{"app/", ""},
+		{"app/init.lua", "base app"},
+		{"app/old.lua", "old"},
+		{"tt.yaml", "base config"},
+		{"instances.enabled/", ""},
+		{"instances.enabled/common", "->../common"},
+		{"instances.enabled/app/", ""},
+		{"instances.enabled/app/init.lua", "base link content"},
+	})
+
+	bundleDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "app"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "instances.enabled"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "app", "init.lua"),
+		[]byte("overlay app"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "tt.yaml"),
+		[]byte("overlay config"), 0644))
+	require.NoError(t, os.Symlink("../app", filepath.Join(bundleDir, "instances.enabled",
+		"app")))
+
+	overridden, err := overlayBaseTgz(baseTgz, bundleDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app/init.lua", "tt.yaml", "instances.enabled/app",
+		"instances.enabled/app/init.lua"}, overridden)
+
+	for path, expected := range map[string]string{
+		"bin/tarantool":                    "base tarantool",
+		"common/lib.lua":                   "base lib",
+		"app/init.lua":                     "overlay app",
+		"app/old.lua":                      "old",
+		"tt.yaml":                          "overlay config",
+		"instances.enabled/common/lib.lua": "base lib",
+	} {
+		content, err := os.ReadFile(filepath.Join(bundleDir, path))
+		require.NoError(t, err, path)
+		assert.Equal(t, expected, string(content), path)
+	}
+	target, err := os.Readlink(filepath.Join(bundleDir, "instances.enabled", "app"))
+	require.NoError(t, err)
+	assert.Equal(t, "../app", target)
+}
+
+func Test_overlayBaseTgzOutsideEntry(t *testing.T) {
+	baseTgz := writeTestTgz(t, [][2]string{{"../evil.lua", "evil"}})
+	_, err := overlayBaseTgz(baseTgz, t.TempDir())
+	assert.EqualError(t, err, `base tarball entry "../evil.lua" points outside of the package`)
+}
+
+func Test_initBaseTgz(t *testing.T) {
+	packCtx := PackCtx{Archive: ArchiveCtx{BaseTgz: t.TempDir()}}
+	assert.ErrorContains(t, initBaseTgz(&packCtx), "not a regular file")
+
+	packCtx.Archive.BaseTgz = filepath.Join(t.TempDir(), "missing.tar.gz")
+	assert.ErrorContains(t, initBaseTgz(&packCtx), "cannot use the base tarball")
+
+	baseTgz := writeTestTgz(t, nil)
+	packCtx.Archive.BaseTgz = baseTgz
+	require.NoError(t, initBaseTgz(&packCtx))
+	assert.Equal(t, baseTgz, packCtx.Archive.BaseTgz)
+}
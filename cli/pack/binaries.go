@@ -0,0 +1,48 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tarantool/tt/cli/cmdcontext"
+)
+
+// checkExecutable returns the absolute path of the executable file. An error is
+// returned if the file does not exist, is not a regular file or is not executable.
+func checkExecutable(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%q is not a regular file", absPath)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		return "", fmt.Errorf("%q is not executable", absPath)
+	}
+	return absPath, nil
+}
+
+// initExplicitBinaries checks the explicitly set tarantool and tt binaries. The tarantool
+// binary replaces the environment one, so the version resolution is skipped.
+func initExplicitBinaries(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx) error {
+	var err error
+	if packCtx.TarantoolBinary != "" {
+		if packCtx.TarantoolBinary, err = checkExecutable(packCtx.TarantoolBinary); err != nil {
+			return &binaryError{fmt.Errorf("invalid tarantool binary: %s", err)}
+		}
+		cmdCtx.Cli.TarantoolCli = cmdcontext.TarantoolCli{Executable: packCtx.TarantoolBinary}
+		cmdCtx.Cli.IsSystem = false
+	}
+	if packCtx.TtBinary != "" {
+		if packCtx.TtBinary, err = checkExecutable(packCtx.TtBinary); err != nil {
+			return &binaryError{fmt.Errorf("invalid tt binary: %s", err)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package pack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tarantool/tt/cli/util"
+)
+
+// lockedBinaries are the names of the binaries which checksums can be locked.
+var lockedBinaries = []string{"tarantool", "tt"}
+
+// loadBinariesLock reads the binaries lock file. The file has sha256sum format: each line
+// contains a hex-encoded SHA256 digest and a binary name separated by spaces. Empty lines
+// and lines starting with # are skipped. Returns the digests by the binary names.
+func loadBinariesLock(lockPath string) (map[string]string, error) {
+	file, err := os.Open(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read binaries lock file %q: %s", lockPath, err)
+	}
+	defer file.Close()
+
+	digests := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			return nil, fmt.Errorf("invalid binaries lock file %q line %d: "+
+				"expected <sha256> <binary name>", lockPath, lineNum)
+		}
+		// sha256sum marks the files read in binary mode with asterisk.
+		name := strings.TrimPrefix(fields[1], "*")
+		if util.Find(lockedBinaries, name) == -1 {
+			return nil, fmt.Errorf("invalid binaries lock file %q line %d: unknown binary "+
+				"%q, expected one of: %s", lockPath, lineNum, name,
+				strings.Join(lockedBinaries, ", "))
+		}
+		if _, found := digests[name]; found {
+			return nil, fmt.Errorf("invalid binaries lock file %q line %d: duplicate "+
+				"binary %q", lockPath, lineNum, name)
+		}
+		digests[name] = strings.ToLower(fields[0])
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read binaries lock file %q: %s", lockPath, err)
+	}
+	return digests, nil
+}
+
+// verifyLockedBinary checks the binary to include into the package matches the checksum
+// from the binaries lock file. Binaries are not checked if the lock file is not set.
+func verifyLockedBinary(packCtx *PackCtx, name, binaryPath string) error {
+	if packCtx.binariesLock == nil {
+		return nil
+	}
+	expected, found := packCtx.binariesLock[name]
+	if !found {
+		return fmt.Errorf("binaries lock file %q does not contain %s checksum",
+			packCtx.BinariesLock, name)
+	}
+	actual, err := util.FileSHA256Hex(binaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to compute checksum of %q: %s", binaryPath, err)
+	}
+	if actual != expected {
+		return fmt.Errorf("%s binary %q does not match the binaries lock file: "+
+			"expected sha256 %s, actual %s", name, binaryPath, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emptyFileSHA256 is a SHA256 digest of the empty content.
+const emptyFileSHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func Test_loadBinariesLock(t *testing.T) {
+	ttDigest := strings.Repeat("A", 64)
+	tests := []struct {
+		name        string
+		content     string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			content: "# Binaries.\n\n" + emptyFileSHA256 + "  tarantool\n" +
+				ttDigest + " *tt\n",
+			expected: map[string]string{
+				"tarantool": emptyFileSHA256,
+				"tt":        strings.ToLower(ttDigest),
+			},
+		},
+		{
+			name:     "tt only",
+			content:  ttDigest + "  tt\n",
+			expected: map[string]string{"tt": strings.ToLower(ttDigest)},
+		},
+		{
+			name:        "invalid digest",
+			content:     "abc  tt\n",
+			expectedErr: "line 1: expected <sha256> <binary name>",
+		},
+		{
+			name:        "unknown binary",
+			content:     emptyFileSHA256 + "  tarantool\n" + ttDigest + "  cartridge\n",
+			expectedErr: `line 2: unknown binary "cartridge", expected one of: tarantool, tt`,
+		},
+		{
+			name:        "duplicate",
+			content:     ttDigest + "  tt\n" + emptyFileSHA256 + "  tt\n",
+			expectedErr: `line 2: duplicate binary "tt"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockPath := filepath.Join(t.TempDir(), "binaries.lock")
+			require.NoError(t, os.WriteFile(lockPath, []byte(tt.content), 0644))
+			digests, err := loadBinariesLock(lockPath)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, digests)
+		})
+	}
+
+	_, err := loadBinariesLock(filepath.Join(t.TempDir(), "missing.lock"))
+	assert.ErrorContains(t, err, "cannot read binaries lock file")
+}
+
+func Test_verifyLockedBinary(t *testing.T) {
+	binPath := filepath.Join(t.TempDir(), "tarantool")
+	require.NoError(t, os.WriteFile(binPath, nil, 0755))
+
+	packCtx := PackCtx{}
+	assert.NoError(t, verifyLockedBinary(&packCtx, "tarantool", binPath))
+
+	packCtx.BinariesLock = "binaries.lock"
+	packCtx.binariesLock = map[string]string{"tarantool": emptyFileSHA256}
+	assert.NoError(t, verifyLockedBinary(&packCtx, "tarantool", binPath))
+	assert.EqualError(t, verifyLockedBinary(&packCtx, "tt", binPath),
+		`binaries lock file "binaries.lock" does not contain tt checksum`)
+
+	require.NoError(t, os.WriteFile(binPath, []byte("tarantool"), 0755))
+	err := verifyLockedBinary(&packCtx, "tarantool", binPath)
+	assert.ErrorContains(t, err, "does not match the binaries lock file: expected sha256 "+
+		emptyFileSHA256+", actual ")
+}
@@ -0,0 +1,48 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+)
+
+func Test_initExplicitBinaries(t *testing.T) {
+	binDir := t.TempDir()
+	tarantoolPath := filepath.Join(binDir, "tarantool")
+	require.NoError(t, os.WriteFile(tarantoolPath, []byte("#!/bin/sh"), 0755))
+	ttPath := filepath.Join(binDir, "tt")
+	require.NoError(t, os.WriteFile(ttPath, []byte("#!/bin/sh"), 0644))
+
+	cmdCtx := &cmdcontext.CmdCtx{}
+	cmdCtx.Cli.IsSystem = true
+	packCtx := &PackCtx{TarantoolBinary: tarantoolPath}
+	require.NoError(t, initExplicitBinaries(cmdCtx, packCtx))
+	assert.Equal(t, tarantoolPath, cmdCtx.Cli.TarantoolCli.Executable)
+	assert.False(t, cmdCtx.Cli.IsSystem)
+
+	packCtx = &PackCtx{TtBinary: ttPath}
+	assert.EqualError(t, initExplicitBinaries(cmdCtx, packCtx),
+		`invalid tt binary: "`+ttPath+`" is not executable`)
+
+	packCtx = &PackCtx{TtBinary: binDir}
+	assert.EqualError(t, initExplicitBinaries(cmdCtx, packCtx),
+		`invalid tt binary: "`+binDir+`" is not a regular file`)
+
+	packCtx = &PackCtx{TarantoolBinary: filepath.Join(binDir, "missing")}
+	err := initExplicitBinaries(cmdCtx, packCtx)
+	assert.ErrorContains(t, err, "invalid tarantool binary: stat")
+	assert.ErrorIs(t, err, ErrBinaryNotFound)
+
+	// Relative paths are resolved.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(binDir))
+	defer os.Chdir(wd)
+	packCtx = &PackCtx{TarantoolBinary: "tarantool"}
+	require.NoError(t, initExplicitBinaries(cmdCtx, packCtx))
+	assert.Equal(t, tarantoolPath, packCtx.TarantoolBinary)
+}
@@ -0,0 +1,57 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tarantool/tt/cli/config"
+)
+
+// checkBundleRoot checks the bundle root is a plain directory name.
+func checkBundleRoot(bundleRoot string) error {
+	if bundleRoot == "." || bundleRoot == ".." || strings.ContainsAny(bundleRoot, `/\`) {
+		return fmt.Errorf("invalid bundle root %q: must be a directory name without path"+
+			" separators", bundleRoot)
+	}
+	return nil
+}
+
+// applyBundleRoot places the bundle content into the bundle root directory. The bundle
+// directory named after the package is renamed if the content is already placed into it.
+// Returns the new bundle path.
+func applyBundleRoot(packCtx *PackCtx, opts *config.CliOpts, bundlePath string) (string,
+	error) {
+	bundleRoot := packCtx.Archive.BundleRoot
+	if bundleRoot == "" {
+		return bundlePath, nil
+	}
+	if opts.Env.InstancesEnabled == "." || packCtx.CartridgeCompat {
+		if bundleRoot != packCtx.Name {
+			if err := os.Rename(filepath.Join(bundlePath, packCtx.Name),
+				filepath.Join(bundlePath, bundleRoot)); err != nil {
+				return bundlePath, fmt.Errorf("cannot rename bundle root: %s", err)
+			}
+		}
+		return bundlePath, nil
+	}
+
+	parentDir, err := os.MkdirTemp(getTmpDir(packCtx), "tt_pack_root")
+	if err != nil {
+		return bundlePath, err
+	}
+	packCtx.operation.addOutput(parentDir)
+	rootPath := filepath.Join(parentDir, bundleRoot)
+	if err = os.Rename(bundlePath, rootPath); err != nil {
+		os.RemoveAll(parentDir)
+		return bundlePath, fmt.Errorf("cannot move bundle to root directory %q: %s",
+			bundleRoot, err)
+	}
+	// The bundle temporary directory is accessible by the owner only. The root directory
+	// must be readable by everyone, so the service user can run the extracted bundle.
+	if err = os.Chmod(rootPath, normalizedExecMode); err != nil {
+		return parentDir, fmt.Errorf("cannot set bundle root directory mode: %s", err)
+	}
+	return parentDir, nil
+}
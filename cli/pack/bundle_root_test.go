@@ -0,0 +1,107 @@
+package pack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_checkBundleRoot(t *testing.T) {
+	assert.NoError(t, checkBundleRoot("app"))
+	for _, bundleRoot := range []string{".", "..", "app/v1", `app\v1`, "/app"} {
+		assert.EqualError(t, checkBundleRoot(bundleRoot), fmt.Sprintf("invalid bundle root %q:"+
+			" must be a directory name without path separators", bundleRoot))
+	}
+}
+
+func Test_applyBundleRoot(t *testing.T) {
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	bundlePath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "tt.yaml"), nil, 0644))
+
+	packCtx := PackCtx{Name: "myapp"}
+	newPath, err := applyBundleRoot(&packCtx, opts, bundlePath)
+	require.NoError(t, err)
+	assert.Equal(t, bundlePath, newPath)
+
+	packCtx.Archive.BundleRoot = "app"
+	newPath, err = applyBundleRoot(&packCtx, opts, bundlePath)
+	require.NoError(t, err)
+	defer os.RemoveAll(newPath)
+	assert.FileExists(t, filepath.Join(newPath, "app", "tt.yaml"))
+	assert.NoDirExists(t, bundlePath)
+}
+
+func Test_applyBundleRootNamedBundle(t *testing.T) {
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "."}}
+	bundlePath := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(bundlePath, "myapp"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "myapp", "init.lua"), nil, 0644))
+
+	packCtx := PackCtx{Name: "myapp", Archive: ArchiveCtx{BundleRoot: "app"}}
+	newPath, err := applyBundleRoot(&packCtx, opts, bundlePath)
+	require.NoError(t, err)
+	assert.Equal(t, bundlePath, newPath)
+	assert.FileExists(t, filepath.Join(bundlePath, "app", "init.lua"))
+	assert.NoDirExists(t, filepath.Join(bundlePath, "myapp"))
+}
+
+func TestPackBundleRootMode(t *testing.T) {
+	sourceDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "app", "init.lua"),
+		[]byte("print(1)"), 0644))
+	// The bundle directory is copied with the source mode, make it a private one as
+	// the temporary bundle directory is.
+	require.NoError(t, os.Chmod(sourceDir, 0700))
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+
+	for _, packageType := range []string{Tgz, Zip} {
+		// modes are the modes of the archive entries by the slash-separated path.
+		modes := map[string]os.FileMode{}
+		packCtx := PackCtx{Type: packageType, Name: "bundle", Version: "1.0.0",
+			SourceDir: sourceDir, OutputDir: t.TempDir(),
+			Archive: ArchiveCtx{CompressionLevel: DefaultCompressionLevel, BundleRoot: "root"}}
+		packagePath, err := Pack(&cmdcontext.CmdCtx{}, &packCtx, opts)
+		require.NoError(t, err)
+
+		if packageType == Zip {
+			reader, err := zip.OpenReader(packagePath)
+			require.NoError(t, err)
+			for _, file := range reader.File {
+				modes[strings.TrimSuffix(file.Name, "/")] = file.Mode()
+			}
+			reader.Close()
+		} else {
+			file, err := os.Open(packagePath)
+			require.NoError(t, err)
+			gzipReader, err := gzip.NewReader(file)
+			require.NoError(t, err)
+			tarReader := tar.NewReader(gzipReader)
+			for {
+				header, err := tarReader.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				modes[header.Name] = header.FileInfo().Mode()
+			}
+			file.Close()
+		}
+		// The bundle root is readable by everyone.
+		mode, found := modes["root"]
+		require.True(t, found, packageType)
+		assert.Equal(t, os.ModeDir|normalizedExecMode, mode, packageType)
+	}
+}
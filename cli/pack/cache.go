@@ -0,0 +1,240 @@
+package pack
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/otiai10/copy"
+	"github.com/tarantool/tt/cli/config"
+)
+
+// appsCacheDirName is a cache directory subdirectory containing applications mirrors.
+const appsCacheDirName = "apps"
+
+// appCacheLocks contains the mutexes of the application mirrors. Several application
+// entries may point to the same application source.
+var appCacheLocks sync.Map
+
+// initCacheDir creates the pack cache directory if it does not exist.
+func initCacheDir(packCtx *PackCtx) error {
+	cacheDir, err := filepath.Abs(packCtx.CacheDir)
+	if err != nil {
+		return fmt.Errorf("cannot get absolute path of cache directory %q: %s",
+			packCtx.CacheDir, err)
+	}
+	if err = os.MkdirAll(filepath.Join(cacheDir, appsCacheDirName), dirPermissions); err != nil {
+		return fmt.Errorf("cannot create cache directory %q: %s", cacheDir, err)
+	}
+	packCtx.CacheDir = cacheDir
+	return nil
+}
+
+// getAppCachePath returns the cache directory path of the application mirror. Mirror is
+// identified by the resolved application source path.
+func getAppCachePath(cacheDir, resolvedAppPath string) string {
+	hash := sha256.Sum256([]byte(resolvedAppPath))
+	return filepath.Join(cacheDir, appsCacheDirName, fmt.Sprintf("%x", hash))
+}
+
+// isCachedFileUpToDate returns true if the cached file has the same type, permissions,
+// size and modification time as the source file.
+func isCachedFileUpToDate(srcInfo, cachedInfo os.FileInfo) bool {
+	return srcInfo.Mode().IsRegular() && srcInfo.Mode() == cachedInfo.Mode() &&
+		srcInfo.Size() == cachedInfo.Size() && srcInfo.ModTime().Equal(cachedInfo.ModTime())
+}
+
+// canLinkCachedFiles returns true if the bundle application files are not changed in place
+// after copying, so they may be hard links to the mirror files. The permissions
+// normalization, modification times clamping, config overlay and cartridge-compat version
+// file change the bundle files, so the files are copied not to change the mirror.
+func canLinkCachedFiles(packCtx *PackCtx) bool {
+	return !packCtx.NormalizePermissions && packCtx.sourceDateEpoch == nil &&
+		packCtx.ConfigOverlay == "" && !packCtx.CartridgeCompat
+}
+
+// copyAppSrcCached copies the application source directory to the destination through
+// the application mirror in the cache directory. Only the files changed since the previous
+// pack are copied to the mirror, the mirror files are hard linked to the destination if
+// the bundle files are not changed later. The rocks are rebuilt in the bundle, so they are
+// always copied unless the rebuild is skipped.
+func copyAppSrcCached(ctx context.Context, packCtx *PackCtx, cliOpts *config.CliOpts,
+	srcAppPath, dstAppPath string) error {
+	resolvedAppPath, err := filepath.EvalSymlinks(srcAppPath)
+	if err != nil {
+		return err
+	}
+	cachePath := getAppCachePath(packCtx.CacheDir, resolvedAppPath)
+	lock, _ := appCacheLocks.LoadOrStore(cachePath, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	log.Debugf("Using application cache %q for %q", cachePath, resolvedAppPath)
+	if err = syncAppCache(ctx, packCtx, cliOpts, resolvedAppPath, cachePath); err != nil {
+		return fmt.Errorf("failed to update application cache %q: %s", cachePath, err)
+	}
+	link := func(relPath string) bool {
+		if !canLinkCachedFiles(packCtx) {
+			return false
+		}
+		return packCtx.NoRebuild || relPath != ".rocks" &&
+			!strings.HasPrefix(relPath, ".rocks"+string(filepath.Separator))
+	}
+	if err = linkOrCopyTree(cachePath, dstAppPath, link); err != nil {
+		return fmt.Errorf("failed to copy application from cache %q: %s", cachePath, err)
+	}
+	return nil
+}
+
+// syncAppCache updates the application mirror to match the application source. Up to date
+// files are not copied. The mirror directory modification time is set to the source one
+// after syncing, so the entries missing in the source are looked for only in the mirror
+// directories which modification time differs from the source one: removing an entry
+// changes the directory modification time.
+func syncAppCache(ctx context.Context, packCtx *PackCtx, cliOpts *config.CliOpts,
+	resolvedAppPath, cachePath string) error {
+	if info, err := os.Lstat(cachePath); err == nil && !info.IsDir() {
+		if err = os.RemoveAll(cachePath); err != nil {
+			return err
+		}
+	}
+	skipFunc, err := appSrcCopySkip(packCtx, cliOpts, resolvedAppPath)
+	if err != nil {
+		return err
+	}
+	rootInfo, err := os.Stat(resolvedAppPath)
+	if err != nil {
+		return err
+	}
+
+	// synced contains the mirror paths of the copied or up to date source entries.
+	synced := map[string]bool{cachePath: true}
+	// changedDirs contains the source modification times of the mirror directories
+	// which entries may differ from the source ones.
+	changedDirs := map[string]time.Time{cachePath: rootInfo.ModTime()}
+	copier := appSrcCopier{
+		ctx:                   ctx,
+		appPath:               resolvedAppPath,
+		dstAppPath:            cachePath,
+		preserveSymlinks:      isPreserveSymlinks(packCtx),
+		allowExternalSymlinks: packCtx.AllowExternalSymlinks,
+		preserveTimes:         true,
+		operation:             packCtx.operation,
+		skip: func(srcInfo os.FileInfo, src, dst string) (bool, error) {
+			skip, err := skipFunc(srcInfo, src, dst)
+			if err != nil {
+				return skip, err
+			}
+			cachedInfo, statErr := os.Lstat(dst)
+			if skip {
+				// The entry filtered out since the previous pack is removed from the mirror.
+				if statErr == nil {
+					return true, os.RemoveAll(dst)
+				}
+				return true, nil
+			}
+			synced[dst] = true
+			if statErr != nil {
+				return false, nil
+			}
+			if isCachedFileUpToDate(srcInfo, cachedInfo) {
+				return true, nil
+			}
+			if srcInfo.IsDir() && cachedInfo.IsDir() {
+				if !srcInfo.ModTime().Equal(cachedInfo.ModTime()) {
+					changedDirs[dst] = srcInfo.ModTime()
+				}
+				return false, nil
+			}
+			return false, os.RemoveAll(dst)
+		},
+	}
+	if err = copier.copy(resolvedAppPath, cachePath, nil); err != nil {
+		return err
+	}
+	return pruneCache(changedDirs, synced)
+}
+
+// pruneCache removes the entries of the changed mirror directories which are not synced
+// with the source and sets the directories modification time to the source one.
+func pruneCache(changedDirs map[string]time.Time, synced map[string]bool) error {
+	for dir, modTime := range changedDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if synced[path] {
+				continue
+			}
+			if err = os.RemoveAll(path); err != nil {
+				return err
+			}
+		}
+		if err = os.Chtimes(dir, modTime, modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkOrCopyTree creates the copy of the directory tree hard linking the regular files
+// for which link returns true. A file is copied if it cannot be linked. The directory
+// modes are set after the directories are filled, so the read-only directories are
+// copied too.
+func linkOrCopyTree(src, dst string, link func(relPath string) bool) error {
+	type dirMode struct {
+		path string
+		mode os.FileMode
+	}
+	var dirModes []dirMode
+	err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dst, relPath)
+		switch {
+		case entry.IsDir():
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			dirModes = append(dirModes, dirMode{dstPath, info.Mode().Perm()})
+			return os.MkdirAll(dstPath, dirPermissions)
+		case entry.Type() == os.ModeSymlink:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dstPath)
+		default:
+			if link(relPath) {
+				if err = os.Link(path, dstPath); err == nil {
+					return nil
+				}
+			}
+			return copy.Copy(path, dstPath, copy.Options{PreserveTimes: true})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(dirModes) - 1; i >= 0; i-- {
+		if err = os.Chmod(dirModes[i].path, dirModes[i].mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+)
+
+func Test_copyAppSrcCached(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{"lib", "tmp"}))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua", "lib/mod.lua",
+		"lib/old.lua", "tmp/file"}))
+	for _, file := range []string{"init.lua", "lib/mod.lua"} {
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, file), []byte("source"), 0644))
+	}
+	require.NoError(t, os.Symlink("mod.lua", filepath.Join(appDir, "lib", "link.lua")))
+
+	packCtx := PackCtx{CacheDir: t.TempDir(), Archive: ArchiveCtx{PreserveSymlinks: true}}
+	require.NoError(t, initCacheDir(&packCtx))
+	packApp := func() string {
+		dstDir := filepath.Join(t.TempDir(), "app")
+		require.NoError(t, copyAppSrcCached(context.Background(), &packCtx,
+			&config.CliOpts{}, appDir, dstDir))
+		return dstDir
+	}
+
+	dstDir := packApp()
+	for _, file := range []string{"init.lua", "lib/mod.lua", "lib/old.lua", "tmp/file"} {
+		assert.FileExists(t, filepath.Join(dstDir, file))
+	}
+	target, err := os.Readlink(filepath.Join(dstDir, "lib", "link.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, "mod.lua", target)
+
+	// Mark the cached files keeping their size and modification time to check up to date
+	// files are not copied.
+	resolvedAppDir, err := filepath.EvalSymlinks(appDir)
+	require.NoError(t, err)
+	cachePath := getAppCachePath(packCtx.CacheDir, resolvedAppDir)
+	mtime := time.Now().Add(-time.Hour)
+	for _, file := range []string{"init.lua", "lib/mod.lua"} {
+		require.NoError(t, os.Chtimes(filepath.Join(appDir, file), mtime, mtime))
+	}
+	packApp()
+	for _, file := range []string{"init.lua", "lib/mod.lua"} {
+		cachedFile := filepath.Join(cachePath, file)
+		require.NoError(t, os.WriteFile(cachedFile, []byte("cached"), 0644))
+		require.NoError(t, os.Chtimes(cachedFile, mtime, mtime))
+	}
+
+	// Change the size of one file and the modification time of another one.
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), []byte("changed"),
+		0664))
+	newMtime := mtime.Add(time.Minute)
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "lib", "new.lua"), []byte("new"),
+		0664))
+	require.NoError(t, os.Remove(filepath.Join(appDir, "lib", "old.lua")))
+	require.NoError(t, os.RemoveAll(filepath.Join(appDir, "tmp")))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "tmp"), []byte("file"), 0664))
+
+	dstDir = packApp()
+	checkContent := func(file, expected string) {
+		content, err := os.ReadFile(filepath.Join(dstDir, file))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content), file)
+	}
+	checkContent("init.lua", "changed")
+	checkContent("lib/mod.lua", "cached")
+	checkContent("lib/new.lua", "new")
+	checkContent("tmp", "file")
+	assert.NoFileExists(t, filepath.Join(dstDir, "lib", "old.lua"))
+	assert.NoFileExists(t, filepath.Join(cachePath, "lib", "old.lua"))
+
+	require.NoError(t, os.Chtimes(filepath.Join(appDir, "lib", "mod.lua"), newMtime, newMtime))
+	dstDir = packApp()
+	checkContent("lib/mod.lua", "source")
+}
+
+func Test_copyAppSrcCachedFilters(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{".rocks"}))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua",
+		".rocks/rock.lua"}))
+
+	packCtx := PackCtx{CacheDir: t.TempDir()}
+	require.NoError(t, initCacheDir(&packCtx))
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrcCached(context.Background(), &packCtx, &config.CliOpts{},
+		appDir, dstDir))
+	assert.FileExists(t, filepath.Join(dstDir, ".rocks", "rock.lua"))
+
+	// Filtered out files are removed from the cache.
+	packCtx.WithoutRocks = true
+	dstDir = filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrcCached(context.Background(), &packCtx, &config.CliOpts{},
+		appDir, dstDir))
+	assert.FileExists(t, filepath.Join(dstDir, "init.lua"))
+	assert.NoDirExists(t, filepath.Join(dstDir, ".rocks"))
+	resolvedAppDir, err := filepath.EvalSymlinks(appDir)
+	require.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(getAppCachePath(packCtx.CacheDir, resolvedAppDir),
+		".rocks"))
+}
+
+func Test_copyAppSrcCachedLinks(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{".rocks"}))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua",
+		".rocks/rock.lua"}))
+	resolvedAppDir, err := filepath.EvalSymlinks(appDir)
+	require.NoError(t, err)
+
+	packCtx := PackCtx{CacheDir: t.TempDir()}
+	require.NoError(t, initCacheDir(&packCtx))
+	cachePath := getAppCachePath(packCtx.CacheDir, resolvedAppDir)
+	isLinked := func(file string) bool {
+		dstDir := filepath.Join(t.TempDir(), "app")
+		require.NoError(t, copyAppSrcCached(context.Background(), &packCtx,
+			&config.CliOpts{}, appDir, dstDir))
+		dstInfo, err := os.Stat(filepath.Join(dstDir, file))
+		require.NoError(t, err)
+		cachedInfo, err := os.Stat(filepath.Join(cachePath, file))
+		require.NoError(t, err)
+		return os.SameFile(dstInfo, cachedInfo)
+	}
+
+	assert.True(t, isLinked("init.lua"))
+	// The rocks are rebuilt in the bundle.
+	assert.False(t, isLinked(".rocks/rock.lua"))
+	packCtx.NoRebuild = true
+	assert.True(t, isLinked(".rocks/rock.lua"))
+
+	// The bundle files are changed in place, so they are not linked.
+	packCtx.NormalizePermissions = true
+	assert.False(t, isLinked("init.lua"))
+}
+
+func Test_syncAppCacheUnchangedDirs(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{"lib"}))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua", "lib/mod.lua"}))
+	packCtx := PackCtx{CacheDir: t.TempDir()}
+	require.NoError(t, initCacheDir(&packCtx))
+	cachePath := filepath.Join(packCtx.CacheDir, "mirror")
+	require.NoError(t, syncAppCache(context.Background(), &packCtx, &config.CliOpts{},
+		appDir, cachePath))
+
+	// The mirror directory keeps the source modification time, so it is not read again.
+	libInfo, err := os.Stat(filepath.Join(appDir, "lib"))
+	require.NoError(t, err)
+	strayPath := filepath.Join(cachePath, "lib", "stray.lua")
+	require.NoError(t, os.WriteFile(strayPath, nil, 0644))
+	require.NoError(t, os.Chtimes(filepath.Join(cachePath, "lib"), libInfo.ModTime(),
+		libInfo.ModTime()))
+	require.NoError(t, syncAppCache(context.Background(), &packCtx, &config.CliOpts{},
+		appDir, cachePath))
+	assert.FileExists(t, strayPath)
+
+	// The removed source entry changes the directory modification time.
+	require.NoError(t, os.Remove(filepath.Join(appDir, "lib", "mod.lua")))
+	mtime := libInfo.ModTime().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(appDir, "lib"), mtime, mtime))
+	require.NoError(t, syncAppCache(context.Background(), &packCtx, &config.CliOpts{},
+		appDir, cachePath))
+	assert.NoFileExists(t, strayPath)
+	assert.NoFileExists(t, filepath.Join(cachePath, "lib", "mod.lua"))
+	cachedLibInfo, err := os.Stat(filepath.Join(cachePath, "lib"))
+	require.NoError(t, err)
+	assert.True(t, mtime.Equal(cachedLibInfo.ModTime()))
+}
+
+func Test_linkOrCopyTreeReadOnlyDir(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(srcDir, []string{"ro"}))
+	require.NoError(t, test_helpers.CreateFiles(srcDir, []string{"ro/file"}))
+	require.NoError(t, os.Chmod(filepath.Join(srcDir, "ro"), 0555))
+	dstDir := filepath.Join(t.TempDir(), "dst")
+	t.Cleanup(func() {
+		os.Chmod(filepath.Join(srcDir, "ro"), 0755)
+		os.Chmod(filepath.Join(dstDir, "ro"), 0755)
+	})
+
+	require.NoError(t, linkOrCopyTree(srcDir, dstDir, func(string) bool { return false }))
+	assert.FileExists(t, filepath.Join(dstDir, "ro", "file"))
+	info, err := os.Stat(filepath.Join(dstDir, "ro"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0555), info.Mode().Perm())
+}
@@ -0,0 +1,193 @@
+package pack
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// debChangelogFileName is a name of the changelog file installed by deb package.
+const debChangelogFileName = "changelog.Debian.gz"
+
+// rpmChangelogDateLayout is a date layout of RPM changelog entry header.
+const rpmChangelogDateLayout = "Mon Jan 2 2006"
+
+var (
+	// debChangelogHeaderRe matches the first line of debian changelog entry:
+	// package (version) distributions; urgency=urgency
+	debChangelogHeaderRe = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]* \([^ ()]+\)( [^ ;]+)+;` +
+		` .*urgency=\S+`)
+	// debChangelogTrailerRe matches the last line of debian changelog entry:
+	//  -- maintainer name <email address>  date
+	debChangelogTrailerRe = regexp.MustCompile(`^ -- (.+ <[^>]+>)  (.+)$`)
+)
+
+// changelogEntry is an entry of RPM changelog.
+type changelogEntry struct {
+	// time is the entry date.
+	time time.Time
+	// author is the entry author and version.
+	author string
+	// text is the list of changes.
+	text string
+}
+
+// parseRpmChangelog parses RPM spec %changelog section content.
+func parseRpmChangelog(content string) ([]changelogEntry, error) {
+	var entries []changelogEntry
+	var text []string
+	flush := func() {
+		if len(entries) > 0 {
+			entries[len(entries)-1].text = strings.TrimSpace(strings.Join(text, "\n"))
+		}
+		text = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if len(entries) == 0 && (line == "" || line == "%changelog") {
+			continue
+		}
+		if !strings.HasPrefix(line, "* ") {
+			if len(entries) == 0 {
+				return nil, fmt.Errorf("line %d: entry must start with \"* \"", lineNum)
+			}
+			text = append(text, line)
+			continue
+		}
+
+		flush()
+		fields := strings.Fields(line[2:])
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("line %d: entry header must contain the date and"+
+				" the author", lineNum)
+		}
+		entryTime, err := time.Parse(rpmChangelogDateLayout, strings.Join(fields[:4], " "))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date: %s", lineNum, err)
+		}
+		entries = append(entries, changelogEntry{
+			time:   entryTime,
+			author: strings.Join(fields[4:], " "),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no changelog entries found")
+	}
+	return entries, nil
+}
+
+// checkDebChangelog checks the content is in debian changelog format.
+func checkDebChangelog(content string) error {
+	entries := 0
+	inEntry := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+		case !inEntry:
+			if !debChangelogHeaderRe.MatchString(line) {
+				return fmt.Errorf("line %d: invalid entry header %q", lineNum, line)
+			}
+			inEntry = true
+		case strings.HasPrefix(line, " -- "):
+			matches := debChangelogTrailerRe.FindStringSubmatch(line)
+			if matches == nil {
+				return fmt.Errorf("line %d: invalid entry trailer %q", lineNum, line)
+			}
+			if _, err := time.Parse(time.RFC1123Z, matches[2]); err != nil {
+				return fmt.Errorf("line %d: invalid date: %s", lineNum, err)
+			}
+			inEntry = false
+			entries++
+		case !strings.HasPrefix(line, "  "):
+			return fmt.Errorf("line %d: change details must be indented with two spaces",
+				lineNum)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if inEntry {
+		return fmt.Errorf("the last entry has no trailer line")
+	}
+	if entries == 0 {
+		return fmt.Errorf("no changelog entries found")
+	}
+	return nil
+}
+
+// loadChangelog reads and checks the changelog file for the package type.
+func loadChangelog(packCtx *PackCtx) error {
+	content, err := os.ReadFile(packCtx.RpmDeb.Changelog)
+	if err != nil {
+		return fmt.Errorf("cannot read changelog: %s", err)
+	}
+	switch packCtx.Type {
+	case Rpm:
+		packCtx.RpmDeb.rpmChangelog, err = parseRpmChangelog(string(content))
+	case Deb:
+		err = checkDebChangelog(string(content))
+		packCtx.RpmDeb.debChangelog = string(content)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s changelog %q: %s", packCtx.Type,
+			packCtx.RpmDeb.Changelog, err)
+	}
+	return nil
+}
+
+// addChangelogRPM adds the changelog entries to the rpm header.
+func addChangelogRPM(rpmHeader *rpmTagSetType, entries []changelogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	times := make([]int32, 0, len(entries))
+	names := make([]string, 0, len(entries))
+	texts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		times = append(times, int32(entry.time.Unix()))
+		names = append(names, entry.author)
+		texts = append(texts, entry.text)
+	}
+	rpmHeader.addTags([]rpmTagType{
+		{ID: tagChangelogTime, Type: rpmTypeInt32, Value: times},
+		{ID: tagChangelogName, Type: rpmTypeStringArray, Value: names},
+		{ID: tagChangelogText, Type: rpmTypeStringArray, Value: texts},
+	}...)
+}
+
+// writeDebChangelog writes the compressed changelog into the package documentation
+// directory.
+func writeDebChangelog(packageDataDir, packageName, changelog string) error {
+	docDir := filepath.Join(packageDataDir, "usr", "share", "doc", packageName)
+	if err := os.MkdirAll(docDir, dirPermissions); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	gzipWriter, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err = gzipWriter.Write([]byte(changelog)); err != nil {
+		return err
+	}
+	if err = gzipWriter.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(docDir, debChangelogFileName), buf.Bytes(),
+		filePermissions)
+}
@@ -0,0 +1,114 @@
+package pack
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRpmChangelog = `%changelog
+* Tue Jan 02 2024 Packager <packager@example.com> - 1.1.0-1
+- Add feature.
+- Fix bug.
+
+* Mon Jan 1 2024 Packager <packager@example.com> - 1.0.0-1
+- Initial release.
+`
+
+const testDebChangelog = `app (1.1.0) stable; urgency=medium
+
+  * Add feature.
+  * Fix bug.
+
+ -- Packager <packager@example.com>  Tue, 02 Jan 2024 10:00:00 +0000
+
+app (1.0.0) stable; urgency=low
+
+  * Initial release.
+
+ -- Packager <packager@example.com>  Mon, 01 Jan 2024 10:00:00 +0000
+`
+
+func Test_parseRpmChangelog(t *testing.T) {
+	entries, err := parseRpmChangelog(testRpmChangelog)
+	require.NoError(t, err)
+	assert.Equal(t, []changelogEntry{
+		{
+			time:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			author: "Packager <packager@example.com> - 1.1.0-1",
+			text:   "- Add feature.\n- Fix bug.",
+		},
+		{
+			time:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			author: "Packager <packager@example.com> - 1.0.0-1",
+			text:   "- Initial release.",
+		},
+	}, entries)
+
+	for _, content := range []string{"", "- change\n", "* Jan 2024 Packager\n",
+		"* Tue Foo 02 2024 Packager\n", testDebChangelog} {
+		_, err = parseRpmChangelog(content)
+		assert.Error(t, err, content)
+	}
+}
+
+func Test_checkDebChangelog(t *testing.T) {
+	require.NoError(t, checkDebChangelog(testDebChangelog))
+
+	for _, content := range []string{
+		"",
+		testRpmChangelog,
+		"app (1.0.0) stable; urgency=low\n\n  * Initial release.\n",
+		"app (1.0.0) stable; urgency=low\n\n * Bad indent.\n\n" +
+			" -- Packager <packager@example.com>  Mon, 01 Jan 2024 10:00:00 +0000\n",
+		"app (1.0.0) stable; urgency=low\n\n  * Initial release.\n\n" +
+			" -- Packager <packager@example.com>  01.01.2024\n",
+	} {
+		assert.Error(t, checkDebChangelog(content), content)
+	}
+}
+
+func Test_loadChangelog(t *testing.T) {
+	changelogPath := filepath.Join(t.TempDir(), "changelog")
+	require.NoError(t, os.WriteFile(changelogPath, []byte(testDebChangelog), 0644))
+
+	packCtx := PackCtx{Type: Deb, RpmDeb: RpmDebCtx{Changelog: changelogPath}}
+	require.NoError(t, loadChangelog(&packCtx))
+	assert.Equal(t, testDebChangelog, packCtx.RpmDeb.debChangelog)
+
+	packCtx = PackCtx{Type: Rpm, RpmDeb: RpmDebCtx{Changelog: changelogPath}}
+	assert.ErrorContains(t, loadChangelog(&packCtx), "invalid rpm changelog")
+}
+
+func Test_writeDebChangelog(t *testing.T) {
+	dataDir := t.TempDir()
+	require.NoError(t, writeDebChangelog(dataDir, "app", testDebChangelog))
+
+	file, err := os.Open(filepath.Join(dataDir, "usr", "share", "doc", "app",
+		debChangelogFileName))
+	require.NoError(t, err)
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gzipReader)
+	require.NoError(t, err)
+	assert.Equal(t, testDebChangelog, string(content))
+}
+
+func Test_addChangelogRPM(t *testing.T) {
+	entries, err := parseRpmChangelog(testRpmChangelog)
+	require.NoError(t, err)
+
+	rpmHeader := rpmTagSetType{}
+	addChangelogRPM(&rpmHeader, entries)
+	require.Len(t, rpmHeader, 3)
+	assert.Equal(t, []int32{1704153600, 1704067200}, rpmHeader[0].Value)
+	assert.Equal(t, []string{"- Add feature.\n- Fix bug.", "- Initial release."},
+		rpmHeader[2].Value)
+}
@@ -0,0 +1,31 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// checksumFileSuffix is a suffix of the package checksum file name.
+const checksumFileSuffix = ".sha256"
+
+// writeChecksumFile writes SHA256 checksum of the package file in sha256sum format
+// to the file next to the package.
+func writeChecksumFile(packagePath string) error {
+	digest, err := util.FileSHA256Hex(packagePath)
+	if err != nil {
+		return fmt.Errorf("failed to compute checksum of %q: %s", packagePath, err)
+	}
+
+	checksumFilePath := packagePath + checksumFileSuffix
+	err = os.WriteFile(checksumFilePath,
+		[]byte(fmt.Sprintf("%s  %s\n", digest, filepath.Base(packagePath))), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write checksum file %q: %s", checksumFilePath, err)
+	}
+	log.Infof("Checksum is written to %s.", checksumFilePath)
+	return nil
+}
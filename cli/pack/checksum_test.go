@@ -0,0 +1,24 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeChecksumFile(t *testing.T) {
+	packagePath := filepath.Join(t.TempDir(), "bundle-1.0.0.x86_64.tar.gz")
+	require.NoError(t, os.WriteFile(packagePath, []byte("hello\n"), 0644))
+
+	require.NoError(t, writeChecksumFile(packagePath))
+	content, err := os.ReadFile(packagePath + checksumFileSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"+
+		"  bundle-1.0.0.x86_64.tar.gz\n", string(content))
+
+	assert.ErrorContains(t, writeChecksumFile(filepath.Join(t.TempDir(), "missing")),
+		"failed to compute checksum")
+}
@@ -0,0 +1,256 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// collectedFile is a file found in the directory to pack.
+type collectedFile struct {
+	// path is a full path of the file. It is set for the files of the OS directory.
+	path string
+	// name is a slash-separated path in the collected file system.
+	name string
+	// relPath is a path relative to the collection root.
+	relPath string
+	// info is a file info got by lstat.
+	info os.FileInfo
+}
+
+// getCollectedFilePath returns the full path of the OS directory file or the file system
+// name of the file otherwise.
+func getCollectedFilePath(file collectedFile) string {
+	if file.path != "" {
+		return file.path
+	}
+	return file.name
+}
+
+// fileCollector collects files of the directory tree using a fixed number of workers
+// reading the directories from a queue.
+type fileCollector struct {
+	fsys FileSystem
+	ctx  context.Context
+	// cancel stops the collection on the first error.
+	cancel context.CancelFunc
+
+	mutex sync.Mutex
+	// changed signals the workers waiting for the queued directories.
+	changed *sync.Cond
+	// queue contains the directories to read.
+	queue []string
+	// pending is a number of the queued directories and the directories being read.
+	pending int
+	files   []collectedFile
+	err     error
+}
+
+// getJobsCount returns the number of workers to use for the pack context.
+func getJobsCount(packCtx *PackCtx) int {
+	if packCtx.Jobs > 0 {
+		return packCtx.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// collectFiles returns all entries of the directory tree including the root itself.
+// Symlinks are not followed. The result is ordered the same way as filepath.Walk does,
+// regardless of the jobs count.
+func collectFiles(ctx context.Context, root string, jobs int) ([]collectedFile, error) {
+	files, err := collectFilesFS(ctx, NewOSFileSystem(root), jobs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range files {
+		files[i].path = filepath.Join(root, files[i].relPath)
+	}
+	return files, nil
+}
+
+// collectFilesFS returns all entries of the file system including the root itself.
+// Symlinks are not followed. The result is ordered the same way as filepath.Walk does,
+// regardless of the jobs count.
+func collectFilesFS(ctx context.Context, fsys FileSystem, jobs int) ([]collectedFile, error) {
+	rootInfo, err := fsys.Lstat(".")
+	if err != nil {
+		return nil, err
+	}
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	collector := fileCollector{fsys: fsys}
+	collector.changed = sync.NewCond(&collector.mutex)
+	collector.ctx, collector.cancel = context.WithCancel(ctx)
+	defer collector.cancel()
+
+	collector.files = append(collector.files, collectedFile{name: ".", relPath: ".",
+		info: rootInfo})
+	if rootInfo.IsDir() {
+		collector.queue = []string{"."}
+		collector.pending = 1
+		var wg sync.WaitGroup
+		for i := 0; i < jobs; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				collector.work()
+			}()
+		}
+		wg.Wait()
+	}
+	if collector.err != nil {
+		return nil, collector.err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(collector.files, func(i, j int) bool {
+		return walkOrderLess(collector.files[i].relPath, collector.files[j].relPath)
+	})
+	return collector.files, nil
+}
+
+// work reads the queued directories until all of them are read or the collection fails.
+func (collector *fileCollector) work() {
+	for {
+		dirName, found := collector.next()
+		if !found {
+			return
+		}
+		files, subDirs, err := collector.statDir(dirName)
+		collector.finish(files, subDirs, err)
+	}
+}
+
+// next returns the queued directory to read. The queue is waited for while other
+// directories are being read. False is returned if the collection is done or failed.
+func (collector *fileCollector) next() (string, bool) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	for len(collector.queue) == 0 && collector.pending > 0 && collector.err == nil {
+		collector.changed.Wait()
+	}
+	if len(collector.queue) == 0 || collector.err != nil {
+		return "", false
+	}
+	last := len(collector.queue) - 1
+	dirName := collector.queue[last]
+	collector.queue = collector.queue[:last]
+	return dirName, true
+}
+
+// finish saves the entries of the read directory and queues its subdirectories. The first
+// error is saved and the collection is canceled.
+func (collector *fileCollector) finish(files []collectedFile, subDirs []string, err error) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	collector.pending--
+	if err != nil {
+		if collector.err == nil {
+			collector.err = err
+		}
+		collector.cancel()
+	} else {
+		collector.files = append(collector.files, files...)
+		collector.queue = append(collector.queue, subDirs...)
+		collector.pending += len(subDirs)
+	}
+	collector.changed.Broadcast()
+}
+
+// collectPackFiles collects the files of the directory to pack. Symlinks pointing outside
+// of the directory are rejected unless external symlinks are allowed in the pack context.
+func collectPackFiles(packCtx *PackCtx, root string) ([]collectedFile, error) {
+	files, err := collectFiles(packCtx.operation.context(), root, getJobsCount(packCtx))
+	if err != nil {
+		return nil, err
+	}
+	if !packCtx.AllowExternalSymlinks {
+		if err = checkExternalSymlinks(root, files); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// checkExternalSymlinks returns an error if any of the collected symlinks is resolved
+// to a path outside of the root directory.
+func checkExternalSymlinks(root string, files []collectedFile) error {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.info.Mode().Type() != os.ModeSymlink {
+			continue
+		}
+		target, err := os.Readlink(file.path)
+		if err != nil {
+			return err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(file.path), target)
+		}
+		external := !isSubPath(root, target)
+		if resolvedTarget, err := filepath.EvalSymlinks(file.path); err == nil {
+			external = external || !isSubPath(resolvedRoot, resolvedTarget)
+		}
+		if external {
+			return fmt.Errorf("symlink %q points outside of the package directory: %q, "+
+				"use --allow-external-symlinks to pack it", file.relPath, target)
+		}
+	}
+	return nil
+}
+
+// statDir reads the directory entries info. Returns the entries and the names of
+// subdirectories.
+func (collector *fileCollector) statDir(dirName string) ([]collectedFile, []string, error) {
+	entries, err := collector.fsys.ReadDir(dirName)
+	if err != nil {
+		return nil, nil, err
+	}
+	files := make([]collectedFile, 0, len(entries))
+	var subDirs []string
+	for _, entry := range entries {
+		if err = collector.ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		entryName := path.Join(dirName, entry.Name())
+		info, err := collector.fsys.Lstat(entryName)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, collectedFile{name: entryName,
+			relPath: filepath.FromSlash(entryName), info: info})
+		if info.IsDir() {
+			subDirs = append(subDirs, entryName)
+		}
+	}
+	return files, subDirs, nil
+}
+
+// walkOrderLess compares relative paths in the filepath.Walk order: the directory goes
+// before its content, the entries of the same directory are ordered lexically.
+func walkOrderLess(left, right string) bool {
+	if left == "." || right == "." {
+		return left == "." && right != "."
+	}
+	leftParts := strings.Split(left, string(filepath.Separator))
+	rightParts := strings.Split(right, string(filepath.Separator))
+	for i := 0; i < len(leftParts) && i < len(rightParts); i++ {
+		if leftParts[i] != rightParts[i] {
+			return leftParts[i] < rightParts[i]
+		}
+	}
+	return len(leftParts) < len(rightParts)
+}
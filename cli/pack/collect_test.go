@@ -0,0 +1,127 @@
+package pack
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+)
+
+func Test_collectFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(root, []string{"a", "a/b", "c", "a.d"}))
+	require.NoError(t, test_helpers.CreateFiles(root, []string{"a/b/f", "a/x", "a.lua",
+		"c/y", "a.d/z"}))
+	require.NoError(t, os.Symlink("c", filepath.Join(root, "link")))
+
+	var expected []string
+	require.NoError(t, filepath.Walk(root, func(path string, _ os.FileInfo, err error) error {
+		relPath, _ := filepath.Rel(root, path)
+		expected = append(expected, relPath)
+		return err
+	}))
+
+	for _, jobs := range []int{1, 2, 8} {
+		files, err := collectFiles(context.Background(), root, jobs)
+		require.NoError(t, err)
+		var actual []string
+		for _, file := range files {
+			actual = append(actual, file.relPath)
+			assert.Equal(t, filepath.Join(root, file.relPath), filepath.Clean(file.path))
+		}
+		assert.Equal(t, expected, actual, "jobs: %d", jobs)
+	}
+}
+
+// countingFileSystem records the maximum number of the concurrent directory reads.
+type countingFileSystem struct {
+	FileSystem
+	mutex         sync.Mutex
+	reading, most int
+}
+
+// ReadDir reads the directory counting the concurrent reads.
+func (fsys *countingFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	fsys.mutex.Lock()
+	fsys.reading++
+	if fsys.reading > fsys.most {
+		fsys.most = fsys.reading
+	}
+	fsys.mutex.Unlock()
+	time.Sleep(time.Millisecond)
+	defer func() {
+		fsys.mutex.Lock()
+		fsys.reading--
+		fsys.mutex.Unlock()
+	}()
+	return fsys.FileSystem.ReadDir(name)
+}
+
+func Test_collectFilesFSJobs(t *testing.T) {
+	root := t.TempDir()
+	var dirs []string
+	for _, dir := range []string{"a", "b", "c", "d", "e", "f"} {
+		dirs = append(dirs, dir, filepath.Join(dir, "sub"))
+	}
+	require.NoError(t, test_helpers.CreateDirs(root, dirs))
+
+	fsys := countingFileSystem{FileSystem: NewOSFileSystem(root)}
+	files, err := collectFilesFS(context.Background(), &fsys, 3)
+	require.NoError(t, err)
+	assert.Len(t, files, len(dirs)+1)
+	assert.LessOrEqual(t, fsys.most, 3)
+}
+
+func Test_collectFilesErrors(t *testing.T) {
+	_, err := collectFiles(context.Background(), filepath.Join(t.TempDir(), "missing"), 2)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = collectFiles(ctx, t.TempDir(), 2)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	if os.Getuid() != 0 {
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, "locked"), 0))
+		defer os.Chmod(filepath.Join(root, "locked"), 0755)
+		_, err = collectFiles(context.Background(), root, 2)
+		assert.ErrorIs(t, err, os.ErrPermission)
+	}
+}
+
+func Test_collectPackFilesExternalSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outsideDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(root, []string{"app"}))
+	require.NoError(t, test_helpers.CreateFiles(root, []string{"app/init.lua"}))
+	require.NoError(t, test_helpers.CreateFiles(outsideDir, []string{"passwd"}))
+	require.NoError(t, os.Symlink("app", filepath.Join(root, "link")))
+	// Broken symlink is checked by its target path.
+	require.NoError(t, os.Symlink(filepath.Join("..", "..", "init.lua"),
+		filepath.Join(root, "app", "broken")))
+
+	_, err := collectPackFiles(&PackCtx{}, root)
+	assert.ErrorContains(t, err, `symlink "app/broken" points outside of the package directory`)
+
+	require.NoError(t, os.Remove(filepath.Join(root, "app", "broken")))
+	files, err := collectPackFiles(&PackCtx{}, root)
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+
+	require.NoError(t, os.Symlink(filepath.Join(outsideDir, "passwd"),
+		filepath.Join(root, "app", "passwd")))
+	_, err = collectPackFiles(&PackCtx{}, root)
+	assert.ErrorContains(t, err, `symlink "app/passwd" points outside of the package directory`)
+
+	files, err = collectPackFiles(&PackCtx{AllowExternalSymlinks: true}, root)
+	require.NoError(t, err)
+	assert.Len(t, files, 5)
+}
@@ -1,13 +1,16 @@
 package pack
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/apex/log"
 	"github.com/otiai10/copy"
@@ -16,6 +19,7 @@ import (
 	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/configure"
 	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/search"
 	"github.com/tarantool/tt/cli/util"
 	"github.com/tarantool/tt/lib/integrity"
 	lua "github.com/yuin/gopher-lua"
@@ -59,6 +63,8 @@ type packFileInfo struct {
 	owner string
 	// group is a file/dir group.
 	group string
+	// perm is a file/dir permission bits.
+	perm os.FileMode
 }
 
 // skipDefaults filters out sockets and git dirs.
@@ -146,7 +152,8 @@ func previousPackageFilters(packCtx *PackCtx) []func(
 		func(srcInfo os.FileInfo, src string) bool {
 			name := srcInfo.Name()
 			if strings.HasPrefix(name, pkgName) {
-				for _, packageSuffix := range [...]string{".rpm", ".deb", ".gz", ".tgz"} {
+				for _, packageSuffix := range [...]string{".rpm", ".deb", ".gz", ".zst", ".tgz",
+					".zip"} {
 					if filepath.Ext(name) == packageSuffix {
 						return true
 					}
@@ -159,14 +166,33 @@ func previousPackageFilters(packCtx *PackCtx) []func(
 
 // appSrcCopySkip returns a filter func to filter out artifacts paths.
 func appSrcCopySkip(packCtx *PackCtx, cliOpts *config.CliOpts,
-	srcAppPath string) func(srcinfo os.FileInfo, src, dest string) (bool, error) {
-	appCopyFilters := appArtifactsFilters(cliOpts, srcAppPath)
-	appCopyFilters = append(appCopyFilters, ttEnvironmentFilters(packCtx, cliOpts)...)
+	srcAppPath string) (func(srcinfo os.FileInfo, src, dest string) (bool, error), error) {
+	appCopyFilters := ttEnvironmentFilters(packCtx, cliOpts)
+	if !packCtx.IncludeRuntimeDirs {
+		appCopyFilters = append(appCopyFilters, appArtifactsFilters(cliOpts, srcAppPath)...)
+	}
 	appCopyFilters = append(appCopyFilters, previousPackageFilters(packCtx)...)
+	if packCtx.WithoutRocks {
+		appCopyFilters = append(appCopyFilters, rocksFilter(srcAppPath))
+	}
+	if packCtx.FollowConfig {
+		appCopyFilters = append(appCopyFilters, configuredFilesFilter(packCtx, srcAppPath))
+	}
 	appCopyFilters = append(appCopyFilters, func(srcInfo os.FileInfo, src string) bool {
 		return skipDefaults(srcInfo, src)
 	})
 
+	envDir := ""
+	if packCtx.configFilePath != "" {
+		envDir = filepath.Dir(packCtx.configFilePath)
+	}
+	packIgnoreFilter, err := ignoreFilter(envDir, srcAppPath, packCtx.bundleAppDirs[srcAppPath],
+		packCtx.excludePatterns)
+	if err != nil {
+		return nil, err
+	}
+	appCopyFilters = append(appCopyFilters, packIgnoreFilter)
+
 	return func(srcinfo os.FileInfo, src, dest string) (bool, error) {
 		for _, shouldSkip := range appCopyFilters {
 			if shouldSkip(srcinfo, src) {
@@ -174,6 +200,14 @@ func appSrcCopySkip(packCtx *PackCtx, cliOpts *config.CliOpts,
 			}
 		}
 		return false, nil
+	}, nil
+}
+
+// rocksFilter returns a filter func to skip the .rocks directory of the application.
+func rocksFilter(srcAppPath string) func(srcInfo os.FileInfo, src string) bool {
+	rocksPath := filepath.Join(srcAppPath, ".rocks")
+	return func(srcInfo os.FileInfo, src string) bool {
+		return filepath.Clean(src) == rocksPath
 	}
 }
 
@@ -243,95 +277,313 @@ func copyBinaries(bundleEnvPath string, packCtx *PackCtx, cmdCtx *cmdcontext.Cmd
 		return fmt.Errorf("failed to create binaries directory in bundle: %s", err)
 	}
 
+	targetArch, err := normalizeArch(packCtx.TargetArch)
+	if err != nil {
+		return err
+	}
+
+	// copiedBinaries are the bundle binaries to strip debug symbols from.
+	copiedBinaries := []string{}
+	packCtx.bundledBinaries = map[string]string{}
+
 	// Copy tarantool.
 	if !packCtx.TarantoolIsSystem || packCtx.WithBinaries {
-		if cmdCtx.Cli.TarantoolCli.Executable == "" {
+		tarantoolExecutable := cmdCtx.Cli.TarantoolCli.Executable
+		if packCtx.archBinDir != "" && packCtx.TarantoolBinary == "" {
+			if tarantoolExecutable, err = getArchTarantool(packCtx); err != nil {
+				return err
+			}
+		}
+		if tarantoolExecutable == "" {
 			log.Warnf("Skip copying tarantool binary: not found")
 		} else {
-			if err := util.CopyFileDeep(cmdCtx.Cli.TarantoolCli.Executable,
+			if err := checkBinaryArch(tarantoolExecutable, targetArch); err != nil {
+				return err
+			}
+			if err := verifyLockedBinary(packCtx, "tarantool",
+				tarantoolExecutable); err != nil {
+				return err
+			}
+			if err := copyBinary(packCtx, tarantoolExecutable,
 				util.JoinPaths(pkgBin, "tarantool")); err != nil {
 				return fmt.Errorf("failed copying tarantool: %s", err)
 			}
+			copiedBinaries = append(copiedBinaries, util.JoinPaths(pkgBin, "tarantool"))
+			packCtx.bundledBinaries["tarantool"] = util.JoinPaths(pkgBin, "tarantool")
 		}
 	}
 
 	// Copy tt.
 	ttExecutable, err := os.Executable()
 	if err != nil {
+		return fmt.Errorf("cannot include tt into the package: %w: %s", ErrBinaryNotFound, err)
+	}
+	if packCtx.TtBinary != "" {
+		ttExecutable = packCtx.TtBinary
+	} else if packCtx.archBinDir != "" {
+		ttExecutable, err = findArchBinary(packCtx, []string{search.ProgramTt}, "")
+		if err != nil {
+			return err
+		}
+	}
+	if err := checkBinaryArch(ttExecutable, targetArch); err != nil {
+		return err
+	}
+	if err := verifyLockedBinary(packCtx, "tt", ttExecutable); err != nil {
 		return err
 	}
-	if err := util.CopyFileDeep(ttExecutable, util.JoinPaths(pkgBin, "tt")); err != nil {
+	if err := copyBinary(packCtx, ttExecutable, util.JoinPaths(pkgBin, "tt")); err != nil {
 		return fmt.Errorf("failed copying tt: %s", err)
 	}
+	copiedBinaries = append(copiedBinaries, util.JoinPaths(pkgBin, "tt"))
+	packCtx.bundledBinaries["tt"] = util.JoinPaths(pkgBin, "tt")
+
+	if packCtx.StripDebug {
+		stripDebug(copiedBinaries...)
+	}
 	return nil
 }
 
 // getDestAppDir returns application directory in the result bundle.
 func getDestAppDir(bundleEnvPath, appName string,
 	packCtx *PackCtx, cliOpts *config.CliOpts) string {
+	return filepath.Join(bundleEnvPath, getBundleAppDir(appName, packCtx, cliOpts))
+}
+
+// getBundleAppDir returns the application directory path relative to the bundle
+// environment directory. The application is copied into the environment directory itself
+// in cartridge compat or single application environment.
+func getBundleAppDir(appName string, packCtx *PackCtx, cliOpts *config.CliOpts) string {
 	if packCtx.CartridgeCompat || cliOpts.Env.InstancesEnabled == "." {
-		return bundleEnvPath
+		return ""
 	}
-	return filepath.Join(bundleEnvPath, appName)
+	return appName
 }
 
 // copyApplications copies applications from current env to the result bundle.
+// Applications are copied concurrently by up to the jobs count workers. The first failure
+// cancels copying of the other applications.
 func copyApplications(bundleEnvPath string, packCtx *PackCtx,
 	cliOpts, newOpts *config.CliOpts) error {
-	var err error
-	for appName, instances := range packCtx.AppsInfo {
-		if len(instances) == 0 {
-			return fmt.Errorf("application %q does not have any instances", appName)
-		}
-		inst := instances[0]
-		appPath := inst.AppDir
-		if inst.IsFileApp {
-			appPath = inst.InstanceScript
-			resolvedAppPath, err := filepath.EvalSymlinks(appPath)
-			if err != nil {
-				return err
+	appNames := make([]string, 0, len(packCtx.AppsInfo))
+	for appName := range packCtx.AppsInfo {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+
+	ctx, cancel := context.WithCancel(packCtx.operation.context())
+	defer cancel()
+	sem := make(chan struct{}, getJobsCount(packCtx))
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var firstErr error
+	for _, appName := range appNames {
+		wg.Add(1)
+		go func(appName string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			err := copyApplication(ctx, bundleEnvPath, appName, packCtx, cliOpts, newOpts)
+			if err == nil {
+				return
 			}
-			if err = copy.Copy(resolvedAppPath,
-				util.JoinPaths(bundleEnvPath, filepath.Base(resolvedAppPath))); err != nil {
-				return fmt.Errorf("failed to copy application %q: %s", resolvedAppPath, err)
+			mutex.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("application %q: %s", appName, err)
 			}
+			mutex.Unlock()
+			cancel()
+		}(appName)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// copyApplication copies the application from current env to the result bundle.
+func copyApplication(ctx context.Context, bundleEnvPath, appName string, packCtx *PackCtx,
+	cliOpts, newOpts *config.CliOpts) error {
+	var err error
+	instances := packCtx.AppsInfo[appName]
+	if len(instances) == 0 {
+		return fmt.Errorf("application %q does not have any instances", appName)
+	}
+	inst := instances[0]
+	appPath := inst.AppDir
+	// The instances directory entry may be a symlink to the application placed elsewhere.
+	// Script is copied with its own name, directory is copied with the entry name.
+	var bundleAppPath string
+	if inst.IsFileApp {
+		appPath = inst.InstanceScript
+		resolvedAppPath, err := filepath.EvalSymlinks(appPath)
+		if err != nil {
+			return err
+		}
+		bundleAppPath = util.JoinPaths(bundleEnvPath, filepath.Base(resolvedAppPath))
+		if err = copy.Copy(resolvedAppPath, bundleAppPath); err != nil {
+			return fmt.Errorf("failed to copy application %q: %s", resolvedAppPath, err)
+		}
+	} else {
+		bundleAppPath = getDestAppDir(bundleEnvPath, appName, packCtx, cliOpts)
+		if packCtx.CacheDir != "" {
+			err = copyAppSrcCached(ctx, packCtx, cliOpts, appPath, bundleAppPath)
 		} else {
-			bundleAppDir := getDestAppDir(bundleEnvPath, appName, packCtx, cliOpts)
-			if err = copyAppSrc(packCtx, cliOpts, appPath, bundleAppDir); err != nil {
+			err = copyAppSrc(ctx, packCtx, cliOpts, appPath, bundleAppPath)
+		}
+		if err != nil {
+			return err
+		}
+		if packCtx.Since != "" {
+			if err = removeUnchangedFiles(bundleAppPath, packCtx.sinceFiles[appName]); err != nil {
 				return err
 			}
 		}
+	}
 
-		if !packCtx.CartridgeCompat && newOpts.Env.InstancesEnabled != "." {
-			// Create applications symlink in instances enabled.
-			if err = os.MkdirAll(util.JoinPaths(bundleEnvPath, newOpts.Env.InstancesEnabled),
-				dirPermissions); err != nil {
-				return fmt.Errorf("cannot create instances.enabled directory: %s", err)
-			}
-			packagingInstEnabledDir := util.JoinPaths(bundleEnvPath, newOpts.Env.InstancesEnabled)
-			err = createAppSymlink(appPath, filepath.Base(appPath), packagingInstEnabledDir)
-			if err != nil {
-				return err
-			}
-			// Create working dir for script-only applications. This is required to do not attempt
-			// to create it on target system which may lead to permissions denied error.
-			if inst.IsFileApp {
-				workingDir := util.JoinPaths(packagingInstEnabledDir, filepath.Base(inst.AppDir))
-				if err = os.Mkdir(workingDir, dirPermissions); err != nil {
-					return fmt.Errorf(
-						"cannot create working directory %q for application: %s",
-						workingDir, err)
-				}
+	if !packCtx.CartridgeCompat && newOpts.Env.InstancesEnabled != "." {
+		// Create applications symlink in instances enabled.
+		if err = os.MkdirAll(util.JoinPaths(bundleEnvPath, newOpts.Env.InstancesEnabled),
+			dirPermissions); err != nil {
+			return fmt.Errorf("cannot create instances.enabled directory: %s", err)
+		}
+		packagingInstEnabledDir := util.JoinPaths(bundleEnvPath, newOpts.Env.InstancesEnabled)
+		err = createAppSymlink(bundleAppPath, filepath.Base(appPath), packagingInstEnabledDir)
+		if err != nil {
+			return err
+		}
+		// Create working dir for script-only applications. This is required to do not attempt
+		// to create it on target system which may lead to permissions denied error.
+		if inst.IsFileApp {
+			workingDir := util.JoinPaths(packagingInstEnabledDir, filepath.Base(inst.AppDir))
+			if err = os.Mkdir(workingDir, dirPermissions); err != nil {
+				return fmt.Errorf(
+					"cannot create working directory %q for application: %s",
+					workingDir, err)
 			}
 		}
 	}
 	return nil
 }
 
+// prepareSourceDirBundle copies the prebuilt bundle directory into a temporary directory
+// for packing. Returns a path to the prepared directory or error if it failed.
+func prepareSourceDirBundle(packCtx *PackCtx) (string, error) {
+	tmpDir, err := os.MkdirTemp(getTmpDir(packCtx), "tt_pack")
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Packing prebuilt bundle from %s", packCtx.SourceDir)
+	if err = copy.Copy(packCtx.SourceDir, tmpDir); err != nil {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Warnf("Failed to remove a directory %s: %s", tmpDir, err)
+		}
+		return "", fmt.Errorf("error copying source directory: %s", err)
+	}
+	if err = applyIncludePatterns(packCtx, tmpDir); err == nil {
+		err = copyExtraFiles(packCtx, tmpDir)
+	}
+	if err == nil && packCtx.PruneEmptyDirs {
+		err = pruneEmptyDirs(tmpDir, nil)
+	}
+	if err == nil && packCtx.NormalizePermissions {
+		err = normalizePermissions(tmpDir)
+	}
+	if err == nil && packCtx.WithIntegrityIndex {
+		err = writeIntegrityIndex(tmpDir, nil)
+	}
+	if err != nil {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Warnf("Failed to remove a directory %s: %s", tmpDir, err)
+		}
+		return "", err
+	}
+	return tmpDir, nil
+}
+
+// sharedBundleContent is a bundle content prepared once and reused by the packages
+// of different types built in one invocation.
+type sharedBundleContent struct {
+	// path is a directory containing the prepared content. It is empty until the content
+	// is prepared for the first package.
+	path string
+	// buildRocks shows if the applications rocks are built in the content.
+	buildRocks bool
+}
+
+// ShareBundleContent makes the pack contexts reuse the modules, applications and rocks
+// collected for the first built package, so the packages have the same content.
+// Returned function removes the shared content.
+func ShareBundleContent(packCtxs ...*PackCtx) func() {
+	content := &sharedBundleContent{}
+	for _, packCtx := range packCtxs {
+		packCtx.sharedContent = content
+	}
+	return func() {
+		if content.path == "" {
+			return
+		}
+		if err := os.RemoveAll(content.path); err != nil {
+			log.Warnf("Failed to remove a directory %s: %s", content.path, err)
+		}
+	}
+}
+
+// copyBundleContent copies modules and applications into the bundle and builds
+// applications rocks. The content is copied from the shared content if it is already
+// prepared for another package.
+func copyBundleContent(cmdCtx *cmdcontext.CmdCtx, bundleEnvPath string, packCtx *PackCtx,
+	cliOpts, newOpts *config.CliOpts, buildRocks bool) error {
+	buildRocks = buildRocks && !packCtx.WithoutRocks && !packCtx.NoRebuild
+	shared := packCtx.sharedContent
+	if shared != nil && shared.path != "" && shared.buildRocks == buildRocks {
+		log.Infof("Copying bundle content collected for the previous package")
+		if err := copy.Copy(shared.path, bundleEnvPath); err != nil {
+			return fmt.Errorf("error copying shared bundle content: %s", err)
+		}
+		return nil
+	}
+
+	copyEnvModules(bundleEnvPath, packCtx, cliOpts, newOpts)
+	if err := copyApplications(bundleEnvPath, packCtx, cliOpts, newOpts); err != nil {
+		return fmt.Errorf("error copying applications: %s", err)
+	}
+	// The files changed by the rocks building and the later steps are hashed again.
+	packCtx.integrityHashes.finishCollection()
+
+	if buildRocks {
+		err := buildAppRocks(cmdCtx, packCtx, cliOpts, bundleEnvPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if shared != nil && shared.path == "" {
+		sharedPath, err := os.MkdirTemp(getTmpDir(packCtx), "tt_pack_shared")
+		if err != nil {
+			return err
+		}
+		if err = copy.Copy(bundleEnvPath, sharedPath); err != nil {
+			os.RemoveAll(sharedPath)
+			return fmt.Errorf("error saving shared bundle content: %s", err)
+		}
+		shared.path = sharedPath
+		shared.buildRocks = buildRocks
+	}
+	return nil
+}
+
 // prepareBundle prepares a temporary directory for packing.
 // Returns a path to the prepared directory or error if it failed.
 func prepareBundle(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	cliOpts *config.CliOpts, buildRocks bool) (string, error) {
+	if packCtx.SourceDir != "" {
+		return prepareSourceDirBundle(packCtx)
+	}
+
 	var err error
 	var signer integrity.Signer = nil
 
@@ -345,10 +597,11 @@ func prepareBundle(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	}
 
 	// Create temporary directory step.
-	tmpDir, err := os.MkdirTemp("", "tt_pack")
+	tmpDir, err := os.MkdirTemp(getTmpDir(packCtx), "tt_pack")
 	if err != nil {
 		return "", err
 	}
+	packCtx.operation.addOutput(tmpDir)
 
 	defer func() {
 		if err != nil {
@@ -367,14 +620,23 @@ func prepareBundle(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		return "", err
 	}
 	newOpts := createNewOpts(cliOpts, *packCtx)
+	if packCtx.RpmDeb.CreateRuntimeDirs {
+		packCtx.RpmDeb.runtimeDirs = getRuntimeDirs(packCtx, newOpts)
+	}
+	if packCtx.WithIntegrityIndex {
+		packCtx.integrityHashes = newIntegrityHashes()
+	}
 
-	copyEnvModules(bundleEnvPath, packCtx, cliOpts, newOpts)
-	if err = copyBinaries(bundleEnvPath, packCtx, cmdCtx, newOpts); err != nil {
-		return "", fmt.Errorf("error copying binaries: %s", err)
+	if err = copyBundleContent(cmdCtx, bundleEnvPath, packCtx, cliOpts, newOpts,
+		buildRocks); err != nil {
+		return "", err
+	}
+	if err = checkArchRocks(bundleEnvPath, packCtx, cliOpts); err != nil {
+		return "", err
 	}
 
-	if err = copyApplications(bundleEnvPath, packCtx, cliOpts, newOpts); err != nil {
-		return "", fmt.Errorf("error copying applications: %s", err)
+	if err = copyBinaries(bundleEnvPath, packCtx, cmdCtx, newOpts); err != nil {
+		return "", fmt.Errorf("error copying binaries: %w", err)
 	}
 
 	if packCtx.Archive.All {
@@ -383,11 +645,11 @@ func prepareBundle(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		}
 	}
 
-	if buildRocks {
-		err = buildAppRocks(cmdCtx, packCtx, cliOpts, bundleEnvPath)
-		if err != nil && !os.IsNotExist(err) {
-			return "", err
-		}
+	if err = applyIncludePatterns(packCtx, bundleEnvPath); err != nil {
+		return "", err
+	}
+	if err = checkIncludedApps(bundleEnvPath, packCtx, newOpts); err != nil {
+		return "", err
 	}
 
 	writeEnv(newOpts, bundleEnvPath, packCtx.CartridgeCompat)
@@ -395,6 +657,40 @@ func prepareBundle(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		return "", err
 	}
 
+	if err = applyConfigOverlay(bundleEnvPath, packCtx, newOpts); err != nil {
+		return "", err
+	}
+
+	if err = copyExtraFiles(packCtx, bundleEnvPath); err != nil {
+		return "", err
+	}
+
+	if packCtx.PruneEmptyDirs {
+		if err = pruneEmptyDirs(tmpDir, nil); err != nil {
+			return "", err
+		}
+	}
+
+	if packCtx.NormalizePermissions {
+		if err = normalizePermissions(tmpDir); err != nil {
+			return "", err
+		}
+	}
+
+	if err = generateManifest(cmdCtx, packCtx, cliOpts, bundleEnvPath); err != nil {
+		return "", err
+	}
+
+	if err = generateSbom(packCtx, cliOpts, bundleEnvPath); err != nil {
+		return "", err
+	}
+
+	if packCtx.WithIntegrityIndex {
+		if err = writeIntegrityIndex(bundleEnvPath, packCtx.integrityHashes); err != nil {
+			return "", err
+		}
+	}
+
 	if packCtx.IntegrityPrivateKey != "" {
 		err = signer.Sign(bundleEnvPath, packCtx.AppList)
 		if err != nil {
@@ -405,8 +701,16 @@ func prepareBundle(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	return tmpDir, nil
 }
 
+// isPreserveSymlinks returns true if the application symlinks are kept as links. The flag
+// is ignored for RPM and Deb packages, they get the symlink target contents.
+func isPreserveSymlinks(packCtx *PackCtx) bool {
+	return packCtx.Archive.PreserveSymlinks && packCtx.Type != Rpm && packCtx.Type != Deb
+}
+
 // copyAppSrc copies a source file or directory to the directory, that will be packed.
-func copyAppSrc(packCtx *PackCtx, cliOpts *config.CliOpts, srcAppPath, dstAppPath string) error {
+// Copying is stopped if the context is canceled.
+func copyAppSrc(ctx context.Context, packCtx *PackCtx, cliOpts *config.CliOpts,
+	srcAppPath, dstAppPath string) error {
 	resolvedAppPath, err := filepath.EvalSymlinks(srcAppPath)
 	if err != nil {
 		return err
@@ -415,11 +719,148 @@ func copyAppSrc(packCtx *PackCtx, cliOpts *config.CliOpts, srcAppPath, dstAppPat
 		return err
 	}
 
-	skipFunc := appSrcCopySkip(packCtx, cliOpts, resolvedAppPath)
+	skipFunc, err := appSrcCopySkip(packCtx, cliOpts, resolvedAppPath)
+	if err != nil {
+		return err
+	}
 
 	// Copying application.
 	log.Debugf("Copying application source %q -> %q", resolvedAppPath, dstAppPath)
-	return copy.Copy(resolvedAppPath, dstAppPath, copy.Options{Skip: skipFunc})
+	copier := appSrcCopier{
+		ctx:                   ctx,
+		appPath:               resolvedAppPath,
+		dstAppPath:            dstAppPath,
+		preserveSymlinks:      isPreserveSymlinks(packCtx),
+		allowExternalSymlinks: packCtx.AllowExternalSymlinks,
+		operation:             packCtx.operation,
+		hashes:                packCtx.integrityHashes,
+		skip:                  skipFunc,
+	}
+	return copier.copy(resolvedAppPath, dstAppPath, nil)
+}
+
+// appSrcCopier copies application source files handling the symlinks.
+type appSrcCopier struct {
+	ctx context.Context
+	// appPath is a resolved application source directory.
+	appPath string
+	// dstAppPath is a destination application directory.
+	dstAppPath string
+	// preserveSymlinks means to keep symlinks pointing to the application directory
+	// as links. All symlinks are dereferenced otherwise.
+	preserveSymlinks bool
+	// allowExternalSymlinks means to copy targets of the symlinks pointing outside of
+	// the application directory instead of failing.
+	allowExternalSymlinks bool
+	// preserveTimes means to keep the modification times of the copied files.
+	preserveTimes bool
+	// operation records the copied files of the pack operation.
+	operation *packOperation
+	// hashes collects the checksums of the copied files if it is set.
+	hashes *integrityHashes
+	// skip is a filter of the files to copy.
+	skip func(srcinfo os.FileInfo, src, dest string) (bool, error)
+}
+
+// copy copies the source directory to the destination. Symlinks are handled after
+// the rest content is copied. copiedDirs contains the directories being copied
+// by the upper calls to detect symlink cycles.
+func (copier *appSrcCopier) copy(src, dst string, copiedDirs []string) error {
+	type symlink struct{ src, dst string }
+	var symlinks []symlink
+	// copiedFile is the file being copied. The files are copied sequentially right after
+	// the skip check, so the reader is wrapped for the last checked file.
+	copiedFile := ""
+	err := copy.Copy(src, dst, copy.Options{
+		OnSymlink: func(string) copy.SymlinkAction {
+			return copy.Skip
+		},
+		PreserveTimes: copier.preserveTimes,
+		WrapReader: func(reader io.Reader) io.Reader {
+			return copier.hashes.wrap(copiedFile, reader)
+		},
+		Skip: func(srcinfo os.FileInfo, srcPath, dstPath string) (bool, error) {
+			copiedFile = dstPath
+			if err := copier.ctx.Err(); err != nil {
+				return false, err
+			}
+			if err := copier.operation.processFile(srcPath); err != nil {
+				return false, err
+			}
+			skip, err := copier.skip(srcinfo, srcPath, dstPath)
+			if err == nil && !skip && srcinfo.Mode().Type() == os.ModeSymlink {
+				symlinks = append(symlinks, symlink{srcPath, dstPath})
+			}
+			return skip, err
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	copiedDirs = append(copiedDirs, src)
+	for _, link := range symlinks {
+		if err = copier.copySymlink(link.src, link.dst, copiedDirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copySymlink preserves or dereferences the symlink. Preserved symlinks are made relative.
+// Symlinks pointing outside of the application directory are rejected if they are
+// not allowed, and always dereferenced otherwise.
+func (copier *appSrcCopier) copySymlink(src, dst string, copiedDirs []string) error {
+	linkTarget, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	target, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		log.Warnf("Symlink %q target cannot be resolved, the symlink is copied as is: %s",
+			src, err)
+		return os.Symlink(linkTarget, dst)
+	}
+
+	if !isSubPath(copier.appPath, target) && !copier.allowExternalSymlinks {
+		return fmt.Errorf("symlink %q points outside of the application directory: %q, "+
+			"use --allow-external-symlinks to pack it", src, target)
+	}
+	if copier.preserveSymlinks {
+		if isSubPath(copier.appPath, target) {
+			appRelTarget, err := filepath.Rel(copier.appPath, target)
+			if err != nil {
+				return fmt.Errorf("failed to get relative path of %q: %s", target, err)
+			}
+			relTarget, err := filepath.Rel(filepath.Dir(dst),
+				filepath.Join(copier.dstAppPath, appRelTarget))
+			if err != nil {
+				return fmt.Errorf("failed to get relative path of %q: %s", target, err)
+			}
+			return os.Symlink(relTarget, dst)
+		}
+		log.Warnf("Symlink %q points outside of the application directory, "+
+			"its target is copied", src)
+	}
+
+	linkDir, err := filepath.EvalSymlinks(filepath.Dir(src))
+	if err != nil {
+		return err
+	}
+	for _, dir := range append(copiedDirs, linkDir) {
+		if isSubPath(target, dir) {
+			return fmt.Errorf("cannot dereference symlink %q: it points to the parent "+
+				"directory %q", src, target)
+		}
+	}
+	return copier.copy(target, dst, copiedDirs)
+}
+
+// isSubPath checks if the path is located in the base directory or is the directory itself.
+func isSubPath(basePath, path string) bool {
+	relPath, err := filepath.Rel(basePath, path)
+	return err == nil && relPath != ".." &&
+		!strings.HasPrefix(relPath, ".."+string(filepath.Separator))
 }
 
 // copyArtifacts copies all artifacts from the current bundle configuration
@@ -593,6 +1034,27 @@ func cleanupAfterBuild(appDir string) {
 	}
 }
 
+// warnNotBuiltApps warns about the applications having a rockspec but no .rocks directory.
+// Such applications are packed without their rocks if the rebuild is skipped.
+func warnNotBuiltApps(packCtx *PackCtx) {
+	appNames := make([]string, 0, len(packCtx.AppsInfo))
+	for appName := range packCtx.AppsInfo {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+	for _, appName := range appNames {
+		instances := packCtx.AppsInfo[appName]
+		if len(instances) == 0 {
+			continue
+		}
+		appDir := instances[0].AppDir
+		if rockspecExists(appDir) && !util.IsDir(filepath.Join(appDir, ".rocks")) {
+			log.Warnf("Application %s has a rockspec but its rocks are not installed, "+
+				"run tt build before packing it with --no-rebuild", appName)
+		}
+	}
+}
+
 // buildAppRocks finds a rockspec file of the application and builds it.
 func buildAppRocks(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	cliOpts *config.CliOpts, bundlePath string) error {
@@ -689,39 +1151,89 @@ func normalizeGitVersion(packageVersion string) (string, error) {
 // getPackageFileName returns the result name of the package file.
 func getPackageFileName(packCtx *PackCtx, opts *config.CliOpts, suffix string,
 	addVersion bool) (string, error) {
-	var packageName string
-
 	if packCtx.FileName != "" {
 		if packCtx.CartridgeCompat {
 			// Need to collect info about version
 			// for generating VERSION and VERSION.lua files.
 			getVersion(packCtx, opts, defaultLongVersion)
 		}
-		return packCtx.FileName, nil
-	} else if packCtx.Name != "" {
-		packageName = packCtx.Name
-	} else {
-		absPath, err := filepath.Abs(".")
-		if err != nil {
-			return "", err
-		}
-		packageName = filepath.Base(absPath)
+		return expandFileNameTemplate(packCtx.FileName, packCtx, opts)
 	}
 
+	template := "{name}"
 	if addVersion {
 		var separator string
 		switch packCtx.Type {
-		case Tgz, Rpm:
+		case Tgz, Zip, Rpm, AppImage:
 			separator = "-"
 		case Deb:
 			separator = "_"
 		}
-		versionSuffix := getVersion(packCtx, opts, defaultLongVersion)
-		packageName += separator + versionSuffix
+		template += separator + "{version}"
 	}
+	return expandFileNameTemplate(template+suffix, packCtx, opts)
+}
 
-	packageName += suffix
-	return packageName, nil
+// getPackageFilePath returns the result path of the package file in the output directory.
+func getPackageFilePath(packCtx *PackCtx, packageFileName string) (string, error) {
+	outputDir := packCtx.OutputDir
+	if outputDir == "" {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		outputDir = currentDir
+	}
+	return filepath.Join(outputDir, packageFileName), nil
+}
+
+// writePackageFile writes the package file using the temporary file in the package
+// directory or in the temporary directory on the same file system and renames it to
+// the package path on success. So the interrupted pack
+// operation does not leave the truncated package at the result path. The artifact mode
+// is set and the size limits are checked before the rename, so the package appears with
+// the requested mode and the package exceeding the maximum size is not written. The full
+// file system is reported as ErrNoSpace naming the temporary file directory. The files
+// are written under the artifact lock taken by the packer.
+func writePackageFile(packCtx *PackCtx, packagePath string,
+	write func(tmpPath string) error) error {
+	tmpPath := filepath.Join(filepath.Dir(packagePath),
+		"."+filepath.Base(packagePath)+".tmp")
+	if packCtx.artifactTmpDir != "" {
+		// The temporary directory may be shared, so the file name is unique.
+		tmpFile, err := os.CreateTemp(packCtx.artifactTmpDir,
+			"."+filepath.Base(packagePath)+".*.tmp")
+		if err != nil {
+			return wrapNoSpaceError(err, packCtx.artifactTmpDir)
+		}
+		tmpFile.Close()
+		tmpPath = tmpFile.Name()
+	} else if err := os.RemoveAll(tmpPath); err != nil {
+		// The temporary file may be left by the killed pack operation.
+		return err
+	}
+	packCtx.operation.addOutput(tmpPath)
+
+	err := wrapNoSpaceError(write(tmpPath), filepath.Dir(tmpPath))
+	if err == nil {
+		err = checkPackageSize(packCtx, packagePath, tmpPath)
+	}
+	if err != nil {
+		if err := os.RemoveAll(tmpPath); err != nil {
+			log.Warnf("Failed to remove a temporary package file %s: %s", tmpPath, err)
+		}
+		return err
+	}
+	if packCtx.artifactMode != nil {
+		if err := os.Chmod(tmpPath, *packCtx.artifactMode); err != nil {
+			return fmt.Errorf("failed to set the package file mode: %s", err)
+		}
+	}
+	if err := os.Rename(tmpPath, packagePath); err != nil {
+		return fmt.Errorf("failed to move the package file to %s: %s", packagePath, err)
+	}
+	packCtx.operation.finishOutput(tmpPath)
+	return nil
 }
 
 // LuaGetRocksVersions gets map which contains {name: versions} from rocks manifest.
@@ -793,7 +1305,41 @@ func createArtifactsDirs(pkgDataDir string, packCtx *PackCtx) error {
 	}
 	for _, dir := range [...]string{"lib", "log", "run"} {
 		packCtx.RpmDeb.pkgFilesInfo[fmt.Sprintf("var/%s/tarantool", dir)] =
-			packFileInfo{"tarantool", "tarantool"}
+			packFileInfo{owner: "tarantool", group: "tarantool"}
+	}
+	for _, runtimeDir := range packCtx.RpmDeb.runtimeDirs {
+		if err := os.MkdirAll(filepath.Join(pkgDataDir, runtimeDir), dirPermissions); err != nil {
+			return fmt.Errorf("cannot create %q: %s", runtimeDir, err)
+		}
+		packCtx.RpmDeb.pkgFilesInfo[runtimeDir] = packFileInfo{owner: "tarantool",
+			group: "tarantool"}
 	}
 	return nil
 }
+
+// getRuntimeDirs returns the package paths of the environment data, log and run directories
+// and their subdirectories for the packed applications. The directories configured relative
+// to the environment are installed with it, so they are skipped.
+func getRuntimeDirs(packCtx *PackCtx, newOpts *config.CliOpts) []string {
+	runtimeDirs := []string{}
+	added := map[string]bool{}
+	addDir := func(dir string) {
+		if !added[dir] {
+			added[dir] = true
+			runtimeDirs = append(runtimeDirs, dir)
+		}
+	}
+	for _, dir := range []string{newOpts.App.WalDir, newOpts.App.MemtxDir,
+		newOpts.App.VinylDir, newOpts.App.LogDir, newOpts.App.RunDir} {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		envDir := filepath.ToSlash(strings.TrimPrefix(dir, string(filepath.Separator)))
+		addDir(envDir)
+		for _, appName := range packCtx.AppList {
+			addDir(envDir + "/" + appName)
+		}
+	}
+	sort.Strings(runtimeDirs)
+	return runtimeDirs
+}
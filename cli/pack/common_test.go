@@ -1,6 +1,8 @@
 package pack
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
@@ -8,12 +10,16 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/build"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/configure"
 	"github.com/tarantool/tt/cli/pack/test_helpers"
+	"github.com/tarantool/tt/cli/running"
 	"github.com/tarantool/tt/lib/integrity"
 )
 
@@ -381,6 +387,54 @@ func Test_createNewOpts(t *testing.T) {
 	}
 }
 
+func Test_createArtifactsDirsRuntimeDirs(t *testing.T) {
+	packCtx := &PackCtx{
+		Type:    Rpm,
+		Name:    "env",
+		AppList: []string{"app1", "app2"},
+		RpmDeb:  RpmDebCtx{pkgFilesInfo: map[string]packFileInfo{}},
+	}
+	newOpts := createNewOpts(&config.CliOpts{
+		Env: &config.TtEnvOpts{},
+		App: &config.AppOpts{WalDir: "var/lib", VinylDir: "var/lib", MemtxDir: "var/lib"},
+	}, *packCtx)
+	packCtx.RpmDeb.runtimeDirs = getRuntimeDirs(packCtx, newOpts)
+	expectedDirs := []string{
+		"var/lib/tarantool/env",
+		"var/lib/tarantool/env/app1",
+		"var/lib/tarantool/env/app2",
+		"var/log/tarantool/env",
+		"var/log/tarantool/env/app1",
+		"var/log/tarantool/env/app2",
+		"var/run/tarantool/env",
+		"var/run/tarantool/env/app1",
+		"var/run/tarantool/env/app2",
+	}
+	assert.Equal(t, expectedDirs, packCtx.RpmDeb.runtimeDirs)
+
+	pkgDataDir := t.TempDir()
+	require.NoError(t, createArtifactsDirs(pkgDataDir, packCtx))
+	for _, dir := range expectedDirs {
+		assert.DirExists(t, filepath.Join(pkgDataDir, dir))
+		assert.Equal(t, packFileInfo{owner: "tarantool", group: "tarantool"},
+			packCtx.RpmDeb.pkgFilesInfo[dir])
+	}
+
+	// The separated memtx, vinyl and wal directories are relative to the environment.
+	newOpts = createNewOpts(&config.CliOpts{
+		Env: &config.TtEnvOpts{},
+		App: &config.AppOpts{WalDir: "wal", VinylDir: "vinyl", MemtxDir: "snap"},
+	}, *packCtx)
+	assert.Equal(t, []string{
+		"var/log/tarantool/env",
+		"var/log/tarantool/env/app1",
+		"var/log/tarantool/env/app2",
+		"var/run/tarantool/env",
+		"var/run/tarantool/env/app1",
+		"var/run/tarantool/env/app2",
+	}, getRuntimeDirs(packCtx, newOpts))
+}
+
 func Test_prepareBundle(t *testing.T) {
 	type params struct {
 		configPath    string
@@ -1106,3 +1160,260 @@ func Test_prepareBundle(t *testing.T) {
 		})
 	}
 }
+
+func Test_prepareBundleFromSourceDir(t *testing.T) {
+	sourceDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "app", "init.lua"),
+		[]byte("print(1)"), 0644))
+
+	bundlePath, err := prepareBundle(&cmdcontext.CmdCtx{}, &PackCtx{SourceDir: sourceDir},
+		&config.CliOpts{}, true)
+	require.NoError(t, err)
+	defer os.RemoveAll(bundlePath)
+
+	assert.NotEqual(t, sourceDir, bundlePath)
+	content, err := os.ReadFile(filepath.Join(bundlePath, "app", "init.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, "print(1)", string(content))
+	assert.NoFileExists(t, filepath.Join(bundlePath, manifestFileName))
+}
+
+func Test_copyApplicationsConcurrently(t *testing.T) {
+	envDir := t.TempDir()
+	appsInfo := map[string][]running.InstanceCtx{}
+	for _, appName := range []string{"app1", "app2", "app3", "app4"} {
+		appDir := filepath.Join(envDir, appName)
+		require.NoError(t, os.MkdirAll(filepath.Join(appDir, "lib"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), []byte(appName),
+			0644))
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, "lib", "mod.lua"), nil, 0644))
+		appsInfo[appName] = []running.InstanceCtx{{AppDir: appDir}}
+	}
+	opts := &config.CliOpts{
+		Env: &config.TtEnvOpts{InstancesEnabled: configure.InstancesEnabledDirName},
+		App: &config.AppOpts{},
+	}
+
+	bundleDir := t.TempDir()
+	packCtx := PackCtx{AppsInfo: appsInfo, Jobs: 2}
+	require.NoError(t, copyApplications(bundleDir, &packCtx, opts, opts))
+	for appName := range appsInfo {
+		content, err := os.ReadFile(filepath.Join(bundleDir, appName, "init.lua"))
+		require.NoError(t, err)
+		assert.Equal(t, appName, string(content))
+		assert.FileExists(t, filepath.Join(bundleDir, appName, "lib", "mod.lua"))
+		assert.FileExists(t, filepath.Join(bundleDir, configure.InstancesEnabledDirName,
+			appName, "init.lua"))
+	}
+
+	// Failed application is reported.
+	appsInfo["broken"] = []running.InstanceCtx{{AppDir: filepath.Join(envDir, "missing")}}
+	err := copyApplications(t.TempDir(), &packCtx, opts, opts)
+	assert.ErrorContains(t, err, `application "broken": `)
+}
+
+func Test_copyApplicationsSymlinkedApps(t *testing.T) {
+	envDir := t.TempDir()
+	instancesDir := filepath.Join(envDir, configure.InstancesEnabledDirName)
+	realDir := filepath.Join(envDir, "elsewhere")
+	require.NoError(t, os.MkdirAll(filepath.Join(realDir, "real_app"), 0755))
+	require.NoError(t, os.Mkdir(instancesDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(realDir, "real_app", "init.lua"), nil,
+		0644))
+	require.NoError(t, os.WriteFile(filepath.Join(realDir, "main.lua"), nil, 0644))
+	require.NoError(t, os.Symlink(filepath.Join(realDir, "real_app"),
+		filepath.Join(instancesDir, "app")))
+	require.NoError(t, os.Symlink(filepath.Join(realDir, "main.lua"),
+		filepath.Join(instancesDir, "script.lua")))
+
+	opts := &config.CliOpts{
+		Env: &config.TtEnvOpts{InstancesEnabled: configure.InstancesEnabledDirName},
+		App: &config.AppOpts{},
+	}
+	packCtx := PackCtx{AppsInfo: map[string][]running.InstanceCtx{
+		"app": {{AppDir: filepath.Join(instancesDir, "app")}},
+		"script": {{AppDir: filepath.Join(instancesDir, "script"), IsFileApp: true,
+			InstanceScript: filepath.Join(instancesDir, "script.lua")}},
+	}}
+	bundleDir := t.TempDir()
+	require.NoError(t, copyApplications(bundleDir, &packCtx, opts, opts))
+
+	// The application directory is packed with the name of the link.
+	assert.FileExists(t, filepath.Join(bundleDir, "app", "init.lua"))
+	assert.NoDirExists(t, filepath.Join(bundleDir, "real_app"))
+	assert.FileExists(t, filepath.Join(bundleDir, "main.lua"))
+	bundleInstancesDir := filepath.Join(bundleDir, configure.InstancesEnabledDirName)
+	assert.FileExists(t, filepath.Join(bundleInstancesDir, "app", "init.lua"))
+	assert.FileExists(t, filepath.Join(bundleInstancesDir, "script.lua"))
+	assert.DirExists(t, filepath.Join(bundleInstancesDir, "script"))
+}
+
+func Test_copyAppSrcSymlinks(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "app")
+	outsideDir := filepath.Join(envDir, "outside")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{"lib"}))
+	require.NoError(t, os.Mkdir(outsideDir, 0755))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"lib/mod.lua"}))
+	require.NoError(t, test_helpers.CreateFiles(outsideDir, []string{"shared.lua"}))
+	require.NoError(t, os.Symlink(filepath.Join("lib", "mod.lua"),
+		filepath.Join(appDir, "rel.lua")))
+	require.NoError(t, os.Symlink(filepath.Join(appDir, "lib"), filepath.Join(appDir, "abs")))
+	require.NoError(t, os.Symlink(filepath.Join(outsideDir, "shared.lua"),
+		filepath.Join(appDir, "shared.lua")))
+
+	readLink := func(path string) string {
+		target, err := os.Readlink(path)
+		require.NoError(t, err)
+		return target
+	}
+
+	// External symlinks are not allowed by default.
+	err := copyAppSrc(context.Background(), &PackCtx{}, &config.CliOpts{}, appDir,
+		filepath.Join(t.TempDir(), "app"))
+	assert.ErrorContains(t, err, fmt.Sprintf(
+		"symlink %q points outside of the application directory",
+		filepath.Join(appDir, "shared.lua")))
+
+	// Symlinks are dereferenced by default.
+	packCtx := PackCtx{AllowExternalSymlinks: true}
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{}, appDir,
+		dstDir))
+	for _, name := range []string{"rel.lua", "abs/mod.lua", "shared.lua"} {
+		stat, err := os.Lstat(filepath.Join(dstDir, name))
+		require.NoError(t, err)
+		assert.True(t, stat.Mode().IsRegular(), name)
+	}
+
+	// Symlinks pointing to the application directory are preserved and made relative.
+	packCtx.Archive.PreserveSymlinks = true
+	dstDir = filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{}, appDir,
+		dstDir))
+	assert.Equal(t, filepath.Join("lib", "mod.lua"), readLink(filepath.Join(dstDir, "rel.lua")))
+	assert.Equal(t, "lib", readLink(filepath.Join(dstDir, "abs")))
+	stat, err := os.Lstat(filepath.Join(dstDir, "shared.lua"))
+	require.NoError(t, err)
+	assert.True(t, stat.Mode().IsRegular())
+
+	// The flag is ignored for RPM and Deb packages.
+	for _, packageType := range []string{Rpm, Deb} {
+		packCtx.Type = packageType
+		dstDir = filepath.Join(t.TempDir(), "app")
+		require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{},
+			appDir, dstDir))
+		stat, err = os.Lstat(filepath.Join(dstDir, "rel.lua"))
+		require.NoError(t, err)
+		assert.True(t, stat.Mode().IsRegular(), packageType)
+	}
+	packCtx.Type = ""
+
+	// Symlink cycle cannot be dereferenced.
+	require.NoError(t, os.Symlink(appDir, filepath.Join(appDir, "lib", "app")))
+	packCtx.Archive.PreserveSymlinks = false
+	err = copyAppSrc(context.Background(), &packCtx, &config.CliOpts{}, appDir,
+		filepath.Join(t.TempDir(), "app"))
+	assert.ErrorContains(t, err, "it points to the parent directory")
+}
+
+func Test_copyBundleContentShared(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.Mkdir(appDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), []byte("v1"), 0644))
+	opts := &config.CliOpts{
+		Env: &config.TtEnvOpts{InstancesEnabled: configure.InstancesEnabledDirName},
+		App: &config.AppOpts{},
+	}
+
+	tgzCtx := PackCtx{Type: Tgz, AppsInfo: map[string][]running.InstanceCtx{
+		"app": {{AppDir: appDir}}}}
+	rpmCtx := tgzCtx
+	rpmCtx.Type = Rpm
+	cleanup := ShareBundleContent(&tgzCtx, &rpmCtx)
+
+	tgzBundle := t.TempDir()
+	require.NoError(t, copyBundleContent(&cmdcontext.CmdCtx{}, tgzBundle, &tgzCtx, opts, opts,
+		false))
+	sharedPath := tgzCtx.sharedContent.path
+	require.DirExists(t, sharedPath)
+
+	// The application is not copied again for the second package.
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), []byte("v2"), 0644))
+	rpmBundle := t.TempDir()
+	require.NoError(t, copyBundleContent(&cmdcontext.CmdCtx{}, rpmBundle, &rpmCtx, opts, opts,
+		false))
+	for _, bundle := range []string{tgzBundle, rpmBundle} {
+		content, err := os.ReadFile(filepath.Join(bundle, "app", "init.lua"))
+		require.NoError(t, err)
+		assert.Equal(t, "v1", string(content))
+		assert.FileExists(t, filepath.Join(bundle, configure.InstancesEnabledDirName, "app",
+			"init.lua"))
+	}
+
+	cleanup()
+	assert.NoDirExists(t, sharedPath)
+}
+
+func Test_copyBinariesTarantoolNotFound(t *testing.T) {
+	bundleDir := t.TempDir()
+	cmdCtx := cmdcontext.CmdCtx{}
+	newOpts := &config.CliOpts{Env: &config.TtEnvOpts{BinDir: "bin"}}
+
+	// The missing tarantool is skipped with a warning.
+	packCtx := PackCtx{TarantoolIsSystem: true, WithBinaries: true}
+	require.NoError(t, copyBinaries(bundleDir, &packCtx, &cmdCtx, newOpts))
+	assert.NoFileExists(t, filepath.Join(bundleDir, "bin", "tarantool"))
+	assert.FileExists(t, filepath.Join(bundleDir, "bin", "tt"))
+}
+
+func Test_copyBundleContentNoRebuild(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.Mkdir(appDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "app-scm-1.rockspec"),
+		[]byte("package = 'app'"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, build.PreBuildScripts[0]),
+		[]byte("#!/bin/sh"), 0755))
+	opts := &config.CliOpts{
+		Env: &config.TtEnvOpts{InstancesEnabled: configure.InstancesEnabledDirName},
+		App: &config.AppOpts{},
+	}
+
+	// The application is packed as is: the rockspec is not built and not removed.
+	packCtx := PackCtx{Type: Tgz, NoRebuild: true, AppsInfo: map[string][]running.InstanceCtx{
+		"app": {{AppDir: appDir}}}}
+	bundleDir := t.TempDir()
+	require.NoError(t, copyBundleContent(&cmdcontext.CmdCtx{}, bundleDir, &packCtx, opts, opts,
+		true))
+	assert.FileExists(t, filepath.Join(bundleDir, "app", "app-scm-1.rockspec"))
+	assert.FileExists(t, filepath.Join(bundleDir, "app", build.PreBuildScripts[0]))
+}
+
+func Test_warnNotBuiltApps(t *testing.T) {
+	logger := log.Log.(*log.Logger)
+	prevHandler := logger.Handler
+	defer func() {
+		logger.Handler = prevHandler
+	}()
+	handler := memory.New()
+	logger.Handler = handler
+
+	builtDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(builtDir, "built-scm-1.rockspec"), nil,
+		0644))
+	require.NoError(t, os.Mkdir(filepath.Join(builtDir, ".rocks"), 0755))
+	notBuiltDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(notBuiltDir, "app-scm-1.rockspec"), nil,
+		0644))
+
+	warnNotBuiltApps(&PackCtx{AppsInfo: map[string][]running.InstanceCtx{
+		"built":    {{AppDir: builtDir}},
+		"app":      {{AppDir: notBuiltDir}},
+		"no_rocks": {{AppDir: t.TempDir()}},
+		"no_insts": {},
+	}})
+	require.Len(t, handler.Entries, 1)
+	assert.Equal(t, "Application app has a rockspec but its rocks are not installed, "+
+		"run tt build before packing it with --no-rebuild", handler.Entries[0].Message)
+}
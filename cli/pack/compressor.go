@@ -0,0 +1,116 @@
+package pack
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// CompressorGzip is a gzip tarball compressor.
+	CompressorGzip = "gzip"
+	// CompressorZstd is a zstd tarball compressor.
+	CompressorZstd = "zstd"
+)
+
+// compressorInfo describes a tarball compressor.
+type compressorInfo struct {
+	// name is a compressor name.
+	name string
+	// ext is a tarball file name extension.
+	ext string
+	// minLevel and maxLevel are the bounds of the compression level.
+	minLevel, maxLevel int
+	// newWriter creates the compressing writer with the passed compression level.
+	newWriter func(writer io.Writer, level int) (io.WriteCloser, error)
+}
+
+// compressors contains supported tarball compressors by their names.
+var compressors = map[string]compressorInfo{
+	CompressorGzip: {
+		name:     CompressorGzip,
+		ext:      "gz",
+		minLevel: gzip.NoCompression,
+		maxLevel: gzip.BestCompression,
+		newWriter: func(writer io.Writer, level int) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(writer, level)
+		},
+	},
+	CompressorZstd: {
+		name:      CompressorZstd,
+		ext:       "zst",
+		minLevel:  1,
+		maxLevel:  22,
+		newWriter: newZstdWriter,
+	},
+}
+
+// getCompressor returns the tarball compressor info. Gzip is used if the compressor
+// is not set.
+func getCompressor(name string) (compressorInfo, error) {
+	if name == "" {
+		name = CompressorGzip
+	}
+	compressor, found := compressors[name]
+	if !found {
+		return compressorInfo{}, fmt.Errorf("unknown compressor %q, supported: %s, %s", name,
+			CompressorGzip, CompressorZstd)
+	}
+	return compressor, nil
+}
+
+// CheckCompression checks the compressor is supported and the compression level is
+// in the compressor range.
+func CheckCompression(archiveCtx ArchiveCtx) error {
+	compressor, err := getCompressor(archiveCtx.Compressor)
+	if err != nil {
+		return err
+	}
+	if compressor.name == CompressorZstd {
+		if err = checkZstdSupport(); err != nil {
+			return err
+		}
+	}
+	if archiveCtx.CompressionLevel < compressor.minLevel ||
+		archiveCtx.CompressionLevel > compressor.maxLevel {
+		return fmt.Errorf("invalid compression level %d: must be in range from %d to %d",
+			archiveCtx.CompressionLevel, compressor.minLevel, compressor.maxLevel)
+	}
+	return nil
+}
+
+// writeCompressedTar writes the tarball of specified path to the writer using
+// the compressor and the compression level from the pack context.
+func writeCompressedTar(srcDirPath string, writer io.Writer, packCtx *PackCtx) error {
+	compressor, err := getCompressor(packCtx.Archive.Compressor)
+	if err != nil {
+		return err
+	}
+	compressWriter, err := compressor.newWriter(writer, packCtx.Archive.CompressionLevel)
+	if err != nil {
+		return fmt.Errorf("failed to create %s writer: %s", strings.ToUpper(compressor.name),
+			err)
+	}
+
+	if err = WriteTarArchive(srcDirPath, compressWriter, packCtx); err != nil {
+		compressWriter.Close()
+		return err
+	}
+	return compressWriter.Close()
+}
+
+// writeTarballFile creates the compressed tarball file of specified path.
+func writeTarballFile(srcDirPath string, destFilePath string, packCtx *PackCtx) error {
+	destFile, err := os.Create(destFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create result tarball file %s: %s", destFilePath, err)
+	}
+	defer destFile.Close()
+
+	if err = writeCompressedTar(srcDirPath, destFile, packCtx); err != nil {
+		return err
+	}
+	return destFile.Close()
+}
@@ -0,0 +1,78 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeCompressedTar(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "init.lua"), []byte("print(1)"),
+		0644))
+
+	tests := []struct {
+		compressor string
+		level      int
+		newReader  func(reader io.Reader) (io.Reader, error)
+	}{
+		{"", DefaultCompressionLevel, func(reader io.Reader) (io.Reader, error) {
+			return gzip.NewReader(reader)
+		}},
+		{CompressorGzip, 0, func(reader io.Reader) (io.Reader, error) {
+			return gzip.NewReader(reader)
+		}},
+		{CompressorZstd, 19, func(reader io.Reader) (io.Reader, error) {
+			return zstd.NewReader(reader)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.compressor, func(t *testing.T) {
+			if tt.compressor == CompressorZstd && checkZstdSupport() != nil {
+				t.Skip("tt is built without zstd support")
+			}
+			var buf bytes.Buffer
+			packCtx := PackCtx{Archive: ArchiveCtx{Compressor: tt.compressor,
+				CompressionLevel: tt.level}}
+			require.NoError(t, writeCompressedTar(srcDir, &buf, &packCtx))
+
+			reader, err := tt.newReader(&buf)
+			require.NoError(t, err)
+			tarReader := tar.NewReader(reader)
+			names := []string{}
+			for {
+				header, err := tarReader.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				names = append(names, header.Name)
+			}
+			assert.Equal(t, []string{".", "init.lua"}, names)
+		})
+	}
+}
+
+func TestCheckCompression(t *testing.T) {
+	assert.NoError(t, CheckCompression(ArchiveCtx{CompressionLevel: 0}))
+	assert.NoError(t, CheckCompression(ArchiveCtx{Compressor: CompressorZstd,
+		CompressionLevel: 1}))
+	assert.EqualError(t, CheckCompression(ArchiveCtx{Compressor: CompressorZstd,
+		CompressionLevel: 23}), "invalid compression level 23: must be in range from 1 to 22")
+	assert.EqualError(t, CheckCompression(ArchiveCtx{Compressor: CompressorGzip,
+		CompressionLevel: 10}), "invalid compression level 10: must be in range from 0 to 9")
+}
+
+func Test_getTarballSuffix(t *testing.T) {
+	suffix, err := getTarballSuffix(compressors[CompressorZstd].ext)
+	require.NoError(t, err)
+	assert.Regexp(t, `^\.[a-z0-9_]+\.tar\.zst$`, suffix)
+}
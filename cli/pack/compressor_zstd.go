@@ -0,0 +1,28 @@
+//go:build !tt_zstd_disable
+
+package pack
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// checkZstdSupport returns an error if tt is built without zstd support.
+func checkZstdSupport() error {
+	return nil
+}
+
+// newZstdWriter creates zstd writer. The level is a zstd compression level from 1 to 22.
+func newZstdWriter(writer io.Writer, level int) (io.WriteCloser, error) {
+	return zstd.NewWriter(writer, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+}
+
+// newZstdReader creates zstd reader.
+func newZstdReader(reader io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	return decoder.IOReadCloser(), nil
+}
@@ -0,0 +1,27 @@
+//go:build tt_zstd_disable
+
+package pack
+
+import (
+	"fmt"
+	"io"
+)
+
+// errZstdDisabled is returned if tt is built without zstd support.
+var errZstdDisabled = fmt.Errorf("zstd compressor is not supported: tt is built with " +
+	"tt_zstd_disable tag, use gzip compressor")
+
+// checkZstdSupport returns an error if tt is built without zstd support.
+func checkZstdSupport() error {
+	return errZstdDisabled
+}
+
+// newZstdWriter returns an error since tt is built without zstd support.
+func newZstdWriter(writer io.Writer, level int) (io.WriteCloser, error) {
+	return nil, errZstdDisabled
+}
+
+// newZstdReader returns an error since tt is built without zstd support.
+func newZstdReader(reader io.Reader) (io.ReadCloser, error) {
+	return nil, errZstdDisabled
+}
@@ -0,0 +1,87 @@
+package pack
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// debConffilesName is a name of the Deb control file listing the config files.
+const debConffilesName = "conffiles"
+
+// parseConfigFileGlobs parses globs of the package config files. The glob is matched
+// against the installed file path without the leading slash.
+func parseConfigFileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		if strings.TrimPrefix(glob, "/") == "" {
+			return nil, fmt.Errorf("invalid config file glob %q: the glob is empty", glob)
+		}
+		reStr, err := globToRegexp(strings.TrimPrefix(glob, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file glob %q: %s", glob, err)
+		}
+		re, err := regexp.Compile("^" + reStr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file glob %q: %s", glob, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// matchesAny returns true if the slash-separated package path matches any of
+// the regular expressions.
+func matchesAny(res []*regexp.Regexp, relPath string) bool {
+	for _, re := range res {
+		if re.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRpmConfigFile returns true if the RPM package file is a config file.
+func (rpmDeb *RpmDebCtx) isRpmConfigFile(relPath string) bool {
+	return matchesAny(rpmDeb.rpmConfigFileRes, relPath)
+}
+
+// writeDebConffiles writes the conffiles control file listing the regular files of
+// the data directory matching the --deb-conffile globs. dpkg keeps the changes of
+// the listed files made by the user on upgrade. The file is not written if no file
+// matches.
+func writeDebConffiles(controlDirPath, dataDirPath string, rpmDeb *RpmDebCtx) error {
+	if len(rpmDeb.debConffileRes) == 0 {
+		return nil
+	}
+	conffiles := []string{}
+	err := filepath.WalkDir(dataDirPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dataDirPath, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if matchesAny(rpmDeb.debConffileRes, relPath) {
+			conffiles = append(conffiles, "/"+relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find config files: %s", err)
+	}
+	if len(conffiles) == 0 {
+		return nil
+	}
+	content := strings.Join(conffiles, "\n") + "\n"
+	return os.WriteFile(filepath.Join(controlDirPath, debConffilesName), []byte(content),
+		0644)
+}
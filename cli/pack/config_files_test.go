@@ -0,0 +1,87 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseConfigFileGlobs(t *testing.T) {
+	res, err := parseConfigFileGlobs([]string{"/usr/share/tarantool/app/*.yaml",
+		"etc/**/conf.d/*"})
+	require.NoError(t, err)
+	rpmDeb := RpmDebCtx{rpmConfigFileRes: res}
+	assert.True(t, rpmDeb.isRpmConfigFile("usr/share/tarantool/app/config.yaml"))
+	assert.False(t, rpmDeb.isRpmConfigFile("usr/share/tarantool/app/init.lua"))
+	assert.False(t, rpmDeb.isRpmConfigFile("usr/share/tarantool/app/sub/config.yaml"))
+	assert.True(t, rpmDeb.isRpmConfigFile("etc/app/conf.d/node.conf"))
+	assert.True(t, rpmDeb.isRpmConfigFile("etc/app/extra/conf.d/node.conf"))
+	assert.False(t, (&RpmDebCtx{}).isRpmConfigFile("etc/app/conf.d/node.conf"))
+
+	for glob, errMsg := range map[string]string{
+		"":           "the glob is empty",
+		"/":          "the glob is empty",
+		"conf[0-9":   "unterminated character class",
+		"app/[a-z*/": "unterminated character class",
+	} {
+		_, err := parseConfigFileGlobs([]string{glob})
+		assert.ErrorContains(t, err, errMsg, glob)
+		assert.ErrorContains(t, err, "invalid config file glob", glob)
+	}
+}
+
+func Test_getFilesInfoConfigFiles(t *testing.T) {
+	baseDir := t.TempDir()
+	appDir := filepath.Join(baseDir, "usr", "share", "tarantool", "app")
+	require.NoError(t, os.MkdirAll(appDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "config.yaml"), []byte("a"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), []byte("b"), 0644))
+
+	// The directory matching the glob is not a config file.
+	res, err := parseConfigFileGlobs([]string{"usr/share/tarantool/**/*.yaml",
+		"usr/share/tarantool/app"})
+	require.NoError(t, err)
+	info, err := getFilesInfo([]string{
+		"usr/share/tarantool/app",
+		"usr/share/tarantool/app/config.yaml",
+		"usr/share/tarantool/app/init.lua",
+	}, baseDir, &RpmDebCtx{rpmConfigFileRes: res}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{dirFlag, fileFlag | configFlag, fileFlag}, info.FileFlags)
+}
+
+func Test_writeDebConffiles(t *testing.T) {
+	dataDir := t.TempDir()
+	appDir := filepath.Join(dataDir, "usr", "share", "tarantool", "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(appDir, "conf.d"), 0755))
+	for _, name := range []string{"config.yaml", "init.lua", "conf.d/b.yaml", "conf.d/a.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, name), []byte("x"), 0644))
+	}
+	require.NoError(t, os.Symlink("config.yaml", filepath.Join(appDir, "link.yaml")))
+
+	controlDir := t.TempDir()
+	conffilesPath := filepath.Join(controlDir, debConffilesName)
+
+	// No globs.
+	require.NoError(t, writeDebConffiles(controlDir, dataDir, &RpmDebCtx{}))
+	assert.NoFileExists(t, conffilesPath)
+
+	// No matching files.
+	res, err := parseConfigFileGlobs([]string{"etc/*.conf"})
+	require.NoError(t, err)
+	require.NoError(t, writeDebConffiles(controlDir, dataDir, &RpmDebCtx{debConffileRes: res}))
+	assert.NoFileExists(t, conffilesPath)
+
+	// The symlinks are not listed, the files are sorted.
+	res, err = parseConfigFileGlobs([]string{"/usr/share/tarantool/app/**/*.yaml"})
+	require.NoError(t, err)
+	require.NoError(t, writeDebConffiles(controlDir, dataDir, &RpmDebCtx{debConffileRes: res}))
+	content, err := os.ReadFile(conffilesPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/usr/share/tarantool/app/conf.d/a.yaml\n"+
+		"/usr/share/tarantool/app/conf.d/b.yaml\n"+
+		"/usr/share/tarantool/app/config.yaml\n", string(content))
+}
@@ -0,0 +1,127 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/config"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ConfigOverlayReplace means to replace the application config with the overlay.
+	ConfigOverlayReplace = "replace"
+	// ConfigOverlayMerge means to deep-merge the overlay into the application config.
+	ConfigOverlayMerge = "merge"
+
+	// defaultClusterConfigName is the application config name used if the application
+	// does not have a cluster config yet.
+	defaultClusterConfigName = "config.yml"
+)
+
+// yamlMap is a YAML mapping decoded by yaml.v2.
+type yamlMap = map[interface{}]interface{}
+
+// loadConfigOverlay reads the config overlay file and checks it is a YAML mapping.
+func loadConfigOverlay(path string) (yamlMap, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config overlay: %s", err)
+	}
+	overlay := yamlMap{}
+	if err = yaml.Unmarshal(content, &overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse config overlay %q: %s", path, err)
+	}
+	return overlay, nil
+}
+
+// initConfigOverlay checks the config overlay options and loads the overlay.
+func initConfigOverlay(packCtx *PackCtx) error {
+	switch packCtx.ConfigOverlayMode {
+	case "", ConfigOverlayReplace, ConfigOverlayMerge:
+	default:
+		return fmt.Errorf("unknown config overlay mode %q, supported: %s, %s",
+			packCtx.ConfigOverlayMode, ConfigOverlayReplace, ConfigOverlayMerge)
+	}
+	var err error
+	packCtx.configOverlay, err = loadConfigOverlay(packCtx.ConfigOverlay)
+	return err
+}
+
+// mergeYamlMaps deep-merges the overlay into the base mapping. The overlay values
+// override the base ones, except the mappings which are merged recursively.
+func mergeYamlMaps(base, overlay yamlMap) yamlMap {
+	for key, value := range overlay {
+		baseMap, isBaseMap := base[key].(yamlMap)
+		overlayMap, isOverlayMap := value.(yamlMap)
+		if isBaseMap && isOverlayMap {
+			base[key] = mergeYamlMaps(baseMap, overlayMap)
+		} else {
+			base[key] = value
+		}
+	}
+	return base
+}
+
+// getBundleClusterConfigPath returns the path of the application cluster config in
+// the bundle.
+func getBundleClusterConfigPath(bundleEnvPath string, packCtx *PackCtx,
+	newOpts *config.CliOpts) (string, error) {
+	if len(packCtx.AppsInfo) != 1 {
+		return "", fmt.Errorf("config overlay can be applied to a single application, "+
+			"%d applications are packed, use --app-list to select one", len(packCtx.AppsInfo))
+	}
+	for appName, instances := range packCtx.AppsInfo {
+		if len(instances) == 0 || instances[0].IsFileApp {
+			return "", fmt.Errorf("config overlay cannot be applied to %q: "+
+				"not an application directory", appName)
+		}
+		configName := defaultClusterConfigName
+		if instances[0].ClusterConfigPath != "" {
+			configName = filepath.Base(instances[0].ClusterConfigPath)
+		}
+		appPath := getDestAppDir(bundleEnvPath, appName, packCtx, newOpts)
+		return filepath.Join(appPath, configName), nil
+	}
+	return "", nil
+}
+
+// applyConfigOverlay writes the config overlay to the application cluster config in
+// the bundle. The config is replaced or the overlay is merged into it depending on
+// the overlay mode.
+func applyConfigOverlay(bundleEnvPath string, packCtx *PackCtx,
+	newOpts *config.CliOpts) error {
+	if packCtx.ConfigOverlay == "" {
+		return nil
+	}
+	configPath, err := getBundleClusterConfigPath(bundleEnvPath, packCtx, newOpts)
+	if err != nil {
+		return err
+	}
+
+	if packCtx.ConfigOverlayMode != ConfigOverlayMerge {
+		log.Infof("Replacing %s with config overlay %s", configPath, packCtx.ConfigOverlay)
+		content, err := os.ReadFile(packCtx.ConfigOverlay)
+		if err != nil {
+			return fmt.Errorf("cannot read config overlay: %s", err)
+		}
+		return os.WriteFile(configPath, content, filePermissions)
+	}
+
+	log.Infof("Merging config overlay %s into %s", packCtx.ConfigOverlay, configPath)
+	merged := yamlMap{}
+	content, err := os.ReadFile(configPath)
+	if err == nil {
+		if err = yaml.Unmarshal(content, &merged); err != nil {
+			return fmt.Errorf("failed to parse application config %q: %s", configPath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if content, err = yaml.Marshal(mergeYamlMaps(merged, packCtx.configOverlay)); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, content, filePermissions)
+}
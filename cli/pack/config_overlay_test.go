@@ -0,0 +1,74 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func Test_initConfigOverlay(t *testing.T) {
+	overlayPath := filepath.Join(t.TempDir(), "prod.yml")
+	require.NoError(t, os.WriteFile(overlayPath, []byte("credentials:\n  users: {}\n"), 0644))
+
+	packCtx := PackCtx{ConfigOverlay: overlayPath}
+	require.NoError(t, initConfigOverlay(&packCtx))
+	assert.Contains(t, packCtx.configOverlay, "credentials")
+
+	packCtx.ConfigOverlayMode = "append"
+	assert.EqualError(t, initConfigOverlay(&packCtx),
+		`unknown config overlay mode "append", supported: replace, merge`)
+
+	require.NoError(t, os.WriteFile(overlayPath, []byte("- not a mapping\n"), 0644))
+	packCtx.ConfigOverlayMode = ConfigOverlayMerge
+	assert.ErrorContains(t, initConfigOverlay(&packCtx), "failed to parse config overlay")
+
+	packCtx.ConfigOverlay = filepath.Join(t.TempDir(), "missing.yml")
+	assert.ErrorContains(t, initConfigOverlay(&packCtx), "cannot read config overlay")
+}
+
+func Test_applyConfigOverlay(t *testing.T) {
+	baseDir := t.TempDir()
+	bundleEnvPath := filepath.Join(baseDir, "bundle")
+	configPath := filepath.Join(bundleEnvPath, "app", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	baseConfig := "groups:\n  storages:\n    replication:\n      failover: off\n" +
+		"    replicasets: {}\nlog:\n  level: 5\n"
+	overlayPath := filepath.Join(baseDir, "prod.yml")
+	overlay := "groups:\n  storages:\n    replication:\n      failover: manual\n" +
+		"log: stderr\n"
+	require.NoError(t, os.WriteFile(overlayPath, []byte(overlay), 0644))
+
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	packCtx := PackCtx{ConfigOverlay: overlayPath,
+		AppsInfo: map[string][]running.InstanceCtx{
+			"app": {{ClusterConfigPath: "/env/app/config.yaml"}}}}
+	require.NoError(t, initConfigOverlay(&packCtx))
+
+	require.NoError(t, os.WriteFile(configPath, []byte(baseConfig), 0644))
+	require.NoError(t, applyConfigOverlay(bundleEnvPath, &packCtx, opts))
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, overlay, string(content))
+
+	packCtx.ConfigOverlayMode = ConfigOverlayMerge
+	require.NoError(t, os.WriteFile(configPath, []byte(baseConfig), 0644))
+	require.NoError(t, applyConfigOverlay(bundleEnvPath, &packCtx, opts))
+	content, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "groups:\n  storages:\n    replicasets: {}\n    replication:\n"+
+		"      failover: manual\nlog: stderr\n", string(content))
+
+	// The default config is created for the application without a cluster config.
+	packCtx.AppsInfo["app"][0].ClusterConfigPath = ""
+	require.NoError(t, applyConfigOverlay(bundleEnvPath, &packCtx, opts))
+	assert.FileExists(t, filepath.Join(bundleEnvPath, "app", defaultClusterConfigName))
+
+	packCtx.AppsInfo["other"] = []running.InstanceCtx{{}}
+	assert.ErrorContains(t, applyConfigOverlay(bundleEnvPath, &packCtx, opts),
+		"config overlay can be applied to a single application, 2 applications are packed")
+}
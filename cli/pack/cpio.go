@@ -3,14 +3,18 @@ package pack
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
 // packCpio runs cpio command and packs the passed directory into the new package.
-func packCpio(relPaths []string, resFileName, packageFilesDir string) error {
+// If reproducible is set, inodes and owners do not depend on the build system.
+func packCpio(ctx context.Context, relPaths []string, resFileName, packageFilesDir string,
+	reproducible bool, progress *progressTracker) error {
 	cpioFile, err := os.Create(resFileName)
 	if err != nil {
 		return err
@@ -25,7 +29,11 @@ func packCpio(relPaths []string, resFileName, packageFilesDir string) error {
 	filesBuffer := bytes.Buffer{}
 	filesBuffer.WriteString(strings.Join(relPaths, "\n"))
 
-	cmd := exec.Command("cpio", "-o", "-H", "newc")
+	cpioArgs := []string{"-o", "-H", "newc"}
+	if reproducible {
+		cpioArgs = append(cpioArgs, "--reproducible", "-R", "0:0")
+	}
+	cmd := exec.CommandContext(ctx, "cpio", cpioArgs...)
 	cmd.Stdin = &filesBuffer
 	cmd.Stdout = cpioFileWriter
 	cmd.Stderr = &stderrBuf
@@ -35,5 +43,12 @@ func packCpio(relPaths []string, resFileName, packageFilesDir string) error {
 		return fmt.Errorf("failed to run \n%s\n\nStderr: %s", cmd.String(), stderrBuf.String())
 	}
 
+	for _, relPath := range relPaths {
+		if fileInfo, err := os.Lstat(filepath.Join(packageFilesDir, relPath)); err == nil &&
+			!fileInfo.IsDir() {
+			progress.fileAdded(relPath, fileInfo.Size())
+		}
+	}
+
 	return nil
 }
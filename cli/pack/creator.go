@@ -1,17 +1,58 @@
 package pack
 
-func CreatePacker(packCtx *PackCtx) Packer {
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+// CreatePacker returns the packer for the pack context package type. ErrUnsupportedType
+// is reported for an unknown package type.
+func CreatePacker(packCtx *PackCtx) (Packer, error) {
 	packType := PackageType(packCtx.Type)
 	switch packType {
 	case Tgz:
-		return &archivePacker{}
+		return &archivePacker{}, nil
+	case Zip:
+		return &zipPacker{}, nil
 	case Deb:
-		return &debPacker{}
+		return &debPacker{}, nil
 	case Rpm:
-		return &rpmPacker{}
+		return &rpmPacker{}, nil
 	case Docker:
-		return nil
+		return &dockerImagePacker{}, nil
+	case AppImage:
+		return &appImagePacker{}, nil
 	default:
-		return nil
+		typeNames := []string{}
+		for _, supportedType := range SupportedTypes() {
+			typeNames = append(typeNames, supportedType.String())
+		}
+		return nil, fmt.Errorf("%w %q, available types: %s", ErrUnsupportedType,
+			packCtx.Type, strings.Join(typeNames, ", "))
+	}
+}
+
+// Pack creates the package of the pack context type and returns the path of the created
+// package file. The path is a docker image name for the docker image type. ErrNoSpace is
+// reported if the file system is full.
+func Pack(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, opts *config.CliOpts) (string, error) {
+	packer, err := CreatePacker(packCtx)
+	if err != nil {
+		return "", err
+	}
+	defer unlockArtifact(packCtx)
+	if err = packer.Run(cmdCtx, packCtx, opts); err != nil {
+		// The package file errors are already wrapped, so the full file system is the one
+		// of the temporary bundle directories.
+		return "", wrapNoSpaceError(err, getTmpDir(packCtx))
+	}
+	if packCtx.Type != Docker {
+		if err = writeSbomFile(packCtx, packCtx.artifactPath); err != nil {
+			return "", err
+		}
 	}
+	return packCtx.artifactPath, nil
 }
@@ -1,6 +1,7 @@
 package pack
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io/fs"
 	"os"
@@ -28,14 +29,26 @@ const (
 
 	debianBinaryFileContent = "2.0\n"
 
+	gpgOriginFileName = "_gpgorigin"
+
 	PreInstScriptName  = "preinst"
 	PostInstScriptName = "postinst"
+	PreRmScriptName    = "prerm"
+	PostRmScriptName   = "postrm"
 )
 
 // defaultEnvPrefix is a path there applications will be stored after install
 // from RPM and Deb packages.
 var defaultEnvPrefix = filepath.Join("usr", "share", "tarantool")
 
+// getInstallPrefix returns the install prefix relative to the target system root.
+func getInstallPrefix(packCtx *PackCtx) string {
+	if packCtx.RpmDeb.InstallPrefix == "" {
+		return defaultEnvPrefix
+	}
+	return strings.TrimPrefix(filepath.Clean("/"+packCtx.RpmDeb.InstallPrefix), "/")
+}
+
 // debPacker is a structure that implements Packer interface
 // with specific deb packing behavior.
 type debPacker struct {
@@ -47,6 +60,15 @@ type debPacker struct {
 // data.tar.xz    : package files
 // control.tar.xz : control files (control, preinst etc.)
 
+// getControlArchiveCtx returns the context to write the control archive with. The package
+// files mode and ownership are set for the data archive only, so the maintainer scripts
+// keep their modes.
+func getControlArchiveCtx(packCtx PackCtx) PackCtx {
+	packCtx.RpmDeb.fileModeRules = nil
+	packCtx.RpmDeb.pkgFilesInfo = nil
+	return packCtx
+}
+
 // Run packs a bundle into deb package.
 func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	opts *config.CliOpts) error {
@@ -59,10 +81,11 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	}
 
 	// Create a package directory, where it will be built.
-	packageDir, err := os.MkdirTemp("", "tt_pack")
+	packageDir, err := os.MkdirTemp(getTmpDir(packCtx), "tt_pack")
 	if err != nil {
 		return err
 	}
+	packCtx.operation.addOutput(packageDir)
 	defer func() {
 		err := os.RemoveAll(packageDir)
 		if err != nil {
@@ -88,7 +111,8 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 
 	log.Info("Creating a data directory")
 
-	rootPrefix := filepath.Join(dataDirName, defaultEnvPrefix, packCtx.Name)
+	installPrefix := getInstallPrefix(packCtx)
+	rootPrefix := filepath.Join(dataDirName, installPrefix, packCtx.Name)
 	if opts.Env.InstancesEnabled == "." || packCtx.CartridgeCompat {
 		rootPrefix = filepath.Dir(rootPrefix)
 	}
@@ -102,8 +126,9 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		return err
 	}
 
-	envSystemPath := filepath.Join("/", defaultEnvPrefix, packCtx.Name)
-	err = initSystemdDir(packCtx, packageDataDir, envSystemPath)
+	envSystemPath := filepath.Join("/", installPrefix, packCtx.Name)
+	err = initSystemdDir(packCtx, packageDataDir, envSystemPath,
+		getVersion(packCtx, opts, defaultVersion))
 	if err != nil {
 		return err
 	}
@@ -112,6 +137,18 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		return err
 	}
 
+	if packCtx.RpmDeb.debChangelog != "" {
+		if err = writeDebChangelog(packageDataDir, packCtx.Name,
+			packCtx.RpmDeb.debChangelog); err != nil {
+			return fmt.Errorf("failed to write changelog: %s", err)
+		}
+	}
+	if packCtx.RpmDeb.License != "" {
+		if err = writeDebCopyright(packageDataDir, packCtx); err != nil {
+			return fmt.Errorf("failed to write copyright file: %s", err)
+		}
+	}
+
 	// App directory.
 	if err = copy.Copy(bundlePath, packagePrefixedPath); err != nil {
 		return err
@@ -124,7 +161,8 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 
 	// Create data.tar.gz.
 	dataArchivePath := filepath.Join(packageDir, dataArchiveName)
-	err = writeTgzArchive(packageDataDir, dataArchivePath, *packCtx)
+	err = writeTgzArchive(packageDataDir, dataArchivePath, *packCtx,
+		gzip.DefaultCompression)
 	if err != nil {
 		return err
 	}
@@ -137,10 +175,14 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	if err != nil {
 		return err
 	}
+	if err = writeDebConffiles(controlDirPath, packageDataDir, &packCtx.RpmDeb); err != nil {
+		return err
+	}
 
 	// Create control.tar.gz.
 	controlArchivePath := filepath.Join(packageDir, controlArchiveName)
-	err = writeTgzArchive(controlDirPath, controlArchivePath, *packCtx)
+	err = writeTgzArchive(controlDirPath, controlArchivePath, getControlArchiveCtx(*packCtx),
+		gzip.DefaultCompression)
 	if err != nil {
 		return err
 	}
@@ -153,7 +195,7 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		return err
 	}
 
-	debSuffix, err := getDebSuffix()
+	debSuffix, err := getDebSuffix(packCtx)
 	if err != nil {
 		return err
 	}
@@ -161,24 +203,79 @@ func (packer *debPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	if err != nil {
 		return err
 	}
+	if packageName, err = getPackageFilePath(packCtx, packageName); err != nil {
+		return err
+	}
+	if err = lockArtifact(packCtx, packageName); err != nil {
+		return err
+	}
 
-	// Create result archive.
-	packDebCmd := exec.Command(
-		"ar", "r",
-		packageName,
+	debMembers := []string{
 		filepath.Join(packageDir, debianBinaryFileName),
 		controlArchivePath,
 		dataArchivePath,
-	)
+	}
+	if packCtx.RpmDeb.SignKey != "" {
+		log.Info("Signing the package")
 
-	err = packDebCmd.Run()
+		gpgOriginPath, err := createGpgOrigin(packageDir, packCtx.RpmDeb.SignKey, debMembers)
+		if err != nil {
+			return err
+		}
+		debMembers = append(debMembers, gpgOriginPath)
+	}
+
+	// Create result archive.
+	arOperation := "r"
+	if packCtx.sourceDateEpoch != nil {
+		// Deterministic mode: zero timestamps and owner ids.
+		arOperation = "rD"
+	}
+	err = writePackageFile(packCtx, packageName, func(tmpPath string) error {
+		packDebCmd := exec.CommandContext(packCtx.operation.context(), "ar",
+			append([]string{arOperation, tmpPath}, debMembers...)...)
+		if err := packDebCmd.Run(); err != nil {
+			return fmt.Errorf("failed to pack DEB: %s", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to pack DEB: %s", err)
+		return err
 	}
 
+	packCtx.progress.done()
+	packCtx.artifactPath = packageName
 	log.Infof("Created result DEB package: %s", packageName)
 
-	return err
+	if packCtx.WithChecksum {
+		return writeChecksumFile(packageName)
+	}
+	return nil
+}
+
+// createGpgOrigin creates _gpgorigin file containing detached signature of
+// the concatenated deb package members, as debsigs does.
+func createGpgOrigin(packageDir, signKey string, debMembers []string) (string, error) {
+	signer, err := newGpgSigner(signKey)
+	if err != nil {
+		return "", err
+	}
+	defer signer.Close()
+
+	membersFilePath := filepath.Join(packageDir, "members")
+	if err = util.MergeFiles(membersFilePath, debMembers...); err != nil {
+		return "", fmt.Errorf("failed to concat DEB members: %s", err)
+	}
+	signature, err := signer.detachSign(membersFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign DEB: %s", err)
+	}
+
+	gpgOriginPath := filepath.Join(packageDir, gpgOriginFileName)
+	if err = os.WriteFile(gpgOriginPath, signature, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %s", gpgOriginFileName, err)
+	}
+	return gpgOriginPath, nil
 }
 
 // createDebianBinary creates a debian-binary file for deb package.
@@ -220,10 +317,13 @@ func getTntTTAsDeps(cmdCtx *cmdcontext.CmdCtx) (PackDependencies, error) {
 }
 
 // getDebSuffix returns suffix for a Deb package.
-func getDebSuffix() (string, error) {
-	arch, err := util.GetArch()
-	if err != nil {
-		return "", err
+func getDebSuffix(packCtx *PackCtx) (string, error) {
+	arch := packCtx.RpmDeb.DebArch
+	if arch == "" {
+		var err error
+		if arch, err = util.GetArch(); err != nil {
+			return "", err
+		}
 	}
 	debSuffix := "-1" + "_" + arch + ".deb"
 	return debSuffix, nil
@@ -49,9 +49,12 @@ func createControlDir(cmdCtx cmdcontext.CmdCtx, packCtx PackCtx,
 	debControlCtx := map[string]interface{}{
 		"Name":         packCtx.Name,
 		"Version":      version,
-		"Maintainer":   defaultMaintainer,
-		"Architecture": runtime.GOARCH,
+		"Maintainer":   getPackageMaintainer(&packCtx),
+		"Homepage":     packCtx.RpmDeb.Homepage,
+		"Description":  formatDebDescription(getPackageDescription(&packCtx)),
+		"Architecture": getDebArch(&packCtx),
 		"Depends":      "",
+		"TtVersion":    debTtVersionField + ": " + getPackerVersion(),
 	}
 
 	deps, err := parseAllDependencies(&cmdCtx, &packCtx)
@@ -60,6 +63,14 @@ func createControlDir(cmdCtx cmdcontext.CmdCtx, packCtx PackCtx,
 	}
 	addDependenciesDeb(&debControlCtx, deps)
 
+	conflicts, provides, err := parsePackageRelations(&packCtx)
+	if err != nil {
+		return err
+	}
+	debControlCtx["Conflicts"] = formatDebDependencies(conflicts)
+	debControlCtx["Provides"] = formatDebDependencies(provides)
+	debControlCtx["Labels"] = getDebLabelFields(packCtx.labels)
+
 	err = createControlFile(destDirPath, &debControlCtx)
 	if err != nil {
 		return err
@@ -74,8 +85,8 @@ func createControlDir(cmdCtx cmdcontext.CmdCtx, packCtx PackCtx,
 			return err
 		}
 	} else {
-		err = copy.Copy(packCtx.RpmDeb.PreInst,
-			filepath.Join(destDirPath, PreInstScriptName))
+		err = writeMaintainerScript(destDirPath, PreInstScriptName,
+			packCtx.RpmDeb.PreInst, packCtx.RpmDeb.preInstScript)
 		if err != nil {
 			return err
 		}
@@ -86,8 +97,22 @@ func createControlDir(cmdCtx cmdcontext.CmdCtx, packCtx PackCtx,
 			return err
 		}
 	} else {
-		err = copy.Copy(packCtx.RpmDeb.PostInst,
-			filepath.Join(destDirPath, PostInstScriptName))
+		err = writeMaintainerScript(destDirPath, PostInstScriptName,
+			packCtx.RpmDeb.PostInst, packCtx.RpmDeb.postInstScript)
+		if err != nil {
+			return err
+		}
+	}
+	if packCtx.RpmDeb.PreRm != "" {
+		err = writeMaintainerScript(destDirPath, PreRmScriptName,
+			packCtx.RpmDeb.PreRm, packCtx.RpmDeb.preRmScript)
+		if err != nil {
+			return err
+		}
+	}
+	if packCtx.RpmDeb.PostRm != "" {
+		err = writeMaintainerScript(destDirPath, PostRmScriptName,
+			packCtx.RpmDeb.PostRm, packCtx.RpmDeb.postRmScript)
 		if err != nil {
 			return err
 		}
@@ -110,6 +135,11 @@ func getDebRelation(relation string) string {
 
 // addDependenciesDeb adds parsed dependencies to the passed map.
 func addDependenciesDeb(debControlCtx *map[string]interface{}, deps PackDependencies) {
+	(*debControlCtx)["Depends"] = formatDebDependencies(deps)
+}
+
+// formatDebDependencies returns parsed dependencies in deb control file format.
+func formatDebDependencies(deps PackDependencies) string {
 	var depsList []string
 
 	for _, dep := range deps {
@@ -123,7 +153,7 @@ func addDependenciesDeb(debControlCtx *map[string]interface{}, deps PackDependen
 		}
 	}
 
-	(*debControlCtx)["Depends"] = strings.Join(depsList, ", ")
+	return strings.Join(depsList, ", ")
 }
 
 // createControlFile creates a control file from template.
@@ -191,19 +221,56 @@ func initScript(destDirPath, scriptName string, mp map[string]interface{}) error
 	return nil
 }
 
+// writeMaintainerScript writes the rendered user script into the passed directory.
+// The script file is copied first to keep its permissions.
+func writeMaintainerScript(destDirPath, scriptName, scriptPath, content string) error {
+	dstPath := filepath.Join(destDirPath, scriptName)
+	if err := copy.Copy(scriptPath, dstPath); err != nil {
+		return err
+	}
+	return os.WriteFile(dstPath, []byte(content), filePermissions)
+}
+
 //go:embed templates/deb_preinst.sh
 var debPreInstScriptContent string
 
 const (
 	defaultMaintainer = "Tarantool developer"
+	// debTtVersionField is a control file field containing the version of tt packed
+	// the package.
+	debTtVersionField = "X-Tt-Version"
 
 	controlFileContent = `Package: {{ .Name }}
 Version: {{ .Version }}
 Maintainer: {{ .Maintainer }}
 Architecture: {{ .Architecture }}
-Description: Tarantool environment: {{ .Name }}
+Description: {{ .Description }}
 Depends: {{ .Depends }}
+{{- if .TtVersion }}
+{{ .TtVersion }}
+{{- end }}
+{{- if .Homepage }}
+Homepage: {{ .Homepage }}
+{{- end }}
+{{- if .Conflicts }}
+Conflicts: {{ .Conflicts }}
+{{- end }}
+{{- if .Provides }}
+Provides: {{ .Provides }}
+{{- end }}
+{{- range .Labels }}
+{{ . }}
+{{- end }}
 
 `
 	postInstScriptContent = ``
 )
+
+// getDebArch returns the architecture for a Deb package control file.
+// Depends on runtime.GOARCH constant if the architecture is not set.
+func getDebArch(packCtx *PackCtx) string {
+	if packCtx.RpmDeb.DebArch != "" {
+		return packCtx.RpmDeb.DebArch
+	}
+	return runtime.GOARCH
+}
@@ -1,6 +1,7 @@
 package pack
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -274,3 +275,76 @@ func TestInitScript(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateControlFileRelations(t *testing.T) {
+	basePath := t.TempDir()
+	err := createControlFile(basePath, &map[string]interface{}{
+		"Name":         "test",
+		"Version":      "1.0.0",
+		"Maintainer":   "dev",
+		"Architecture": "amd64",
+		"Description":  "Tarantool environment: test",
+		"Depends":      "tarantool",
+		"Conflicts":    "old (<< 2.0)",
+		"Provides":     formatDebDependencies(PackDependencies{{Name: "app"}}),
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(basePath, "control"))
+	require.NoError(t, err)
+	require.Equal(t, `Package: test
+Version: 1.0.0
+Maintainer: dev
+Architecture: amd64
+Description: Tarantool environment: test
+Depends: tarantool
+Conflicts: old (<< 2.0)
+Provides: app
+
+`, string(content))
+}
+
+func TestCreateControlDirRemoveScripts(t *testing.T) {
+	postRmPath := filepath.Join(t.TempDir(), "postrm.sh")
+	require.NoError(t, os.WriteFile(postRmPath, []byte("rm -rf {{ .Prefix }}"), 0755))
+	packCtx := PackCtx{
+		Name:    "test",
+		Version: "1.0.0",
+		RpmDeb:  RpmDebCtx{PostRm: postRmPath, postRmScript: "rm -rf /usr/share/tarantool"},
+	}
+
+	controlPath := filepath.Join(t.TempDir(), "control")
+	require.NoError(t, createControlDir(cmdcontext.CmdCtx{}, packCtx, &config.CliOpts{},
+		controlPath))
+	content, err := os.ReadFile(filepath.Join(controlPath, PostRmScriptName))
+	require.NoError(t, err)
+	require.Equal(t, "rm -rf /usr/share/tarantool", string(content))
+	require.NoFileExists(t, filepath.Join(controlPath, PreRmScriptName))
+}
+
+func TestCreateControlDirMetadata(t *testing.T) {
+	packCtx := PackCtx{
+		Name:    "test",
+		Version: "1.0.0",
+		RpmDeb: RpmDebCtx{Maintainer: "Jane Doe <jane@example.com>",
+			Homepage: "https://example.com", Description: "Test app\n\nLong description"},
+	}
+	controlPath := filepath.Join(t.TempDir(), "control")
+	require.NoError(t, createControlDir(cmdcontext.CmdCtx{}, packCtx, &config.CliOpts{},
+		controlPath))
+	content, err := os.ReadFile(filepath.Join(controlPath, "control"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "Maintainer: Jane Doe <jane@example.com>\n")
+	require.Contains(t, string(content), "Description: Test app\n .\n Long description\n")
+	require.Contains(t, string(content), "Homepage: https://example.com\n")
+	require.NotContains(t, string(content), debLabelFieldPrefix)
+	require.Contains(t, string(content), "\n"+debTtVersionField+": "+getPackerVersion()+"\n")
+
+	packCtx.labels = map[string]string{"pipeline": "https://ci.example.com/1", "build": "42"}
+	require.NoError(t, createControlDir(cmdcontext.CmdCtx{}, packCtx, &config.CliOpts{},
+		controlPath))
+	content, err = os.ReadFile(filepath.Join(controlPath, "control"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\nX-Tt-Label-build: 42\n"+
+		"X-Tt-Label-pipeline: https://ci.example.com/1\n")
+}
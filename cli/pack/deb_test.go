@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,3 +38,9 @@ func TestCreateDebianBinary(t *testing.T) {
 		})
 	}
 }
+
+func Test_getInstallPrefix(t *testing.T) {
+	assert.Equal(t, "usr/share/tarantool", getInstallPrefix(&PackCtx{}))
+	assert.Equal(t, "opt/company/tarantool", getInstallPrefix(&PackCtx{
+		RpmDeb: RpmDebCtx{InstallPrefix: "/opt/company/tarantool/"}}))
+}
@@ -0,0 +1,109 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// defaultDedupMinSize is a size of the smallest file replaced with a hardlink if the
+// threshold is not set. A hardlink entry takes a tar block, so the smaller files are
+// not worth hashing.
+const defaultDedupMinSize = 4096
+
+// dedupKey identifies the files replaceable with hardlinks to each other: the content
+// and the package file info must be the same, because the links share them.
+type dedupKey struct {
+	digest string
+	info   packFileInfo
+}
+
+// isDedupEnabled returns true if the duplicate files are packed as hardlinks.
+func isDedupEnabled(packCtx *PackCtx) bool {
+	return packCtx.Archive.Dedup && packCtx.Type == Tgz
+}
+
+// getDedupMinSize returns the size of the smallest file to deduplicate.
+func getDedupMinSize(packCtx *PackCtx) int64 {
+	if packCtx.Archive.dedupMinSize > 0 {
+		return packCtx.Archive.dedupMinSize
+	}
+	return defaultDedupMinSize
+}
+
+// hashFSFile returns the SHA256 checksum of the file system file content.
+func hashFSFile(fsys FileSystem, name string) (string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return sha256Hex(file)
+}
+
+// findDuplicateFiles returns the relative paths of the first files with the same content
+// keyed by the relative paths of their duplicates. Only the regular files not smaller
+// than the dedup threshold are hashed, up to the jobs count files concurrently.
+func findDuplicateFiles(packCtx *PackCtx, fsys FileSystem, files []collectedFile) (
+	map[string]string, error) {
+	minSize := getDedupMinSize(packCtx)
+	digests := make([]string, len(files))
+
+	ctx, cancel := context.WithCancel(packCtx.operation.context())
+	defer cancel()
+	sem := make(chan struct{}, getJobsCount(packCtx))
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var firstErr error
+	for i, file := range files {
+		if !file.info.Mode().IsRegular() || file.info.Size() < minSize {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, file collectedFile) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			digest, err := hashFSFile(fsys, file.name)
+			if err == nil {
+				digests[i] = digest
+				return
+			}
+			mutex.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to hash %q: %s", file.relPath, err)
+			}
+			mutex.Unlock()
+			cancel()
+		}(i, file)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := packCtx.operation.context().Err(); err != nil {
+		return nil, err
+	}
+
+	// The files are in the archive order, so the link target is written before the links.
+	firstFiles := map[dedupKey]string{}
+	duplicates := map[string]string{}
+	for i, file := range files {
+		if digests[i] == "" {
+			continue
+		}
+		key := dedupKey{digests[i], packCtx.RpmDeb.getPackFileInfo(file.relPath,
+			file.info.Mode())}
+		if first, found := firstFiles[key]; found {
+			duplicates[file.relPath] = first
+		} else {
+			firstFiles[key] = file.relPath
+		}
+	}
+	return duplicates, nil
+}
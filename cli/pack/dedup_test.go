@@ -0,0 +1,87 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTarArchiveDedup(t *testing.T) {
+	bundlePath := t.TempDir()
+	rock := strings.Repeat("rock content\n", 100)
+	files := []struct {
+		path    string
+		content string
+		perm    os.FileMode
+	}{
+		{"app1/.rocks/rock.lua", rock, 0644},
+		{"app2/.rocks/rock.lua", rock, 0644},
+		{"app3/.rocks/rock.lua", rock, 0755},
+		{"app1/small.lua", "small", 0644},
+		{"app2/small.lua", "small", 0644},
+	}
+	for _, file := range files {
+		path := filepath.Join(bundlePath, file.path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(file.content), file.perm))
+		require.NoError(t, os.Chmod(path, file.perm))
+	}
+
+	packCtx := &PackCtx{Type: Tgz, Archive: ArchiveCtx{Dedup: true, dedupMinSize: 100}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteTarArchive(bundlePath, &buf, packCtx))
+
+	links := map[string]string{}
+	sizes := map[string]int{}
+	tarReader := tar.NewReader(&buf)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeLink {
+			links[header.Name] = header.Linkname
+		} else if header.Typeflag == tar.TypeReg {
+			sizes[header.Name] = len(content)
+		}
+	}
+
+	// The file with other permissions and the files under the threshold are not linked.
+	assert.Equal(t, map[string]string{"app2/.rocks/rock.lua": "app1/.rocks/rock.lua"}, links)
+	assert.Equal(t, map[string]int{
+		"app1/.rocks/rock.lua": len(rock),
+		"app3/.rocks/rock.lua": len(rock),
+		"app1/small.lua":       5,
+		"app2/small.lua":       5,
+	}, sizes)
+
+	// Dedup is applied to tgz only.
+	packCtx.Type = Deb
+	buf.Reset()
+	require.NoError(t, WriteTarArchive(bundlePath, &buf, packCtx))
+	tarReader = tar.NewReader(&buf)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.NotEqual(t, byte(tar.TypeLink), header.Typeflag, header.Name)
+	}
+}
+
+func Test_getDedupMinSize(t *testing.T) {
+	assert.EqualValues(t, defaultDedupMinSize, getDedupMinSize(&PackCtx{}))
+	assert.EqualValues(t, 10,
+		getDedupMinSize(&PackCtx{Archive: ArchiveCtx{dedupMinSize: 10}}))
+}
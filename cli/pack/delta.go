@@ -0,0 +1,427 @@
+package pack
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/util"
+)
+
+const (
+	// deltaFileSuffix is a suffix of the delta file name.
+	deltaFileSuffix = ".delta"
+	// deltaMetadataName is a name of the first delta entry containing the delta metadata.
+	deltaMetadataName = ".tt-delta.json"
+	// deltaFormatVersion is a version of the delta format.
+	deltaFormatVersion = 1
+	// deltaBaseRecord is a PAX record of the delta entry which content is not stored
+	// in the delta. The value is a path of the base tarball file with the same content.
+	deltaBaseRecord = "TT_DELTA.base"
+	// deltaSizeRecord is a PAX record with the size of the content taken from the base.
+	deltaSizeRecord = "TT_DELTA.size"
+	// deltaSha256Record is a PAX record with the checksum of the content taken from
+	// the base.
+	deltaSha256Record = "TT_DELTA.sha256"
+)
+
+// deltaMetadata describes the delta between the base and the target tarballs.
+type deltaMetadata struct {
+	// Version is a version of the delta format.
+	Version int `json:"version"`
+	// Base is a file name of the base tarball.
+	Base string `json:"base"`
+	// BaseSha256 is a checksum of the base tarball file.
+	BaseSha256 string `json:"base_sha256"`
+	// Target is a file name of the tarball the delta is created for.
+	Target string `json:"target"`
+	// Compressor is a compressor of the target tarball.
+	Compressor string `json:"compressor"`
+	// CompressionLevel is a compression level of the target tarball.
+	CompressionLevel int `json:"compression_level"`
+}
+
+// initDeltaAgainst checks the previous tarball to create the delta against.
+func initDeltaAgainst(packCtx *PackCtx) error {
+	deltaAgainst, err := filepath.Abs(packCtx.Archive.DeltaAgainst)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of the previous tarball %q: %s",
+			packCtx.Archive.DeltaAgainst, err)
+	}
+	if !util.IsRegularFile(deltaAgainst) {
+		return fmt.Errorf("cannot create the delta against %q: not a regular file",
+			packCtx.Archive.DeltaAgainst)
+	}
+	packCtx.Archive.DeltaAgainst = deltaAgainst
+	return nil
+}
+
+// walkTarball calls the visitor for each entry of the compressed tarball file.
+// The reader passed to the visitor reads the entry content.
+func walkTarball(tarballPath string, visit func(header *tar.Header, reader io.Reader) error) error {
+	file, err := os.Open(tarballPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	decompressor, err := newDecompressor(file)
+	if err != nil {
+		return err
+	}
+	defer decompressor.Close()
+	tarReader := tar.NewReader(decompressor)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = visit(header, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// sha256Hex returns SHA256 checksum of the reader content in hex format.
+func sha256Hex(reader io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// getTarballFileHashes returns the paths of the tarball regular files by their content
+// checksums.
+func getTarballFileHashes(tarballPath string) (map[string]string, error) {
+	hashes := map[string]string{}
+	err := walkTarball(tarballPath, func(header *tar.Header, reader io.Reader) error {
+		if header.Typeflag != tar.TypeReg {
+			return nil
+		}
+		digest, err := sha256Hex(reader)
+		if err != nil {
+			return err
+		}
+		if _, found := hashes[digest]; !found {
+			hashes[digest] = header.Name
+		}
+		return nil
+	})
+	return hashes, err
+}
+
+// writeDelta writes the delta of the target tarball against the base tarball. The delta
+// is a tarball with all target entries in the same order, but the content of the files
+// found in the base tarball is replaced with the reference to the base file.
+func writeDelta(packCtx *PackCtx, baseTarball, targetPath string, writer io.Writer) error {
+	baseSha256, err := util.FileSHA256Hex(baseTarball)
+	if err != nil {
+		return err
+	}
+	baseFiles, err := getTarballFileHashes(baseTarball)
+	if err != nil {
+		return fmt.Errorf("failed to read the previous tarball %q: %s", baseTarball, err)
+	}
+	// The target file checksums are computed first to decide which content is stored.
+	targetHashes := []string{}
+	err = walkTarball(targetPath, func(header *tar.Header, reader io.Reader) error {
+		if header.Typeflag != tar.TypeReg {
+			return nil
+		}
+		digest, err := sha256Hex(reader)
+		targetHashes = append(targetHashes, digest)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read the tarball %q: %s", targetPath, err)
+	}
+
+	compressor, err := getCompressor(packCtx.Archive.Compressor)
+	if err != nil {
+		return err
+	}
+	compressWriter, err := compressor.newWriter(writer, packCtx.Archive.CompressionLevel)
+	if err != nil {
+		return fmt.Errorf("failed to create %s writer: %s", strings.ToUpper(compressor.name),
+			err)
+	}
+	defer compressWriter.Close()
+	tarWriter := tar.NewWriter(compressWriter)
+
+	metadata, err := json.MarshalIndent(deltaMetadata{
+		Version:          deltaFormatVersion,
+		Base:             filepath.Base(baseTarball),
+		BaseSha256:       baseSha256,
+		Target:           filepath.Base(targetPath),
+		Compressor:       compressor.name,
+		CompressionLevel: packCtx.Archive.CompressionLevel,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = tarWriter.WriteHeader(&tar.Header{Name: deltaMetadataName, Typeflag: tar.TypeReg,
+		Mode: 0644, Size: int64(len(metadata)), ModTime: getBuildTime(packCtx)})
+	if err != nil {
+		return err
+	}
+	if _, err = tarWriter.Write(metadata); err != nil {
+		return err
+	}
+
+	stored, referenced := 0, 0
+	err = walkTarball(targetPath, func(header *tar.Header, reader io.Reader) error {
+		if header.Typeflag == tar.TypeReg {
+			digest := targetHashes[stored+referenced]
+			if basePath, found := baseFiles[digest]; found {
+				if header.PAXRecords == nil {
+					header.PAXRecords = map[string]string{}
+				}
+				header.PAXRecords[deltaBaseRecord] = basePath
+				header.PAXRecords[deltaSizeRecord] = fmt.Sprint(header.Size)
+				header.PAXRecords[deltaSha256Record] = digest
+				header.Size = 0
+				header.Format = tar.FormatPAX
+				referenced++
+				return tarWriter.WriteHeader(header)
+			}
+			stored++
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := io.Copy(tarWriter, reader)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write the delta: %s", err)
+	}
+	log.Infof("The delta stores %d changed files and references %d files of %s.",
+		stored, referenced, filepath.Base(baseTarball))
+
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	return compressWriter.Close()
+}
+
+// writeDeltaFile writes the delta of the target tarball against the previous tarball
+// from the pack context next to the target tarball. Returns the delta file path.
+func writeDeltaFile(packCtx *PackCtx, targetPath string) (string, error) {
+	deltaPath := targetPath + deltaFileSuffix
+	log.Infof("Creating the delta against %s.", packCtx.Archive.DeltaAgainst)
+	err := writePackageFile(packCtx, deltaPath, func(tmpPath string) error {
+		file, err := os.Create(tmpPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err = writeDelta(packCtx, packCtx.Archive.DeltaAgainst, targetPath,
+			file); err != nil {
+			return err
+		}
+		return file.Close()
+	})
+	if err != nil {
+		return "", err
+	}
+	log.Infof("The delta is written to %s.", deltaPath)
+	return deltaPath, nil
+}
+
+// readDeltaMetadata reads the metadata of the delta file.
+func readDeltaMetadata(deltaPath string) (deltaMetadata, error) {
+	metadata := deltaMetadata{}
+	errFound := errors.New("found")
+	err := walkTarball(deltaPath, func(header *tar.Header, reader io.Reader) error {
+		if header.Name != deltaMetadataName {
+			return fmt.Errorf("the first entry is not %s", deltaMetadataName)
+		}
+		if err := json.NewDecoder(reader).Decode(&metadata); err != nil {
+			return fmt.Errorf("failed to parse %s: %s", deltaMetadataName, err)
+		}
+		return errFound
+	})
+	if err == nil {
+		err = fmt.Errorf("%s is not found", deltaMetadataName)
+	}
+	if err != errFound {
+		return metadata, fmt.Errorf("invalid delta %q: %s", deltaPath, err)
+	}
+	if metadata.Version != deltaFormatVersion {
+		return metadata, fmt.Errorf("invalid delta %q: unsupported format version %d",
+			deltaPath, metadata.Version)
+	}
+	return metadata, nil
+}
+
+// extractBaseFiles extracts the base tarball files referenced by the delta into
+// the directory. Returns the extracted file paths by the base paths.
+func extractBaseFiles(baseTarball, deltaPath, dir string) (map[string]string, error) {
+	extracted := map[string]string{}
+	err := walkTarball(deltaPath, func(header *tar.Header, reader io.Reader) error {
+		if basePath, found := header.PAXRecords[deltaBaseRecord]; found {
+			extracted[basePath] = ""
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the delta %q: %s", deltaPath, err)
+	}
+
+	err = walkTarball(baseTarball, func(header *tar.Header, reader io.Reader) error {
+		path, found := extracted[header.Name]
+		if header.Typeflag != tar.TypeReg || !found || path != "" {
+			return nil
+		}
+		file, err := os.CreateTemp(dir, "base")
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if _, err = io.Copy(file, reader); err != nil {
+			return err
+		}
+		extracted[header.Name] = file.Name()
+		return file.Close()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the base tarball %q: %s", baseTarball, err)
+	}
+	for basePath, path := range extracted {
+		if path == "" {
+			return nil, fmt.Errorf("file %s referenced by the delta is not found in the base"+
+				" tarball %q", basePath, baseTarball)
+		}
+	}
+	return extracted, nil
+}
+
+// writeBaseContent writes the content of the extracted base file. The content checksum
+// must match the checksum stored in the delta.
+func writeBaseContent(writer io.Writer, extractedPath, digest string) error {
+	file, err := os.Open(extractedPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(writer, hash), file); err != nil {
+		return err
+	}
+	if fmt.Sprintf("%x", hash.Sum(nil)) != digest {
+		return fmt.Errorf("checksum mismatch")
+	}
+	return nil
+}
+
+// writeDeltaTarget writes the target tarball reconstructed from the base tarball files
+// and the delta.
+func writeDeltaTarget(metadata deltaMetadata, deltaPath string, extracted map[string]string,
+	writer io.Writer) error {
+	compressor, err := getCompressor(metadata.Compressor)
+	if err != nil {
+		return err
+	}
+	compressWriter, err := compressor.newWriter(writer, metadata.CompressionLevel)
+	if err != nil {
+		return fmt.Errorf("failed to create %s writer: %s", strings.ToUpper(compressor.name),
+			err)
+	}
+	defer compressWriter.Close()
+	tarWriter := tar.NewWriter(compressWriter)
+
+	err = walkTarball(deltaPath, func(header *tar.Header, reader io.Reader) error {
+		if header.Name == deltaMetadataName {
+			return nil
+		}
+		basePath, found := header.PAXRecords[deltaBaseRecord]
+		if !found {
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return err
+			}
+			_, err := io.Copy(tarWriter, reader)
+			return err
+		}
+		if _, err := fmt.Sscan(header.PAXRecords[deltaSizeRecord], &header.Size); err != nil {
+			return fmt.Errorf("invalid size of %s: %s", header.Name, err)
+		}
+		digest := header.PAXRecords[deltaSha256Record]
+		for _, record := range []string{deltaBaseRecord, deltaSizeRecord, deltaSha256Record} {
+			delete(header.PAXRecords, record)
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if err := writeBaseContent(tarWriter, extracted[basePath], digest); err != nil {
+			return fmt.Errorf("failed to write %s from the base file %s: %s", header.Name,
+				basePath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	return compressWriter.Close()
+}
+
+// ApplyDelta reconstructs the tarball from the base tarball and the delta created with
+// --delta-against option. The tarball is written to the output path or next to the delta
+// file with the original tarball name if the output path is not set. Returns the path of
+// the reconstructed tarball.
+func ApplyDelta(basePath, deltaPath, outputPath string) (string, error) {
+	metadata, err := readDeltaMetadata(deltaPath)
+	if err != nil {
+		return "", err
+	}
+	baseSha256, err := util.FileSHA256Hex(basePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the base tarball %q: %s", basePath, err)
+	}
+	if baseSha256 != metadata.BaseSha256 {
+		return "", fmt.Errorf("the delta %q is created against another tarball %s",
+			deltaPath, metadata.Base)
+	}
+	if outputPath == "" {
+		outputPath = filepath.Join(filepath.Dir(deltaPath), metadata.Target)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "tt_delta")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	extracted, err := extractBaseFiles(basePath, deltaPath, tmpDir)
+	if err != nil {
+		return "", err
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+".tmp")
+	output, err := os.Create(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the tarball %s: %s", outputPath, err)
+	}
+	defer os.Remove(tmpPath)
+	defer output.Close()
+	if err = writeDeltaTarget(metadata, deltaPath, extracted, output); err != nil {
+		return "", fmt.Errorf("failed to apply the delta %q: %s", deltaPath, err)
+	}
+	if err = output.Close(); err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmpPath, outputPath); err != nil {
+		return "", fmt.Errorf("failed to create the tarball %s: %s", outputPath, err)
+	}
+	return outputPath, nil
+}
@@ -0,0 +1,120 @@
+package pack
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readTestTarball returns the tarball entries in writeTestTgz format.
+func readTestTarball(t *testing.T, tarballPath string) [][2]string {
+	entries := [][2]string{}
+	err := walkTarball(tarballPath, func(header *tar.Header, reader io.Reader) error {
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		entry := [2]string{header.Name, string(content)}
+		if header.Typeflag == tar.TypeSymlink {
+			entry[1] = "->" + header.Linkname
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	require.NoError(t, err)
+	return entries
+}
+
+// writeTestDelta writes the delta of the target tarball against the base tarball.
+func writeTestDelta(t *testing.T, basePath, targetPath string) string {
+	packCtx := &PackCtx{Archive: ArchiveCtx{CompressionLevel: DefaultCompressionLevel}}
+	deltaPath := filepath.Join(t.TempDir(), "bundle.tar.gz.delta")
+	file, err := os.Create(deltaPath)
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, writeDelta(packCtx, basePath, targetPath, file))
+	return deltaPath
+}
+
+func TestApplyDelta(t *testing.T) {
+	bigContent := strings.Repeat("unchanged content\n", 1000)
+	basePath := writeTestTgz(t, [][2]string{
+		{"bin/", ""},
+		{"bin/tarantool", bigContent},
+		{"app/", ""},
+		{"app/init.lua", "old app"},
+		{"app/removed.lua", "removed"},
+		{"app/moved.lua", "moved"},
+	})
+	targetEntries := [][2]string{
+		{"bin/", ""},
+		{"bin/tarantool", bigContent},
+		{"app/", ""},
+		{"app/init.lua", "new app"},
+		{"app/lib/moved.lua", "moved"},
+		{"app/added.lua", "added"},
+		{"instances.enabled/app", "->../app"},
+	}
+	targetPath := writeTestTgz(t, targetEntries)
+	deltaPath := writeTestDelta(t, basePath, targetPath)
+
+	// Only the changed and added files content is stored in the delta.
+	stored := []string{}
+	err := walkTarball(deltaPath, func(header *tar.Header, reader io.Reader) error {
+		if header.Typeflag == tar.TypeReg && header.Size > 0 {
+			stored = append(stored, header.Name)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{deltaMetadataName, "app/init.lua", "app/added.lua"}, stored)
+
+	metadata, err := readDeltaMetadata(deltaPath)
+	require.NoError(t, err)
+	assert.Equal(t, "base.tar.gz", metadata.Target)
+	assert.Equal(t, CompressorGzip, metadata.Compressor)
+
+	outputPath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	tarballPath, err := ApplyDelta(basePath, deltaPath, outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, outputPath, tarballPath)
+	assert.Equal(t, targetEntries, readTestTarball(t, tarballPath))
+
+	// The original tarball name is used next to the delta if the output is not set.
+	tarballPath, err = ApplyDelta(basePath, deltaPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(deltaPath), "base.tar.gz"), tarballPath)
+	assert.Equal(t, targetEntries, readTestTarball(t, tarballPath))
+}
+
+func TestApplyDeltaErrors(t *testing.T) {
+	basePath := writeTestTgz(t, [][2]string{{"init.lua", "base"}, {"lib.lua", "lib"}})
+	targetPath := writeTestTgz(t, [][2]string{{"init.lua", "target"}, {"lib.lua", "lib"}})
+	deltaPath := writeTestDelta(t, basePath, targetPath)
+
+	_, err := ApplyDelta(targetPath, deltaPath, filepath.Join(t.TempDir(), "out.tar.gz"))
+	assert.ErrorContains(t, err, "is created against another tarball base.tar.gz")
+
+	_, err = ApplyDelta(basePath, targetPath, filepath.Join(t.TempDir(), "out.tar.gz"))
+	assert.ErrorContains(t, err, "the first entry is not "+deltaMetadataName)
+
+	emptyPath := writeTestTgz(t, [][2]string{})
+	_, err = ApplyDelta(basePath, emptyPath, filepath.Join(t.TempDir(), "out.tar.gz"))
+	assert.ErrorContains(t, err, deltaMetadataName+" is not found")
+}
+
+func Test_initDeltaAgainst(t *testing.T) {
+	packCtx := &PackCtx{Archive: ArchiveCtx{DeltaAgainst: t.TempDir()}}
+	assert.ErrorContains(t, initDeltaAgainst(packCtx), "not a regular file")
+
+	tgzPath := writeTestTgz(t, [][2]string{{"init.lua", "box.cfg{}"}})
+	packCtx.Archive.DeltaAgainst = tgzPath
+	require.NoError(t, initDeltaAgainst(packCtx))
+	assert.Equal(t, tgzPath, packCtx.Archive.DeltaAgainst)
+}
@@ -44,6 +44,19 @@ func parseAllDependencies(cmdCtx *cmdcontext.CmdCtx,
 	return deps, nil
 }
 
+// parsePackageRelations parses conflicts and provides lists of the package.
+func parsePackageRelations(packCtx *PackCtx) (PackDependencies, PackDependencies, error) {
+	conflicts, err := parseDependencies(packCtx.RpmDeb.Conflicts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid conflicts: %s", err)
+	}
+	provides, err := parseDependencies(packCtx.RpmDeb.Provides)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid provides: %s", err)
+	}
+	return conflicts, provides, nil
+}
+
 // parseDependencies accepts a slice of strings and parses dependencies from it.
 func parseDependencies(rawDeps []string) (PackDependencies, error) {
 	parser := participle.MustBuild(
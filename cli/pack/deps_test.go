@@ -185,3 +185,20 @@ func TestParseDependencies(t *testing.T) {
 		})
 	}
 }
+
+func Test_parsePackageRelations(t *testing.T) {
+	packCtx := PackCtx{RpmDeb: RpmDebCtx{
+		Conflicts: []string{"old_app<2.0"},
+		Provides:  []string{"app=2.0.0"},
+	}}
+	conflicts, provides, err := parsePackageRelations(&packCtx)
+	require.NoError(t, err)
+	require.Equal(t, PackDependencies{{Name: "old_app",
+		Relations: []DepRelation{{Relation: "<", Version: "2.0"}}}}, conflicts)
+	require.Equal(t, PackDependencies{{Name: "app",
+		Relations: []DepRelation{{Relation: "=", Version: "2.0.0"}}}}, provides)
+
+	packCtx.RpmDeb.Provides = []string{"app=>2.0"}
+	_, _, err = parsePackageRelations(&packCtx)
+	require.ErrorContains(t, err, "invalid provides")
+}
@@ -0,0 +1,125 @@
+package pack
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/apex/log"
+)
+
+// scpScheme is a URL scheme of the remote package destination.
+const scpScheme = "scp"
+
+// scpDestination is a remote location to upload the result package to.
+type scpDestination struct {
+	// user is a remote user name. The ssh client default is used if it is empty.
+	user string
+	// host is a remote host name.
+	host string
+	// port is a remote ssh port. The ssh client default is used if it is empty.
+	port string
+	// path is a remote file or directory path. The remote user home directory is used
+	// if it is empty.
+	path string
+}
+
+// parseDestination parses the scp://[user@]host[:port][/path] destination URL.
+func parseDestination(destination string) (scpDestination, error) {
+	destURL, err := url.Parse(destination)
+	if err != nil {
+		return scpDestination{}, fmt.Errorf("invalid destination %q: %s", destination, err)
+	}
+	if destURL.Scheme != scpScheme {
+		return scpDestination{}, fmt.Errorf("invalid destination %q: unsupported scheme %q,"+
+			" expected scp://[user@]host[:port][/path]", destination, destURL.Scheme)
+	}
+	if destURL.Hostname() == "" {
+		return scpDestination{}, fmt.Errorf("invalid destination %q: host is not set",
+			destination)
+	}
+	if _, isSet := destURL.User.Password(); isSet {
+		return scpDestination{}, fmt.Errorf("invalid destination %q: password "+
+			"authentication is not supported, use ssh agent or --identity", destination)
+	}
+	return scpDestination{
+		user: destURL.User.Username(),
+		host: destURL.Hostname(),
+		port: destURL.Port(),
+		path: destURL.Path,
+	}, nil
+}
+
+// target returns the destination in [user@]host:path format accepted by scp.
+func (dest scpDestination) target() string {
+	host := dest.host
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if dest.user != "" {
+		host = dest.user + "@" + host
+	}
+	return host + ":" + dest.path
+}
+
+// initDestination checks the remote destination and the identity file.
+func initDestination(packCtx *PackCtx) error {
+	dest, err := parseDestination(packCtx.Destination)
+	if err != nil {
+		return err
+	}
+	if packCtx.Identity != "" {
+		if _, err = os.Stat(packCtx.Identity); err != nil {
+			return fmt.Errorf("cannot use identity file %q: %s", packCtx.Identity, err)
+		}
+	}
+	packCtx.destination = &dest
+	return nil
+}
+
+// getScpArgs returns scp arguments to upload the files to the destination.
+func getScpArgs(dest scpDestination, identity string, files []string) []string {
+	args := []string{}
+	if identity != "" {
+		args = append(args, "-i", identity)
+	}
+	if dest.port != "" {
+		args = append(args, "-P", dest.port)
+	}
+	args = append(args, "--")
+	args = append(args, files...)
+	return append(args, dest.target())
+}
+
+// UploadPackage uploads the created package to the remote destination using scp.
+// Authentication uses ssh agent or the identity file. The local package is kept.
+func UploadPackage(packCtx *PackCtx) error {
+	if packCtx.destination == nil {
+		return nil
+	}
+	if packCtx.artifactPath == "" {
+		return fmt.Errorf("cannot upload the package: result package is unknown")
+	}
+
+	files := append([]string{packCtx.artifactPath}, packCtx.Archive.volumes...)
+	if packCtx.WithChecksum {
+		checksumPath := packCtx.artifactPath
+		if len(packCtx.Archive.volumes) > 0 {
+			// The checksum of the split tarball is written for the joined tarball.
+			checksumPath = strings.TrimSuffix(checksumPath, splitIndexSuffix)
+		}
+		files = append(files, checksumPath+checksumFileSuffix)
+	}
+	log.Infof("Uploading %s to %s.", packCtx.artifactPath, packCtx.Destination)
+	scpCmd := exec.Command("scp", getScpArgs(*packCtx.destination, packCtx.Identity,
+		files)...)
+	scpCmd.Stdout = getCmdStdout(packCtx)
+	scpCmd.Stderr = os.Stderr
+	if err := scpCmd.Run(); err != nil {
+		return fmt.Errorf("failed to upload the package to %q: %s, the package is kept "+
+			"at %s", packCtx.Destination, err, packCtx.artifactPath)
+	}
+	return nil
+}
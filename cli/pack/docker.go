@@ -45,10 +45,33 @@ func getTarantoolVersionForInstall(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx)
 	return
 }
 
+// getDockerPackArgs returns the tt pack arguments to pass into the container. The host
+// specific --use-docker, --tarantool-version, --output-dir and --tmp-dir flags are removed
+// in both "--flag value" and "--flag=value" forms.
+func getDockerPackArgs(cmdArgs []string) []string {
+	args := make([]string, 0, len(cmdArgs))
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		name, _, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--use-docker":
+			continue
+		case "--tarantool-version", "--output-dir", "--tmp-dir":
+			if !hasValue {
+				// Skip the flag value.
+				i++
+			}
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
 // PackInDocker runs tt pack in docker container.
 func PackInDocker(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	opts config.CliOpts, cmdArgs []string) error {
-	tmpDir, err := os.MkdirTemp("", "docker_pack_ctx")
+	tmpDir, err := os.MkdirTemp(getTmpDir(packCtx), "docker_pack_ctx")
 	if err != nil {
 		return err
 	}
@@ -81,22 +104,8 @@ func PackInDocker(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		return err
 	}
 
-	// Remove --use-docker and --tarantool-version from args.
-	for i := 0; i < len(cmdArgs); {
-		arg := cmdArgs[i]
-		if arg == "--use-docker" {
-			cmdArgs = append(cmdArgs[:i], cmdArgs[i+1:]...)
-			continue
-		}
-		if arg == "--tarantool-version" {
-			cmdArgs = append(cmdArgs[:i], cmdArgs[i+2:]...)
-			continue
-		}
-		i++
-	}
-
 	// Generate pack command line for tt in container.
-	ttPackCommandLine := append([]string{"tt"}, cmdArgs[1:]...)
+	ttPackCommandLine := append([]string{"tt"}, getDockerPackArgs(cmdArgs)[1:]...)
 
 	// If bin_dir is not empty, we need to pack binaries built in container.
 	relEnvBinPath := configure.BinPath
@@ -130,25 +139,27 @@ func PackInDocker(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		},
 		Verbose: true,
 	}
-	if err = docker.RunContainer(dockerRunOptions, os.Stdout); err != nil {
+	if err = docker.RunContainer(dockerRunOptions, getCmdStdout(packCtx)); err != nil {
 		return err
 	}
 
 	skipRegularFilesFunc := func(srcInfo os.FileInfo, src, dest string) (bool, error) {
 		switch filepath.Ext(srcInfo.Name()) {
-		case ".deb", ".rpm", ".gz":
+		case ".deb", ".rpm", ".gz", ".zip", checksumFileSuffix:
 			return false, nil
 		default:
 			return true, nil
 		}
 	}
 
-	curDir, err := os.Getwd()
-	if err != nil {
-		return err
+	outputDir := packCtx.OutputDir
+	if outputDir == "" {
+		if outputDir, err = os.Getwd(); err != nil {
+			return err
+		}
 	}
 
-	err = copy.Copy(tempEnvDir, curDir, copy.Options{Skip: skipRegularFilesFunc})
+	err = copy.Copy(tempEnvDir, outputDir, copy.Options{Skip: skipRegularFilesFunc})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,115 @@
+package pack
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/templates"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// DefaultImageBase is a default base image for docker image packing.
+const DefaultImageBase = "ubuntu:22.04"
+
+//go:embed templates/Dockerfile.pack.image
+var imageDockerfile string
+
+// dockerImagePacker is a structure that implements Packer interface
+// with specific docker image building behavior.
+type dockerImagePacker struct {
+}
+
+// getImageTag returns the result image tag.
+func getImageTag(packCtx *PackCtx, opts *config.CliOpts) string {
+	if packCtx.Image.Tag != "" {
+		return packCtx.Image.Tag
+	}
+	return fmt.Sprintf("%s:%s", strings.ToLower(packCtx.Name),
+		getVersion(packCtx, opts, defaultVersion))
+}
+
+// genImageDockerfile generates Dockerfile of the result image.
+func genImageDockerfile(packCtx *PackCtx, opts *config.CliOpts) (string, error) {
+	envPath := filepath.Join("/", defaultEnvPrefix, packCtx.Name)
+	copyDest := envPath
+	if opts.Env.InstancesEnabled == "." || packCtx.CartridgeCompat {
+		copyDest = filepath.Dir(copyDest)
+	}
+
+	from := packCtx.Image.From
+	if from == "" {
+		from = DefaultImageBase
+	}
+
+	return templates.NewDefaultEngine().RenderText(imageDockerfile, map[string]string{
+		"from":      from,
+		"copy_dest": copyDest,
+		"env_path":  envPath,
+		"tt":        getTTBinary(packCtx, envPath),
+	})
+}
+
+// Run of dockerImagePacker builds docker image containing the bundle.
+func (packer *dockerImagePacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
+	opts *config.CliOpts) error {
+	if err := util.CheckRequiredBinaries("docker"); err != nil {
+		return fmt.Errorf("docker is required to build an image: %s", err)
+	}
+
+	// The image must contain tarantool and tt binaries, even if tarantool is system.
+	imagePackCtx := *packCtx
+	if !imagePackCtx.WithoutBinaries {
+		imagePackCtx.WithBinaries = true
+	}
+
+	bundlePath, err := prepareBundle(cmdCtx, &imagePackCtx, opts, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := os.RemoveAll(bundlePath)
+		if err != nil {
+			log.Warnf("Failed to remove a temporary directory %s: %s",
+				bundlePath, err.Error())
+		}
+	}()
+
+	log.Debugf("The package structure is created in: %s", bundlePath)
+
+	dockerfileText, err := genImageDockerfile(&imagePackCtx, opts)
+	if err != nil {
+		return err
+	}
+	// Dockerfile is written out of the build context to not get into the image.
+	dockerfileDir, err := os.MkdirTemp(getTmpDir(packCtx), "tt_pack_image")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dockerfileDir)
+	dockerfilePath := filepath.Join(dockerfileDir, "Dockerfile")
+	if err = os.WriteFile(dockerfilePath, []byte(dockerfileText), 0664); err != nil {
+		return err
+	}
+
+	imageTag := getImageTag(&imagePackCtx, opts)
+	log.Infof("Building docker image %s.", imageTag)
+
+	buildCmd := exec.Command("docker", "build", "--file", dockerfilePath, "--tag", imageTag,
+		bundlePath)
+	buildCmd.Stdout = getCmdStdout(packCtx)
+	buildCmd.Stderr = os.Stderr
+	if err = buildCmd.Run(); err != nil {
+		return fmt.Errorf("failed to build docker image: %s", err)
+	}
+
+	packCtx.artifactPath = imageTag
+	log.Infof("Created result docker image: %s", imageTag)
+	return nil
+}
@@ -0,0 +1,52 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_getImageTag(t *testing.T) {
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	assert.Equal(t, "bundle:1.0.0", getImageTag(&PackCtx{Name: "Bundle", Version: "1.0.0"},
+		opts))
+	assert.Equal(t, "registry/app:latest", getImageTag(&PackCtx{Name: "bundle",
+		Image: ImageCtx{Tag: "registry/app:latest"}}, opts))
+}
+
+func Test_genImageDockerfile(t *testing.T) {
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	dockerfile, err := genImageDockerfile(&PackCtx{Name: "bundle", WithBinaries: true}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, `FROM ubuntu:22.04
+
+COPY . /usr/share/tarantool/bundle
+
+WORKDIR /usr/share/tarantool/bundle
+
+ENTRYPOINT ["/usr/share/tarantool/bundle/bin/tt", "start", "-i"]
+`, dockerfile)
+
+	opts.Env.InstancesEnabled = "."
+	dockerfile, err = genImageDockerfile(&PackCtx{Name: "app", WithoutBinaries: true,
+		Image: ImageCtx{From: "debian:12"}}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, `FROM debian:12
+
+COPY . /usr/share/tarantool
+
+WORKDIR /usr/share/tarantool/app
+
+ENTRYPOINT ["tt", "start", "-i"]
+`, dockerfile)
+}
+
+func Test_dockerImagePackerNoDocker(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	packer := dockerImagePacker{}
+	err := packer.Run(&cmdcontext.CmdCtx{}, &PackCtx{}, &config.CliOpts{})
+	assert.ErrorContains(t, err, "docker is required to build an image")
+}
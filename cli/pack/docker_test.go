@@ -34,3 +34,14 @@ func Test_convertVersionToStringForInstall(t *testing.T) {
 	assert.Equal(t, "3.0.0-alpha2", getVersionStringForInstall(ver))
 
 }
+
+func Test_getDockerPackArgs(t *testing.T) {
+	args := []string{"pack", "tgz", "--use-docker", "--output-dir", "/host/out",
+		"--name", "app", "--tarantool-version=2.11.1", "--output-dir=/host/out2",
+		"--tmp-dir", "/host/tmp", "--tmp-dir=/host/tmp2", "--use-docker=true",
+		"--tarantool-version", "3.0.0", "--version", "1.0.0"}
+	assert.Equal(t, []string{"pack", "tgz", "--name", "app", "--version", "1.0.0"},
+		getDockerPackArgs(args))
+	// The passed args are not modified.
+	assert.Equal(t, "--use-docker", args[2])
+}
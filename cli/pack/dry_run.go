@@ -0,0 +1,60 @@
+package pack
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+// DryRun prepares the bundle the same way as packers do and prints the list
+// of files to be packed with their sizes. The result package is not created.
+func DryRun(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, opts *config.CliOpts,
+	writer io.Writer) error {
+	bundlePath, err := prepareBundle(cmdCtx, packCtx, opts, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := os.RemoveAll(bundlePath)
+		if err != nil {
+			log.Warnf("Failed to remove a temporary directory %s: %s",
+				bundlePath, err.Error())
+		}
+	}()
+
+	return printBundleFiles(bundlePath, writer)
+}
+
+// printBundleFiles prints paths relative to the bundle root and sizes of all the bundle
+// files followed by the total size.
+func printBundleFiles(bundlePath string, writer io.Writer) error {
+	var filesCount, totalSize int64
+	err := filepath.Walk(bundlePath, func(filePath string, fileInfo os.FileInfo,
+		err error) error {
+		if err != nil {
+			return err
+		}
+		if fileInfo.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(bundlePath, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path of %q: %s", filePath, err)
+		}
+		fmt.Fprintf(writer, "%12d  %s\n", fileInfo.Size(), relPath)
+		filesCount++
+		totalSize += fileInfo.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(writer, "Total: %d files, %d bytes\n", filesCount, totalSize)
+	return nil
+}
@@ -0,0 +1,29 @@
+package pack
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+)
+
+func Test_printBundleFiles(t *testing.T) {
+	bundleDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(bundleDir, []string{"app", "bin"}))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "app", "init.lua"),
+		[]byte("return 1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "tt.yaml"),
+		[]byte("env: {}\n"), 0644))
+
+	var out bytes.Buffer
+	require.NoError(t, printBundleFiles(bundleDir, &out))
+	assert.Equal(t,
+		"           8  app/init.lua\n"+
+			"           8  tt.yaml\n"+
+			"Total: 2 files, 16 bytes\n",
+		out.String())
+}
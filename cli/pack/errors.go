@@ -0,0 +1,44 @@
+package pack
+
+import "errors"
+
+var (
+	// ErrNoApps is reported if there are no applications to pack.
+	ErrNoApps = errors.New("there are no apps found")
+	// ErrUnsupportedType is reported if the package type is not supported.
+	ErrUnsupportedType = errors.New("unsupported package type")
+	// ErrBinaryNotFound is reported if the binary requested to include into the package
+	// is not found.
+	ErrBinaryNotFound = errors.New("binary is not found")
+	// ErrTimeout is reported if the pack operation is not completed in time.
+	ErrTimeout = errors.New("pack operation timed out")
+	// ErrInterrupted is reported if the pack operation is interrupted by a signal.
+	ErrInterrupted = errors.New("pack operation is interrupted")
+	// ErrNoSpace is reported if the file system is full while the package is written.
+	ErrNoSpace = errors.New("not enough disk space")
+	// ErrLocked is reported if the package is being written by another pack operation.
+	ErrLocked = errors.New("package is being written by another pack operation")
+	// ErrIntegrity is reported if the bundle files do not match the integrity index.
+	ErrIntegrity = errors.New("bundle integrity check failed")
+)
+
+// binaryError is an error of the binary lookup or check. It matches ErrBinaryNotFound
+// keeping the error text as is.
+type binaryError struct {
+	err error
+}
+
+// Error returns the error text.
+func (binaryErr *binaryError) Error() string {
+	return binaryErr.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (binaryErr *binaryError) Unwrap() error {
+	return binaryErr.err
+}
+
+// Is returns true for ErrBinaryNotFound.
+func (binaryErr *binaryError) Is(target error) bool {
+	return target == ErrBinaryNotFound
+}
@@ -0,0 +1,70 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// extraFile is a file copied into the bundle.
+type extraFile struct {
+	// src is an absolute path of the source file.
+	src string
+	// dst is a destination path relative to the bundle environment directory.
+	dst string
+}
+
+// parseExtraFiles parses src:dst mappings of the extra files. Source files must exist.
+func parseExtraFiles(mappings []string) ([]extraFile, error) {
+	extraFiles := make([]extraFile, 0, len(mappings))
+	for _, mapping := range mappings {
+		src, dst, found := strings.Cut(mapping, ":")
+		if !found || src == "" || dst == "" {
+			return nil, fmt.Errorf("invalid extra file %q: expected src:dst", mapping)
+		}
+		dst = filepath.Clean(dst)
+		if !filepath.IsLocal(dst) {
+			return nil, fmt.Errorf("invalid extra file %q: destination must be a path "+
+				"inside the bundle", mapping)
+		}
+		absSrc, err := filepath.Abs(src)
+		if err != nil {
+			return nil, err
+		}
+		if !util.IsRegularFile(absSrc) {
+			return nil, fmt.Errorf("invalid extra file %q: %s is not a regular file",
+				mapping, src)
+		}
+		extraFiles = append(extraFiles, extraFile{src: absSrc, dst: dst})
+	}
+	return extraFiles, nil
+}
+
+// copyExtraFiles copies the extra files into the bundle environment directory.
+// The existing bundle paths are overwritten only if Force is set.
+func copyExtraFiles(packCtx *PackCtx, bundleEnvPath string) error {
+	for _, extra := range packCtx.extraFiles {
+		dst := filepath.Join(bundleEnvPath, extra.dst)
+		if _, err := os.Lstat(dst); err == nil {
+			if !packCtx.Force {
+				return fmt.Errorf("extra file %s collides with the bundle path %s, "+
+					"use --force to overwrite it", extra.src, extra.dst)
+			}
+			if err = os.RemoveAll(dst); err != nil {
+				return err
+			}
+		}
+		log.Debugf("Copying extra file %q -> %q", extra.src, dst)
+		if err := os.MkdirAll(filepath.Dir(dst), dirPermissions); err != nil {
+			return err
+		}
+		if err := util.CopyFilePreserve(extra.src, dst); err != nil {
+			return fmt.Errorf("failed to copy extra file %s: %s", extra.src, err)
+		}
+	}
+	return nil
+}
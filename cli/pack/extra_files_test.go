@@ -0,0 +1,74 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseExtraFiles(t *testing.T) {
+	srcDir := t.TempDir()
+	readmePath := filepath.Join(srcDir, "README")
+	require.NoError(t, os.WriteFile(readmePath, []byte("readme"), 0644))
+
+	extraFiles, err := parseExtraFiles([]string{readmePath + ":README",
+		readmePath + ":doc/./README.md"})
+	require.NoError(t, err)
+	assert.Equal(t, []extraFile{{readmePath, "README"}, {readmePath, "doc/README.md"}},
+		extraFiles)
+
+	tests := []struct {
+		mapping     string
+		expectedErr string
+	}{
+		{"README", `invalid extra file "README": expected src:dst`},
+		{readmePath + ":", "expected src:dst"},
+		{":README", "expected src:dst"},
+		{readmePath + ":../README", "destination must be a path inside the bundle"},
+		{readmePath + ":/README", "destination must be a path inside the bundle"},
+		{srcDir + ":doc", srcDir + " is not a regular file"},
+		{filepath.Join(srcDir, "LICENSE") + ":LICENSE", "LICENSE is not a regular file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mapping, func(t *testing.T) {
+			_, err := parseExtraFiles([]string{tt.mapping})
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func Test_copyExtraFiles(t *testing.T) {
+	srcDir := t.TempDir()
+	readmePath := filepath.Join(srcDir, "README")
+	require.NoError(t, os.WriteFile(readmePath, []byte("readme"), 0600))
+	licensePath := filepath.Join(srcDir, "LICENSE")
+	require.NoError(t, os.WriteFile(licensePath, []byte("license"), 0644))
+
+	bundleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "tt.yaml"), []byte("env:"),
+		0644))
+	packCtx := PackCtx{extraFiles: []extraFile{{readmePath, "README"},
+		{licensePath, "doc/LICENSE"}}}
+	require.NoError(t, copyExtraFiles(&packCtx, bundleDir))
+
+	content, err := os.ReadFile(filepath.Join(bundleDir, "doc", "LICENSE"))
+	require.NoError(t, err)
+	assert.Equal(t, "license", string(content))
+	info, err := os.Stat(filepath.Join(bundleDir, "README"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	packCtx.extraFiles = []extraFile{{readmePath, "tt.yaml"}}
+	err = copyExtraFiles(&packCtx, bundleDir)
+	assert.EqualError(t, err, "extra file "+readmePath+" collides with the bundle path "+
+		"tt.yaml, use --force to overwrite it")
+
+	packCtx.Force = true
+	require.NoError(t, copyExtraFiles(&packCtx, bundleDir))
+	content, err = os.ReadFile(filepath.Join(bundleDir, "tt.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "readme", string(content))
+}
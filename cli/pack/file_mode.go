@@ -0,0 +1,97 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fileModeRule is a parsed --file-mode spec setting the mode and the ownership of
+// the package files matching the glob.
+type fileModeRule struct {
+	// re is a regular expression to match slash-separated path in the package.
+	re *regexp.Regexp
+	// mode is a permission bits to set if setMode is true.
+	mode os.FileMode
+	// setMode is set if the rule changes the file mode.
+	setMode bool
+	// owner is a file owner to set. The owner is not changed if it is empty.
+	owner string
+	// group is a file group to set. The group is not changed if it is empty.
+	group string
+}
+
+// parseFileModeRules parses --file-mode specs in <glob>=<mode>:<owner>:<group> format.
+// Any of mode, owner and group can be empty to keep the default value. The glob is
+// matched against the installed file path without the leading slash.
+func parseFileModeRules(specs []string) ([]fileModeRule, error) {
+	rules := make([]fileModeRule, 0, len(specs))
+	for _, spec := range specs {
+		sepPos := strings.LastIndex(spec, "=")
+		attrs := strings.Split(spec[sepPos+1:], ":")
+		if sepPos <= 0 || len(attrs) != 3 {
+			return nil, fmt.Errorf("invalid file mode spec %q: "+
+				"expected <glob>=<mode>:<owner>:<group>", spec)
+		}
+
+		glob := strings.TrimPrefix(spec[:sepPos], "/")
+		reStr, err := globToRegexp(glob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file mode spec %q: %s", spec, err)
+		}
+		rule := fileModeRule{owner: attrs[1], group: attrs[2]}
+		if rule.re, err = regexp.Compile("^" + reStr + "$"); err != nil {
+			return nil, fmt.Errorf("invalid file mode spec %q: %s", spec, err)
+		}
+		if attrs[0] != "" {
+			mode, err := strconv.ParseUint(attrs[0], 8, 32)
+			if err != nil || mode > 0777 {
+				return nil, fmt.Errorf("invalid file mode spec %q: mode %q must be "+
+					"an octal number from 0 to 0777", spec, attrs[0])
+			}
+			rule.mode = os.FileMode(mode)
+			rule.setMode = true
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// getPackFileInfo returns the owner, the group and the permission bits to set for
+// the package file. The file info set by the packer is used as a base, the matching
+// --file-mode rules are applied on top of it in the order of specifying.
+func (rpmDeb *RpmDebCtx) getPackFileInfo(relPath string, perm os.FileMode) packFileInfo {
+	info, found := rpmDeb.pkgFilesInfo[relPath]
+	if !found {
+		info = packFileInfo{owner: defaultFileUser, group: defaultFileGroup}
+	}
+	info.perm = perm.Perm()
+	for _, rule := range rpmDeb.fileModeRules {
+		if !rule.re.MatchString(relPath) {
+			continue
+		}
+		if rule.setMode {
+			info.perm = rule.mode
+		}
+		if rule.owner != "" {
+			info.owner = rule.owner
+		}
+		if rule.group != "" {
+			info.group = rule.group
+		}
+	}
+	return info
+}
+
+// parseArtifactMode parses the octal permission bits of the result package file.
+func parseArtifactMode(mode string) (*os.FileMode, error) {
+	bits, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || bits > 0777 {
+		return nil, fmt.Errorf("invalid artifact mode %q: must be an octal number "+
+			"from 0 to 0777", mode)
+	}
+	artifactMode := os.FileMode(bits)
+	return &artifactMode, nil
+}
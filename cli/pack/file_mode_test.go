@@ -0,0 +1,143 @@
+package pack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseFileModeRules(t *testing.T) {
+	rules, err := parseFileModeRules([]string{"/usr/share/tarantool/app/data/**=0770::",
+		"usr/share/tarantool/*.lua=:tarantool:tarantool", "a=b=0600:user:"})
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	assert.True(t, rules[0].setMode)
+	assert.Equal(t, os.FileMode(0770), rules[0].mode)
+	assert.Empty(t, rules[0].owner)
+	assert.False(t, rules[1].setMode)
+	assert.Equal(t, "tarantool", rules[1].owner)
+	assert.Equal(t, "tarantool", rules[1].group)
+	assert.True(t, rules[2].re.MatchString("a=b"))
+
+	for spec, errMsg := range map[string]string{
+		"no-attrs":         "expected <glob>=<mode>:<owner>:<group>",
+		"=0644:root:root":  "expected <glob>=<mode>:<owner>:<group>",
+		"file=0644:root":   "expected <glob>=<mode>:<owner>:<group>",
+		"file=0999::":      `mode "0999" must be an octal number from 0 to 0777`,
+		"file=01777::":     `mode "01777" must be an octal number from 0 to 0777`,
+		"file[0-9=0644::":  "unterminated character class",
+		"file=rwx:user:gr": `mode "rwx" must be an octal number`,
+	} {
+		_, err := parseFileModeRules([]string{spec})
+		assert.ErrorContains(t, err, errMsg, spec)
+		assert.ErrorContains(t, err, "invalid file mode spec", spec)
+	}
+}
+
+func Test_getPackFileInfo(t *testing.T) {
+	rules, err := parseFileModeRules([]string{"usr/share/app/**=0640:app:",
+		"usr/share/app/data=0770::app", "var/lib/tarantool=:root:"})
+	require.NoError(t, err)
+	rpmDeb := RpmDebCtx{fileModeRules: rules, pkgFilesInfo: map[string]packFileInfo{
+		"var/lib/tarantool": {owner: "tarantool", group: "tarantool"}}}
+
+	assert.Equal(t, packFileInfo{defaultFileUser, defaultFileGroup, 0644},
+		rpmDeb.getPackFileInfo("usr/share/init.lua", 0644))
+	assert.Equal(t, packFileInfo{"app", defaultFileGroup, 0640},
+		rpmDeb.getPackFileInfo("usr/share/app/init.lua", 0644))
+	// The last matching rule wins.
+	assert.Equal(t, packFileInfo{"app", "app", 0770},
+		rpmDeb.getPackFileInfo("usr/share/app/data", os.ModeDir|0755))
+	assert.Equal(t, packFileInfo{"root", "tarantool", 0750},
+		rpmDeb.getPackFileInfo("var/lib/tarantool", os.ModeDir|0750))
+}
+
+func TestWriteTgzArchiveFileModes(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "app", "data"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "app", "init.lua"), nil, 0644))
+
+	rules, err := parseFileModeRules([]string{"app/data=0770:tarantool:tarantool"})
+	require.NoError(t, err)
+	packCtx := PackCtx{RpmDeb: RpmDebCtx{fileModeRules: rules}}
+	tgzPath := filepath.Join(t.TempDir(), "data.tar.gz")
+	require.NoError(t, writeTgzArchive(srcDir, tgzPath, packCtx, DefaultCompressionLevel))
+
+	file, err := os.Open(tgzPath)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	headers := map[string]*tar.Header{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		headers[header.Name] = header
+	}
+	require.Contains(t, headers, "app/data")
+	assert.EqualValues(t, 0770, headers["app/data"].Mode&0777)
+	assert.Equal(t, "tarantool", headers["app/data"].Uname)
+	assert.Equal(t, "tarantool", headers["app/data"].Gname)
+	require.Contains(t, headers, "app/init.lua")
+	assert.EqualValues(t, 0644, headers["app/init.lua"].Mode&0777)
+	assert.Equal(t, defaultFileUser, headers["app/init.lua"].Uname)
+}
+
+func TestWriteTgzArchiveControlFileModes(t *testing.T) {
+	controlDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(controlDir, "control"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(controlDir, PostInstScriptName), nil, 0755))
+
+	rules, err := parseFileModeRules([]string{"*=0644::"})
+	require.NoError(t, err)
+	packCtx := PackCtx{RpmDeb: RpmDebCtx{fileModeRules: rules}}
+	tgzPath := filepath.Join(t.TempDir(), "control.tar.gz")
+	require.NoError(t, writeTgzArchive(controlDir, tgzPath, getControlArchiveCtx(packCtx),
+		DefaultCompressionLevel))
+	// The data archive rules are kept.
+	require.Len(t, packCtx.RpmDeb.fileModeRules, 1)
+
+	file, err := os.Open(tgzPath)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	modes := map[string]int64{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		modes[header.Name] = header.Mode & 0777
+	}
+	assert.EqualValues(t, 0755, modes[PostInstScriptName])
+	assert.EqualValues(t, 0644, modes["control"])
+}
+
+func Test_parseArtifactMode(t *testing.T) {
+	mode, err := parseArtifactMode("0664")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0664), *mode)
+
+	mode, err = parseArtifactMode("640")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), *mode)
+
+	for _, invalid := range []string{"0888", "1777", "rw-r--r--", "-1"} {
+		_, err = parseArtifactMode(invalid)
+		assert.EqualError(t, err, `invalid artifact mode "`+invalid+
+			`": must be an octal number from 0 to 0777`)
+	}
+}
@@ -0,0 +1,88 @@
+package pack
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// fileNamePlaceholders contains the placeholders supported in the package file name
+// template.
+var fileNamePlaceholders = []string{"name", "version", "type", "arch", "date"}
+
+// fileNamePlaceholderRe matches the placeholder in the package file name template.
+var fileNamePlaceholderRe = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// checkFileNameTemplate checks the package file name template contains only the known
+// placeholders.
+func checkFileNameTemplate(template string) error {
+	for _, match := range fileNamePlaceholderRe.FindAllStringSubmatch(template, -1) {
+		if util.Find(fileNamePlaceholders, match[1]) == -1 {
+			return fmt.Errorf("unknown placeholder %s in filename %q, expected one of: {%s}",
+				match[0], template, strings.Join(fileNamePlaceholders, "}, {"))
+		}
+	}
+	return nil
+}
+
+// getPackageArch returns the architecture the package file is named with.
+func getPackageArch(packCtx *PackCtx) (string, error) {
+	switch {
+	case packCtx.Type == Deb && packCtx.RpmDeb.DebArch != "":
+		return packCtx.RpmDeb.DebArch, nil
+	case packCtx.Type == Rpm && packCtx.RpmDeb.RpmArch != "":
+		return packCtx.RpmDeb.RpmArch, nil
+	}
+	return util.GetArch()
+}
+
+// expandFileNameTemplate substitutes the placeholders of the package file name template.
+// The placeholder values are evaluated only if they are used.
+func expandFileNameTemplate(template string, packCtx *PackCtx,
+	opts *config.CliOpts) (string, error) {
+	values := map[string]func() (string, error){
+		"name": func() (string, error) {
+			if packCtx.Name != "" {
+				return packCtx.Name, nil
+			}
+			absPath, err := filepath.Abs(".")
+			if err != nil {
+				return "", err
+			}
+			return filepath.Base(absPath), nil
+		},
+		"version": func() (string, error) {
+			return getVersion(packCtx, opts, defaultLongVersion), nil
+		},
+		"type": func() (string, error) {
+			return packCtx.Type, nil
+		},
+		"arch": func() (string, error) {
+			return getPackageArch(packCtx)
+		},
+		"date": func() (string, error) {
+			return getBuildTime(packCtx).Format("20060102"), nil
+		},
+	}
+
+	var err error
+	fileName := fileNamePlaceholderRe.ReplaceAllStringFunc(template, func(match string) string {
+		getValue, found := values[match[1:len(match)-1]]
+		if !found || err != nil {
+			return match
+		}
+		value, valueErr := getValue()
+		if valueErr != nil {
+			err = valueErr
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
@@ -0,0 +1,67 @@
+package pack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/util"
+)
+
+func Test_checkFileNameTemplate(t *testing.T) {
+	assert.NoError(t, checkFileNameTemplate(""))
+	assert.NoError(t, checkFileNameTemplate("bundle.tar.gz"))
+	assert.NoError(t, checkFileNameTemplate("{name}-{version}-{type}-{arch}-{date}"))
+	assert.EqualError(t, checkFileNameTemplate("{name}-{release}.tgz"),
+		`unknown placeholder {release} in filename "{name}-{release}.tgz", expected one of: `+
+			`{name}, {version}, {type}, {arch}, {date}`)
+	assert.ErrorContains(t, checkFileNameTemplate("{}.tgz"), "unknown placeholder {}")
+}
+
+func Test_getPackageFileNameTemplate(t *testing.T) {
+	arch, err := util.GetArch()
+	require.NoError(t, err)
+	sourceDateEpoch := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: t.TempDir()}}
+
+	tests := []struct {
+		name     string
+		packCtx  PackCtx
+		suffix   string
+		expected string
+	}{
+		{
+			name: "all placeholders",
+			packCtx: PackCtx{Type: Tgz, Name: "myapp", Version: "1.2.3",
+				FileName: "{name}-{version}-el8-{arch}.{type}-{date}"},
+			expected: "myapp-1.2.3-el8-" + arch + ".tgz-20240305",
+		},
+		{
+			name: "package architecture",
+			packCtx: PackCtx{Type: Deb, Name: "myapp", FileName: "{name}_{arch}.deb",
+				RpmDeb: RpmDebCtx{DebArch: "armhf", RpmArch: "noarch"}},
+			expected: "myapp_armhf.deb",
+		},
+		{
+			name:     "no placeholders",
+			packCtx:  PackCtx{Type: Rpm, Name: "myapp", FileName: "bundle.rpm"},
+			expected: "bundle.rpm",
+		},
+		{
+			name:     "default",
+			packCtx:  PackCtx{Type: Deb, Name: "myapp", Version: "2.0.0"},
+			suffix:   "-1_armhf.deb",
+			expected: "myapp_2.0.0-1_armhf.deb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.packCtx.sourceDateEpoch = &sourceDateEpoch
+			fileName, err := getPackageFileName(&tt.packCtx, opts, tt.suffix, true)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fileName)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package pack
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// osFileSystem is a FileSystem of the OS directory.
+type osFileSystem struct {
+	root string
+}
+
+// NewOSFileSystem returns the FileSystem of the OS directory.
+func NewOSFileSystem(root string) FileSystem {
+	return osFileSystem{root: root}
+}
+
+// osPath returns the OS path of the file system name.
+func (fsys osFileSystem) osPath(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(fsys.root, filepath.FromSlash(name)), nil
+}
+
+// Open opens the named file.
+func (fsys osFileSystem) Open(name string) (fs.File, error) {
+	osPath, err := fsys.osPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(osPath)
+}
+
+// ReadDir reads the named directory.
+func (fsys osFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	osPath, err := fsys.osPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadDir(osPath)
+}
+
+// Lstat returns the file info. The symlink is not followed.
+func (fsys osFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	osPath, err := fsys.osPath("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Lstat(osPath)
+}
+
+// ReadLink returns the symlink target.
+func (fsys osFileSystem) ReadLink(name string) (string, error) {
+	osPath, err := fsys.osPath("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	return os.Readlink(osPath)
+}
+
+// evalSymlinks returns the OS path of the file with all symlinks resolved.
+func (fsys osFileSystem) evalSymlinks(name string) (string, error) {
+	osPath, err := fsys.osPath("evalsymlinks", name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(osPath)
+}
+
+// readOnlyFileSystem is a FileSystem of fs.FS without symlinks, for example embed.FS.
+type readOnlyFileSystem struct {
+	fs.FS
+}
+
+// NewFileSystem returns the FileSystem of fs.FS. The files of fs.FS are not symlinks.
+func NewFileSystem(fsys fs.FS) FileSystem {
+	return readOnlyFileSystem{fsys}
+}
+
+// ReadDir reads the named directory.
+func (fsys readOnlyFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(fsys.FS, name)
+}
+
+// Lstat returns the file info.
+func (fsys readOnlyFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return fs.Stat(fsys.FS, name)
+}
+
+// ReadLink returns an error, the file system does not have symlinks.
+func (fsys readOnlyFileSystem) ReadLink(name string) (string, error) {
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+}
+
+// getLinkTargetBase returns the base name of the symlink target with all symlinks
+// resolved. The symlinks are resolved by the OS for the OS file system, the last target
+// of the symlinks chain is used otherwise.
+func getLinkTargetBase(fsys FileSystem, name string) (string, error) {
+	if osFsys, ok := fsys.(osFileSystem); ok {
+		resolved, err := osFsys.evalSymlinks(name)
+		return filepath.Base(resolved), err
+	}
+	const maxLinks = 255
+	for i := 0; i < maxLinks; i++ {
+		target, err := fsys.ReadLink(name)
+		if err != nil {
+			return "", err
+		}
+		if path.IsAbs(target) {
+			return path.Base(target), nil
+		}
+		name = path.Join(path.Dir(name), target)
+		info, err := fsys.Lstat(name)
+		if err != nil || info.Mode().Type() != fs.ModeSymlink {
+			return path.Base(name), nil
+		}
+	}
+	return "", fmt.Errorf("too many levels of symbolic links: %s", name)
+}
@@ -0,0 +1,87 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSFileSystem(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "app", "init.lua"), []byte("box.cfg{}"),
+		0644))
+	require.NoError(t, os.Symlink("app", filepath.Join(root, "link")))
+
+	fsys := NewOSFileSystem(root)
+	content, err := fs.ReadFile(fsys, "app/init.lua")
+	require.NoError(t, err)
+	assert.Equal(t, "box.cfg{}", string(content))
+
+	info, err := fsys.Lstat("link")
+	require.NoError(t, err)
+	assert.Equal(t, fs.ModeSymlink, info.Mode().Type())
+	target, err := fsys.ReadLink("link")
+	require.NoError(t, err)
+	assert.Equal(t, "app", target)
+	base, err := getLinkTargetBase(fsys, "link")
+	require.NoError(t, err)
+	assert.Equal(t, "app", base)
+
+	_, err = fsys.Open("../outside")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+func Test_collectFilesFS(t *testing.T) {
+	fsys := NewFileSystem(fstest.MapFS{
+		"app/init.lua":     {Data: []byte("box.cfg{}")},
+		"app/lib/util.lua": {Data: []byte("return {}")},
+		"app.lua":          {Data: []byte("print(1)")},
+	})
+	files, err := collectFilesFS(context.Background(), fsys, 2)
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.name)
+		assert.Equal(t, filepath.FromSlash(file.name), file.relPath)
+		assert.Empty(t, file.path)
+	}
+	assert.Equal(t, []string{".", "app", "app/init.lua", "app/lib", "app/lib/util.lua",
+		"app.lua"}, names)
+
+	_, err = fsys.ReadLink("app.lua")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+func TestWriteTarArchiveFS(t *testing.T) {
+	fsys := NewFileSystem(fstest.MapFS{
+		"app/init.lua": {Data: []byte("box.cfg{}"), Mode: 0644},
+		"bin/tt":       {Data: []byte("tt"), Mode: 0755},
+	})
+	var buf bytes.Buffer
+	require.NoError(t, WriteTarArchiveFS(fsys, &buf, &PackCtx{}))
+
+	entries := map[string]string{}
+	tarReader := tar.NewReader(&buf)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		entries[header.Name] = string(content)
+	}
+	assert.Equal(t, map[string]string{".": "", "app": "", "app/init.lua": "box.cfg{}",
+		"bin": "", "bin/tt": "tt"}, entries)
+}
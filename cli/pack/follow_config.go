@@ -0,0 +1,101 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/running"
+)
+
+// instanceScriptSuffix is a suffix of the instance script of the multi-instance
+// application.
+const instanceScriptSuffix = ".init.lua"
+
+// initConfiguredFiles collects the files of the applications defined by their instances:
+// the cluster configs, the instance scripts and the scripts set by the app.file option
+// of the cluster config. The sets are keyed by the resolved application directory.
+// The single file and the single instance applications are packed as is.
+func initConfiguredFiles(packCtx *PackCtx) error {
+	packCtx.configuredFiles = map[string]map[string]bool{}
+	for appName, instances := range packCtx.AppsInfo {
+		if len(instances) == 0 || instances[0].IsFileApp || instances[0].SingleApp {
+			continue
+		}
+		appDir, err := filepath.EvalSymlinks(instances[0].AppDir)
+		if err != nil {
+			return fmt.Errorf("cannot apply --follow-config to application %q: %s",
+				appName, err)
+		}
+		files, err := getConfiguredFiles(instances)
+		if err != nil {
+			return fmt.Errorf("cannot apply --follow-config to application %q: %s",
+				appName, err)
+		}
+		packCtx.configuredFiles[appDir] = files
+	}
+	return nil
+}
+
+// getConfiguredFiles returns the slash-separated paths relative to the application
+// directory of the files defined by the application instances.
+func getConfiguredFiles(instances []running.InstanceCtx) (map[string]bool, error) {
+	appDir := instances[0].AppDir
+	files := map[string]bool{}
+	addFile := func(path string) error {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(appDir, path)
+		}
+		relPath, err := filepath.Rel(appDir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relPath)] = true
+		return nil
+	}
+	for _, inst := range instances {
+		paths := []string{inst.InstanceScript, inst.ClusterConfigPath}
+		if cfg := inst.Configuration.RawConfig; cfg != nil {
+			if file, err := cfg.Get([]string{"app", "file"}); err == nil {
+				script, ok := file.(string)
+				if !ok {
+					return nil, fmt.Errorf("app.file of instance %q is not a string",
+						inst.InstName)
+				}
+				paths = append(paths, script)
+			}
+		}
+		for _, path := range paths {
+			if path == "" {
+				continue
+			}
+			if err := addFile(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// configuredFilesFilter returns a filter func to skip the instance scripts of
+// the multi-instance application which are not defined by its instances. Such scripts are
+// never run. The rest application files are shared by the instances and are not skipped.
+func configuredFilesFilter(packCtx *PackCtx,
+	srcAppPath string) func(srcInfo os.FileInfo, src string) bool {
+	files, found := packCtx.configuredFiles[srcAppPath]
+	if !found {
+		return func(srcInfo os.FileInfo, src string) bool { return false }
+	}
+	return func(srcInfo os.FileInfo, src string) bool {
+		if !srcInfo.Mode().IsRegular() || filepath.Dir(src) != srcAppPath ||
+			!strings.HasSuffix(srcInfo.Name(), instanceScriptSuffix) ||
+			files[srcInfo.Name()] {
+			return false
+		}
+		log.Debugf("Skipping %s: the script is not defined by the application instances",
+			srcInfo.Name())
+		return true
+	}
+}
@@ -0,0 +1,94 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/lib/integrity"
+)
+
+// packConfiguredApp collects the application instances, applies --follow-config and
+// returns the names of the copied application root entries.
+func packConfiguredApp(t *testing.T, appsDir string) []string {
+	instances, err := running.CollectInstances("app", appsDir, integrity.IntegrityCtx{
+		Repository: &mockRepository{}})
+	require.NoError(t, err)
+	packCtx := PackCtx{FollowConfig: true,
+		AppsInfo: map[string][]running.InstanceCtx{"app": instances}}
+	require.NoError(t, initConfiguredFiles(&packCtx))
+
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{},
+		filepath.Join(appsDir, "app"), dstDir))
+	entries, err := os.ReadDir(dstDir)
+	require.NoError(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func Test_initConfiguredFiles(t *testing.T) {
+	appsDir := t.TempDir()
+	appDir := filepath.Join(appsDir, "app")
+	require.NoError(t, os.Mkdir(appDir, 0755))
+	files := map[string]string{
+		"instances.yml":    "router:\napp.storage:\n",
+		"router.init.lua":  "router",
+		"storage.init.lua": "storage",
+		"old.init.lua":     "old",
+		"init.lua":         "default",
+		"lib.lua":          "lib",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, name), []byte(content), 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(appDir, "unused.init.lua"), 0755))
+
+	assert.Equal(t, []string{"init.lua", "instances.yml", "lib.lua", "router.init.lua",
+		"storage.init.lua", "unused.init.lua"}, packConfiguredApp(t, appsDir))
+}
+
+func Test_initConfiguredFilesClusterConfig(t *testing.T) {
+	appsDir := t.TempDir()
+	appDir := filepath.Join(appsDir, "app")
+	require.NoError(t, os.Mkdir(appDir, 0755))
+	files := map[string]string{
+		"instances.yml": "router:\nstorage:\n",
+		"config.yaml": `groups:
+  group:
+    replicasets:
+      replicaset:
+        instances:
+          router:
+            app:
+              file: router.init.lua
+          storage: {}
+`,
+		"router.init.lua":  "router",
+		"storage.init.lua": "storage",
+		"init.lua":         "default",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, name), []byte(content), 0644))
+	}
+
+	// The cluster config instances do not run the instance scripts by name, only
+	// the app.file scripts are packed.
+	assert.Equal(t, []string{"config.yaml", "init.lua", "instances.yml", "router.init.lua"},
+		packConfiguredApp(t, appsDir))
+}
+
+func Test_initConfiguredFilesSingleApp(t *testing.T) {
+	packCtx := PackCtx{AppsInfo: map[string][]running.InstanceCtx{
+		"app": {{InstName: "app", AppDir: t.TempDir(), SingleApp: true}}}}
+	require.NoError(t, initConfiguredFiles(&packCtx))
+	assert.Empty(t, packCtx.configuredFiles)
+}
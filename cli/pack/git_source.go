@@ -0,0 +1,132 @@
+package pack
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/configure"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// gitNoPromptEnv disables git credentials prompting, so the clone fails instead of
+// waiting for the input if the credential helpers do not provide the credentials.
+const gitNoPromptEnv = "GIT_TERMINAL_PROMPT=0"
+
+// gitRepoNameRe matches the repository name at the end of git URL.
+var gitRepoNameRe = regexp.MustCompile(`([^/:\\]+?)(\.git)?/*$`)
+
+// redactGitURL returns the git URL with the password replaced to print it safely.
+func redactGitURL(gitURL string) string {
+	parsed, err := url.Parse(gitURL)
+	if err != nil || parsed.User == nil {
+		return gitURL
+	}
+	return parsed.Redacted()
+}
+
+// getGitRepoName returns the repository name of the git URL: the last path element
+// without .git suffix.
+func getGitRepoName(gitURL string) (string, error) {
+	match := gitRepoNameRe.FindStringSubmatch(gitURL)
+	if match == nil || match[1] == "." || match[1] == ".." {
+		return "", fmt.Errorf("cannot get repository name from git URL %q",
+			redactGitURL(gitURL))
+	}
+	return match[1], nil
+}
+
+// CloneGitSource shallowly clones the git repository ref to pack it. The default branch
+// is cloned if the ref is not set. The clone directory is named after the repository,
+// so the repository is packed as the application of this name if it is not a tt
+// environment. Returned function removes the clone, it is removed on failure too.
+func CloneGitSource(packCtx *PackCtx) (string, func(), error) {
+	if err := util.CheckRequiredBinaries("git"); err != nil {
+		return "", nil, fmt.Errorf("cannot clone %s: %s", redactGitURL(packCtx.GitURL), err)
+	}
+	repoName, err := getGitRepoName(packCtx.GitURL)
+	if err != nil {
+		return "", nil, err
+	}
+	tmpDir, err := os.MkdirTemp(getTmpDir(packCtx), "tt_pack_git")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Warnf("Failed to remove a temporary directory %s: %s", tmpDir, err)
+		}
+	}
+
+	cloneDir := filepath.Join(tmpDir, repoName)
+	log.Infof("Cloning %s to %s", redactGitURL(packCtx.GitURL), cloneDir)
+	if err = fetchGitRef(cloneDir, packCtx.GitURL, packCtx.GitRef); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("cannot clone %s: %s", redactGitURL(packCtx.GitURL), err)
+	}
+	return cloneDir, cleanup, nil
+}
+
+// fetchGitRef fetches the last commit of the ref into the new repository in the directory
+// and checks it out. The ref is fetched as a tag first, so the version can be got from
+// git, then as a branch or a commit hash.
+func fetchGitRef(dir, gitURL, ref string) error {
+	if err := os.MkdirAll(dir, dirPermissions); err != nil {
+		return err
+	}
+	env := []string{gitNoPromptEnv}
+	git := func(args ...string) error {
+		if _, err := gitCommandEnv(env, dir, args...); err != nil {
+			return fmt.Errorf("git %s failed: %s", args[0], err)
+		}
+		return nil
+	}
+	if err := git("init", "--quiet"); err != nil {
+		return err
+	}
+	if err := git("remote", "add", "origin", gitURL); err != nil {
+		return err
+	}
+
+	checkoutRef := "FETCH_HEAD"
+	tagRef := "refs/tags/" + ref
+	if ref != "" && git("fetch", "--quiet", "--depth", "1", "origin",
+		tagRef+":"+tagRef) == nil {
+		checkoutRef = tagRef
+	} else {
+		if ref == "" {
+			ref = "HEAD"
+		}
+		if err := git("fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+			return err
+		}
+	}
+	if err := git("checkout", "--quiet", checkoutRef); err != nil {
+		return err
+	}
+	return git("submodule", "--quiet", "update", "--init", "--recursive", "--depth", "1")
+}
+
+// GitSourceOpts makes the command context use the cloned repository as the environment
+// and returns the options to pack with. The repository tt configuration is loaded if
+// it has one, otherwise the repository is packed as a single application.
+func GitSourceOpts(cmdCtx *cmdcontext.CmdCtx, cloneDir string) (*config.CliOpts, error) {
+	configPath := filepath.Join(cloneDir, configure.ConfigName)
+	if !util.IsRegularFile(configPath) {
+		cmdCtx.Cli.ConfigPath = ""
+		cmdCtx.Cli.ConfigDir = cloneDir
+		return configure.GetDefaultCliOpts(), nil
+	}
+	opts, configPath, err := configure.GetCliOpts(configPath, cmdCtx.Integrity.Repository)
+	if err != nil {
+		return nil, err
+	}
+	cmdCtx.Cli.ConfigPath = configPath
+	cmdCtx.Cli.ConfigDir = filepath.Dir(configPath)
+	return opts, nil
+}
@@ -0,0 +1,89 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// dirtyVersionSuffix is appended to the version got from git if the working tree
+// has uncommitted changes.
+const dirtyVersionSuffix = "+dirty"
+
+// getGitVersionDir returns a directory to get the package version from git in. It is
+// the application directory if a single application is packed, the environment directory
+// otherwise.
+func getGitVersionDir(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx) string {
+	if len(packCtx.AppsInfo) == 1 {
+		for _, instances := range packCtx.AppsInfo {
+			if len(instances) > 0 {
+				if instances[0].IsFileApp {
+					return filepath.Dir(instances[0].InstanceScript)
+				}
+				return instances[0].AppDir
+			}
+		}
+	}
+	if packCtx.SourceDir != "" {
+		return packCtx.SourceDir
+	}
+	return cmdCtx.Cli.ConfigDir
+}
+
+// gitCommand runs git with the arguments in the directory and returns its trimmed output.
+func gitCommand(dir string, args ...string) (string, error) {
+	return gitCommandEnv(nil, dir, args...)
+}
+
+// gitCommandEnv runs git with the additional environment variables in the directory and
+// returns its trimmed output.
+func gitCommandEnv(env []string, dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// getVersionFromGit returns the package version got by `git describe --tags` in the
+// directory. Leading "v" is stripped. The dirty suffix is added if the working tree
+// has uncommitted changes and allowDirty is not set.
+func getVersionFromGit(dir string, allowDirty bool) (string, error) {
+	if err := util.CheckRequiredBinaries("git"); err != nil {
+		return "", fmt.Errorf("cannot get package version from git: %s. Install git or"+
+			" set the version with --version option", err)
+	}
+
+	version, err := gitCommand(dir, "describe", "--tags")
+	if err != nil {
+		return "", fmt.Errorf("cannot get package version from git in %q: %s. Create a tag"+
+			" or set the version with --version option", dir, err)
+	}
+	version = strings.TrimPrefix(version, "v")
+	if version == "" {
+		return "", fmt.Errorf("cannot get package version from git in %q: empty tag", dir)
+	}
+
+	if !allowDirty {
+		status, err := gitCommand(dir, "status", "--porcelain")
+		if err != nil {
+			return "", fmt.Errorf("cannot get git working tree status in %q: %s", dir, err)
+		}
+		if status != "" {
+			version += dirtyVersionSuffix
+		}
+	}
+	return version, nil
+}
@@ -0,0 +1,113 @@
+package pack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/running"
+)
+
+// initTestGitRepo creates a git repository with a single commit.
+func initTestGitRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repoDir, "-c", "user.name=tt",
+			"-c", "user.email=tt@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	git("init")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "init.lua"), nil, 0644))
+	git("add", "init.lua")
+	git("commit", "-m", "init")
+	git("tag", "v1.2.3")
+	return repoDir
+}
+
+func Test_getVersionFromGit(t *testing.T) {
+	repoDir := initTestGitRepo(t)
+
+	version, err := getVersionFromGit(repoDir, false)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", version)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "init.lua"), []byte("-- edit"),
+		0644))
+	version, err = getVersionFromGit(repoDir, false)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3+dirty", version)
+
+	version, err = getVersionFromGit(repoDir, true)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", version)
+}
+
+func Test_getVersionFromGitNoTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repoDir := t.TempDir()
+	require.NoError(t, exec.Command("git", "-C", repoDir, "init").Run())
+
+	_, err := getVersionFromGit(repoDir, false)
+	assert.ErrorContains(t, err, "Create a tag or set the version with --version option")
+}
+
+func Test_getGitVersionDir(t *testing.T) {
+	packCtx := PackCtx{AppsInfo: map[string][]running.InstanceCtx{
+		"app": {{AppDir: "/env/app"}},
+	}}
+	assert.Equal(t, "/env/app", getGitVersionDir(&cmdcontext.CmdCtx{}, &packCtx))
+
+	packCtx.AppsInfo["script"] = []running.InstanceCtx{{IsFileApp: true,
+		InstanceScript: "/env/script.lua"}}
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = "/env"
+	assert.Equal(t, "/env", getGitVersionDir(&cmdCtx, &packCtx))
+}
+
+func Test_initGitVersionRpmDirty(t *testing.T) {
+	repoDir := initTestGitRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "init.lua"), []byte("-- edit"),
+		0644))
+
+	packCtx := PackCtx{Name: "bundle", Type: Rpm, VersionFromGit: true,
+		AppsInfo: map[string][]running.InstanceCtx{"app": {{AppDir: repoDir}}}}
+	require.NoError(t, initGitVersion(&cmdcontext.CmdCtx{}, &packCtx))
+	assert.Equal(t, "1.2.3+dirty", packCtx.Version)
+
+	// The dirty suffix is moved into the RPM release.
+	cpioPath := filepath.Join(t.TempDir(), "payload.cpio")
+	require.NoError(t, os.WriteFile(cpioPath, []byte("cpio"), 0644))
+	opts := config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	rpmHeader, err := genRpmHeader(nil, cpioPath, cpioPath, filepath.Dir(cpioPath),
+		&cmdcontext.CmdCtx{}, &packCtx, &opts)
+	require.NoError(t, err)
+	tags := map[int]rpmTagType{}
+	for _, tag := range rpmHeader {
+		tags[tag.ID] = tag
+	}
+	assert.Equal(t, "1.2.3", tags[tagVersion].Value)
+	assert.Equal(t, "1.dirty", tags[tagRelease].Value)
+	suffix, err := getRPMSuffix(&packCtx)
+	require.NoError(t, err)
+	assert.Contains(t, suffix, "-1.dirty.")
+
+	// The version not valid for RPM is rejected before packing.
+	tag := exec.Command("git", "-C", repoDir, "tag", "-a", "-m", "release", "release")
+	tag.Env = append(os.Environ(), "GIT_COMMITTER_NAME=tt",
+		"GIT_COMMITTER_EMAIL=tt@example.com")
+	output, err := tag.CombinedOutput()
+	require.NoError(t, err, string(output))
+	err = initGitVersion(&cmdcontext.CmdCtx{}, &packCtx)
+	assert.ErrorContains(t, err, `invalid RPM version got from git: failed to parse version`)
+}
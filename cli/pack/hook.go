@@ -0,0 +1,46 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/config"
+)
+
+// checkPostPackHook checks the post-pack hook is an executable file.
+func checkPostPackHook(packCtx *PackCtx) error {
+	hookPath, err := exec.LookPath(packCtx.PostPackHook)
+	if err != nil {
+		return fmt.Errorf("invalid post-pack hook %q: %s", packCtx.PostPackHook, err)
+	}
+	packCtx.PostPackHook = hookPath
+	return nil
+}
+
+// RunPostPackHook runs the post-pack hook for the created package. The package path is
+// passed as the first argument, package info is passed in environment variables.
+func RunPostPackHook(packCtx *PackCtx, opts *config.CliOpts) error {
+	if packCtx.PostPackHook == "" {
+		return nil
+	}
+	if packCtx.artifactPath == "" {
+		return fmt.Errorf("cannot run post-pack hook: result package is unknown")
+	}
+
+	log.Infof("Running post-pack hook %s.", packCtx.PostPackHook)
+	hookCmd := exec.Command(packCtx.PostPackHook, packCtx.artifactPath)
+	hookCmd.Env = append(os.Environ(),
+		"TT_PACK_NAME="+packCtx.Name,
+		"TT_PACK_VERSION="+getVersion(packCtx, opts, defaultVersion),
+		"TT_PACK_TYPE="+packCtx.Type,
+		"TT_PACK_PATH="+packCtx.artifactPath,
+	)
+	hookCmd.Stdout = getCmdStdout(packCtx)
+	hookCmd.Stderr = os.Stderr
+	if err := hookCmd.Run(); err != nil {
+		return fmt.Errorf("post-pack hook %q failed: %s", packCtx.PostPackHook, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_RunPostPackHook(t *testing.T) {
+	baseDir := t.TempDir()
+	outputPath := filepath.Join(baseDir, "hook_output")
+	hookPath := filepath.Join(baseDir, "hook.sh")
+	require.NoError(t, os.WriteFile(hookPath, []byte(`#!/bin/sh
+echo "$1 $TT_PACK_NAME $TT_PACK_VERSION $TT_PACK_TYPE" > `+outputPath+`
+`), 0755))
+
+	packCtx := PackCtx{Name: "bundle", Version: "1.2.3", Type: Tgz, PostPackHook: hookPath,
+		artifactPath: "/tmp/bundle-1.2.3.x86_64.tar.gz"}
+	require.NoError(t, checkPostPackHook(&packCtx))
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	require.NoError(t, RunPostPackHook(&packCtx, opts))
+
+	output, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/bundle-1.2.3.x86_64.tar.gz bundle 1.2.3 tgz\n", string(output))
+}
+
+func Test_RunPostPackHookFailed(t *testing.T) {
+	hookPath := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(hookPath, []byte("#!/bin/sh\nexit 3\n"), 0755))
+
+	packCtx := PackCtx{Name: "bundle", Type: Tgz, PostPackHook: hookPath,
+		artifactPath: "bundle.tar.gz"}
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	assert.ErrorContains(t, RunPostPackHook(&packCtx, opts), "exit status 3")
+}
+
+func Test_checkPostPackHook(t *testing.T) {
+	hookPath := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(hookPath, []byte("#!/bin/sh\n"), 0644))
+
+	packCtx := PackCtx{PostPackHook: hookPath}
+	assert.ErrorContains(t, checkPostPackHook(&packCtx), "invalid post-pack hook")
+
+	packCtx.PostPackHook = filepath.Join(filepath.Dir(hookPath), "missing.sh")
+	assert.ErrorContains(t, checkPostPackHook(&packCtx), "invalid post-pack hook")
+}
@@ -0,0 +1,144 @@
+package pack
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// parseIncludePatterns compiles the include globs matched against the slash-separated
+// bundle-relative paths.
+func parseIncludePatterns(globs []string) ([]*regexp.Regexp, error) {
+	return parsePathGlobs("include", globs)
+}
+
+// parsePathGlobs compiles the kind patterns globs matched against the slash-separated
+// relative paths.
+func parsePathGlobs(kind string, globs []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		glob = strings.TrimSuffix(glob, "/")
+		if glob == "" || strings.HasPrefix(glob, "/") {
+			return nil, fmt.Errorf("invalid %s pattern %q: must be a bundle-relative "+
+				"path glob", kind, glob)
+		}
+		reStr, err := globToRegexp(glob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %s", kind, glob, err)
+		}
+		re, err := regexp.Compile("^" + reStr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %s", kind, glob, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// isIncluded returns true if the relative path matches any of the include patterns.
+func isIncluded(patterns []*regexp.Regexp, relPath string) bool {
+	for _, re := range patterns {
+		if re.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// findEmptyApps returns the names of the applications left without files in the bundle.
+func findEmptyApps(bundleEnvPath string, packCtx *PackCtx, newOpts *config.CliOpts) (
+	[]string, error) {
+	emptyApps := []string{}
+	for _, appName := range packCtx.AppList {
+		appPath := getDestAppDir(bundleEnvPath, appName, packCtx, newOpts)
+		if util.IsRegularFile(appPath) {
+			continue
+		}
+		hasFiles := false
+		err := filepath.WalkDir(appPath, func(path string, entry fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && path == appPath {
+				return filepath.SkipDir
+			} else if err != nil {
+				return err
+			}
+			if !entry.IsDir() {
+				hasFiles = true
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !hasFiles {
+			emptyApps = append(emptyApps, appName)
+		}
+	}
+	sort.Strings(emptyApps)
+	return emptyApps, nil
+}
+
+// checkIncludedApps returns an error if the include patterns leave any application
+// without files, so the package would not ship it.
+func checkIncludedApps(bundleEnvPath string, packCtx *PackCtx, newOpts *config.CliOpts) error {
+	if len(packCtx.includePatterns) == 0 || packCtx.AllowEmpty {
+		return nil
+	}
+	emptyApps, err := findEmptyApps(bundleEnvPath, packCtx, newOpts)
+	if err != nil {
+		return fmt.Errorf("failed to check included files: %s", err)
+	}
+	if len(emptyApps) > 0 {
+		return fmt.Errorf("include patterns matched no files of applications: %s, "+
+			"use --allow-empty to pack them empty", strings.Join(emptyApps, ", "))
+	}
+	return nil
+}
+
+// applyIncludePatterns removes the bundle entries not matching the include patterns.
+// The matched directories are kept with all their content. The not matched directories
+// are kept only if they contain the matched entries. The bundle is not changed if there
+// are no include patterns.
+func applyIncludePatterns(packCtx *PackCtx, bundlePath string) error {
+	if len(packCtx.includePatterns) == 0 {
+		return nil
+	}
+	included := func(path string) bool {
+		relPath, err := filepath.Rel(bundlePath, path)
+		return err == nil && isIncluded(packCtx.includePatterns, filepath.ToSlash(relPath))
+	}
+	err := filepath.WalkDir(bundlePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == bundlePath {
+			return nil
+		}
+		if included(path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		return os.Remove(path)
+	})
+	if err == nil {
+		// The included directories are kept even if they are empty.
+		err = pruneEmptyDirs(bundlePath, included)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply include patterns: %s", err)
+	}
+	return nil
+}
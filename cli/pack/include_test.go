@@ -0,0 +1,81 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_parseIncludePatterns(t *testing.T) {
+	patterns, err := parseIncludePatterns([]string{"app/docs/", "*/schema/*.sql"})
+	require.NoError(t, err)
+	require.Len(t, patterns, 2)
+	assert.True(t, isIncluded(patterns, "app/docs"))
+	assert.False(t, isIncluded(patterns, "other/app/docs"))
+	assert.True(t, isIncluded(patterns, "app/schema/init.sql"))
+	assert.False(t, isIncluded(patterns, "app/schema/init.lua"))
+
+	_, err = parseIncludePatterns([]string{"app/[ab"})
+	assert.EqualError(t, err, `invalid include pattern "app/[ab": `+
+		`unterminated character class in "app/[ab"`)
+	_, err = parseIncludePatterns([]string{"/app"})
+	assert.ErrorContains(t, err, "must be a bundle-relative path glob")
+	_, err = parseIncludePatterns([]string{""})
+	assert.ErrorContains(t, err, "must be a bundle-relative path glob")
+}
+
+func Test_applyIncludePatterns(t *testing.T) {
+	bundleDir := t.TempDir()
+	for _, path := range []string{"app/init.lua", "app/docs/README.md", "app/docs/api/index.md",
+		"app/schema/init.sql", "app/schema/init.lua", "bin/tt", "modules/mod.lua"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(bundleDir, path), nil, 0644))
+	}
+	// The included directory is kept even if it is empty.
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "app", "docs", "empty"), 0755))
+
+	packCtx := PackCtx{}
+	require.NoError(t, applyIncludePatterns(&packCtx, bundleDir))
+	assert.FileExists(t, filepath.Join(bundleDir, "bin", "tt"))
+
+	packCtx.includePatterns, _ = parseIncludePatterns([]string{"app/docs", "**/*.sql"})
+	require.NoError(t, applyIncludePatterns(&packCtx, bundleDir))
+	var paths []string
+	require.NoError(t, filepath.Walk(bundleDir, func(path string, _ os.FileInfo, err error) error {
+		relPath, _ := filepath.Rel(bundleDir, path)
+		paths = append(paths, filepath.ToSlash(relPath))
+		return err
+	}))
+	sort.Strings(paths)
+	assert.Equal(t, []string{".", "app", "app/docs", "app/docs/README.md", "app/docs/api",
+		"app/docs/api/index.md", "app/docs/empty", "app/schema", "app/schema/init.sql"}, paths)
+}
+
+func Test_checkIncludedApps(t *testing.T) {
+	bundleDir := t.TempDir()
+	for _, path := range []string{"app1/init.lua", "app1/docs/README.md", "app2/init.lua",
+		"bin/tt"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(bundleDir, path), nil, 0644))
+	}
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+
+	packCtx := PackCtx{AppList: []string{"app1", "app2"}}
+	packCtx.includePatterns, _ = parseIncludePatterns([]string{"app1/docs", "bin"})
+	require.NoError(t, applyIncludePatterns(&packCtx, bundleDir))
+	assert.EqualError(t, checkIncludedApps(bundleDir, &packCtx, opts),
+		"include patterns matched no files of applications: app2, "+
+			"use --allow-empty to pack them empty")
+
+	packCtx.AllowEmpty = true
+	require.NoError(t, checkIncludedApps(bundleDir, &packCtx, opts))
+
+	packCtx = PackCtx{AppList: []string{"app1"}}
+	packCtx.includePatterns, _ = parseIncludePatterns([]string{"app1/docs"})
+	require.NoError(t, checkIncludedApps(bundleDir, &packCtx, opts))
+}
@@ -0,0 +1,216 @@
+package pack
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+)
+
+// IntegrityIndexFileName is a name of the bundle root file listing the sha256 checksums
+// of the bundle regular files. The file has the sha256sum format: a line per file with
+// the hex-encoded checksum, two spaces and the slash-separated path relative to the
+// bundle root. The lines are sorted by path. The bundle is verified with
+// `sha256sum -c .integrity` run in the bundle root or with tt pack verify DIR.
+const IntegrityIndexFileName = ".integrity"
+
+// fileHash is a checksum of the bundle file computed while the file is copied.
+type fileHash struct {
+	// digest is a hex-encoded sha256 checksum.
+	digest string
+	// size is a size of the hashed content.
+	size int64
+}
+
+// integrityHashes collects the checksums of the bundle files computed while the
+// applications are copied, so the files are not read again to write the index.
+type integrityHashes struct {
+	mutex sync.Mutex
+	// hashes are the file checksums by the bundle file path.
+	hashes map[string]fileHash
+	// collected is a time the collection is finished. The files modified later are
+	// hashed again.
+	collected time.Time
+}
+
+// newIntegrityHashes returns the checksums collector.
+func newIntegrityHashes() *integrityHashes {
+	return &integrityHashes{hashes: map[string]fileHash{}}
+}
+
+// hashingReader computes the checksum of the read content and records it on EOF.
+type hashingReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	size   int64
+	done   func(hash fileHash)
+}
+
+// Read reads the content updating the checksum.
+func (reader *hashingReader) Read(buf []byte) (int, error) {
+	n, err := reader.reader.Read(buf)
+	reader.hash.Write(buf[:n])
+	reader.size += int64(n)
+	if err == io.EOF && reader.done != nil {
+		reader.done(fileHash{digest: fmt.Sprintf("%x", reader.hash.Sum(nil)),
+			size: reader.size})
+		reader.done = nil
+	}
+	return n, err
+}
+
+// wrap returns the reader of the file copied to the bundle path recording its checksum.
+// The reader is returned as is if the hashes are not collected.
+func (hashes *integrityHashes) wrap(dstPath string, reader io.Reader) io.Reader {
+	if hashes == nil || dstPath == "" {
+		return reader
+	}
+	return &hashingReader{reader: reader, hash: sha256.New(),
+		done: func(hash fileHash) {
+			hashes.mutex.Lock()
+			hashes.hashes[dstPath] = hash
+			hashes.mutex.Unlock()
+		}}
+}
+
+// finishCollection marks the end of the files collection.
+func (hashes *integrityHashes) finishCollection() {
+	if hashes != nil {
+		hashes.collected = time.Now()
+	}
+}
+
+// get returns the checksum recorded for the bundle file if the file is not changed after
+// the collection.
+func (hashes *integrityHashes) get(path string, info fs.FileInfo) (string, bool) {
+	if hashes == nil || hashes.collected.IsZero() {
+		return "", false
+	}
+	hashes.mutex.Lock()
+	hash, found := hashes.hashes[path]
+	hashes.mutex.Unlock()
+	if !found || hash.size != info.Size() || info.ModTime().After(hashes.collected) {
+		return "", false
+	}
+	return hash.digest, true
+}
+
+// writeIntegrityIndex writes the index of the bundle regular files checksums into
+// the bundle root. The checksums collected while copying the applications are reused,
+// the rest files are hashed.
+func writeIntegrityIndex(bundleEnvPath string, hashes *integrityHashes) error {
+	log.Infof("Generate %s file", IntegrityIndexFileName)
+	indexPath := filepath.Join(bundleEnvPath, IntegrityIndexFileName)
+	lines := []string{}
+	err := filepath.WalkDir(bundleEnvPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() || path == indexPath {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		digest, found := hashes.get(path, info)
+		if !found {
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			digest, err = sha256Hex(file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+		relPath, err := filepath.Rel(bundleEnvPath, path)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, digest+"  "+filepath.ToSlash(relPath))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hash bundle files: %s", err)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return getIndexLinePath(lines[i]) < getIndexLinePath(lines[j])
+	})
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		content += "\n"
+	}
+	if err = os.WriteFile(indexPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %s", indexPath, err)
+	}
+	return nil
+}
+
+// getIndexLinePath returns the file path of the integrity index line.
+func getIndexLinePath(line string) string {
+	_, path, _ := strings.Cut(line, "  ")
+	return path
+}
+
+// VerifyIntegrity checks the files of the bundle directory against the integrity index
+// in its root. The modified and missing files are written to the writer, ErrIntegrity
+// is reported if there are any. The files missing in the index are not checked, they
+// may be created by the running applications.
+func VerifyIntegrity(bundlePath string, writer io.Writer) error {
+	indexPath := filepath.Join(bundlePath, IntegrityIndexFileName)
+	index, err := os.Open(indexPath)
+	if err != nil {
+		return fmt.Errorf("cannot open the integrity index: %s", err)
+	}
+	defer index.Close()
+
+	checked, failed := 0, 0
+	scanner := bufio.NewScanner(index)
+	for scanner.Scan() {
+		digest, relPath, found := strings.Cut(scanner.Text(), "  ")
+		if !found || relPath == "" || !isSubPath(bundlePath, filepath.Join(bundlePath,
+			filepath.FromSlash(relPath))) {
+			return fmt.Errorf("invalid integrity index line %d: %q", checked+failed+1,
+				scanner.Text())
+		}
+		checked++
+		file, err := os.Open(filepath.Join(bundlePath, filepath.FromSlash(relPath)))
+		if os.IsNotExist(err) {
+			fmt.Fprintf(writer, "%s: missing\n", relPath)
+			failed++
+			continue
+		} else if err != nil {
+			return err
+		}
+		actual, err := sha256Hex(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %s", relPath, err)
+		}
+		if actual != digest {
+			fmt.Fprintf(writer, "%s: modified\n", relPath)
+			failed++
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read the integrity index: %s", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d files are modified or missing", ErrIntegrity,
+			failed, checked)
+	}
+	fmt.Fprintf(writer, "%d files are verified.\n", checked)
+	return nil
+}
@@ -0,0 +1,102 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+)
+
+func Test_copyAppSrcIntegrityHashes(t *testing.T) {
+	appDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{"lib"}))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), []byte("init"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "lib", "mod.lua"), []byte("mod"),
+		0644))
+
+	hashes := newIntegrityHashes()
+	packCtx := PackCtx{integrityHashes: hashes}
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{}, appDir,
+		dstDir))
+	hashes.finishCollection()
+
+	// The checksums are computed while the files are copied.
+	for file, digest := range map[string]string{
+		"init.lua":    "bb54068aea85faa7e487530083366be9962390af822e4c71ef1aca7033c83e66",
+		"lib/mod.lua": "e55cffc81a5ad8cfe85239d944a3ae9513645a9eed79bc884f51b80b2760fc46",
+	} {
+		path := filepath.Join(dstDir, file)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		actual, found := hashes.get(path, info)
+		require.True(t, found, file)
+		assert.Equal(t, digest, actual, file)
+	}
+
+	// The file changed after the collection is hashed again.
+	path := filepath.Join(dstDir, "init.lua")
+	mtime := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	_, found := hashes.get(path, info)
+	assert.False(t, found)
+}
+
+func Test_writeIntegrityIndex(t *testing.T) {
+	bundleDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(bundleDir, []string{"app", "bin"}))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "app", "init.lua"),
+		[]byte("init"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "bin", "tt"), []byte("tt"),
+		0755))
+	require.NoError(t, os.Symlink("init.lua", filepath.Join(bundleDir, "app", "link.lua")))
+
+	// The recorded checksum is reused for the file not changed after the collection.
+	hashes := newIntegrityHashes()
+	hashes.hashes[filepath.Join(bundleDir, "app", "init.lua")] = fileHash{digest: "recorded",
+		size: 4}
+	hashes.finishCollection()
+	require.NoError(t, writeIntegrityIndex(bundleDir, hashes))
+	content, err := os.ReadFile(filepath.Join(bundleDir, IntegrityIndexFileName))
+	require.NoError(t, err)
+	assert.Equal(t, "recorded  app/init.lua\n"+
+		"0e07cf830957701d43c183f1515f63e6b68027e528f43ef52b1527a520ddec82  bin/tt\n",
+		string(content))
+
+	require.NoError(t, writeIntegrityIndex(bundleDir, nil))
+	var output bytes.Buffer
+	require.NoError(t, VerifyIntegrity(bundleDir, &output))
+	assert.Equal(t, "2 files are verified.\n", output.String())
+
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "app", "init.lua"),
+		[]byte("changed"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(bundleDir, "bin", "tt")))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "app", "new.lua"), nil, 0644))
+	output.Reset()
+	err = VerifyIntegrity(bundleDir, &output)
+	assert.ErrorIs(t, err, ErrIntegrity)
+	assert.ErrorContains(t, err, "2 of 2 files are modified or missing")
+	assert.Equal(t, "app/init.lua: modified\nbin/tt: missing\n", output.String())
+}
+
+func TestVerifyIntegrityInvalidIndex(t *testing.T) {
+	bundleDir := t.TempDir()
+	err := VerifyIntegrity(bundleDir, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "cannot open the integrity index")
+
+	for _, line := range []string{"digest", "digest  ../outside", "digest  "} {
+		require.NoError(t, os.WriteFile(filepath.Join(bundleDir, IntegrityIndexFileName),
+			[]byte(line+"\n"), 0644))
+		err = VerifyIntegrity(bundleDir, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "invalid integrity index line 1")
+	}
+}
@@ -1,6 +1,8 @@
 package pack
 
 import (
+	"io/fs"
+
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
 )
@@ -9,3 +11,13 @@ import (
 type Packer interface {
 	Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, opts *config.CliOpts) error
 }
+
+// FileSystem is a file system the packed files are read from. The names are
+// slash-separated paths relative to the file system root as in fs.FS.
+type FileSystem interface {
+	fs.ReadDirFS
+	// Lstat returns the file info. The symlink is not followed.
+	Lstat(name string) (fs.FileInfo, error)
+	// ReadLink returns the symlink target.
+	ReadLink(name string) (string, error)
+}
@@ -0,0 +1,75 @@
+package pack
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PackageFile describes an entry of the verified package.
+type PackageFile struct {
+	// Path is a slash-separated entry path relative to the package root.
+	Path string `json:"path"`
+	// Size is a size of the entry content in bytes.
+	Size int64 `json:"size"`
+	// Mode is the entry file mode in ls format, for example: -rw-r--r--.
+	Mode string `json:"mode"`
+	// SHA256 is a checksum of the regular file content. It is empty for the other entries.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// visitInventoryEntry calls the visitor for the package entry and passes the entry
+// description to the inventory. The regular file content is hashed while it is read.
+func visitInventoryEntry(entry packageEntry, visit entryVisitor,
+	inventory func(file PackageFile) error) error {
+	file := PackageFile{Path: getEntryPath(entry), Size: entry.size,
+		Mode: entry.mode.String()}
+	if entry.content == nil {
+		if err := visit(entry); err != nil {
+			return err
+		}
+		return inventory(file)
+	}
+
+	hash := sha256.New()
+	entry.content = io.TeeReader(entry.content, hash)
+	if err := visitEntry(visit, entry); err != nil {
+		return err
+	}
+	file.SHA256 = fmt.Sprintf("%x", hash.Sum(nil))
+	return inventory(file)
+}
+
+// WritePackageInventory verifies the package like VerifyPackage and writes the JSON object
+// with the list of all package entries in "files" and the package description in "package".
+// For deb and rpm the payload entries are listed. The entries are written while
+// the package is read, so the listing is not kept in memory. The written JSON is
+// incomplete if the package is corrupted.
+func WritePackageInventory(packagePath string, writer io.Writer) error {
+	if _, err := io.WriteString(writer, `{"files": [`); err != nil {
+		return err
+	}
+	separator := "\n  "
+	info, err := verifyPackage(packagePath, func(file PackageFile) error {
+		content, err := json.Marshal(file)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(writer, separator); err != nil {
+			return err
+		}
+		separator = ",\n  "
+		_, err = writer.Write(content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to encode the package info: %s", err)
+	}
+	_, err = fmt.Fprintf(writer, "\n], \"package\": %s}\n", content)
+	return err
+}
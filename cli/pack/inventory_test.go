@@ -0,0 +1,69 @@
+package pack
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packageInventory is the decoded WritePackageInventory output.
+type packageInventory struct {
+	Files   []PackageFile `json:"files"`
+	Package PackageInfo   `json:"package"`
+}
+
+func TestWritePackageInventory(t *testing.T) {
+	digest := func(content string) string {
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+	}
+	tgzPath := writeTestTgz(t, testBundleEntries)
+	tests := []struct {
+		packageType string
+		path        string
+		expected    []PackageFile
+	}{
+		{Tgz, tgzPath, []PackageFile{
+			{"bin", 0, "drwxr-xr-x", ""},
+			{"bin/tarantool", 9, "-rw-r--r--", digest("tarantool")},
+			{"bin/tt", 2, "-rw-r--r--", digest("tt")},
+			{"instances.enabled", 0, "drwxr-xr-x", ""},
+			{"instances.enabled/app1", 0, "L---------", ""},
+			{"manifest.json", int64(len(testManifest)), "-rw-r--r--", digest(testManifest)},
+			{"tt.yaml", 5, "-rw-r--r--", digest("env:\n")},
+		}},
+		{Rpm, writeTestRpm(t, [][2]string{
+			{"usr/", ""},
+			{"usr/share/tarantool/bundle/tt.yaml", "env:\n"},
+		}), []PackageFile{
+			{"usr", 0, "drwxr-xr-x", ""},
+			{"usr/share/tarantool/bundle/tt.yaml", 5, "-rw-r--r--", digest("env:\n")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.packageType, func(t *testing.T) {
+			output := bytes.Buffer{}
+			require.NoError(t, WritePackageInventory(tt.path, &output))
+			var inventory packageInventory
+			require.NoError(t, json.Unmarshal(output.Bytes(), &inventory))
+			assert.Equal(t, tt.expected, inventory.Files)
+
+			info, err := VerifyPackage(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, info, inventory.Package)
+		})
+	}
+
+	// The deb data archive entries are listed.
+	output := bytes.Buffer{}
+	require.NoError(t, WritePackageInventory(writeTestDeb(t, tgzPath), &output))
+	var inventory packageInventory
+	require.NoError(t, json.Unmarshal(output.Bytes(), &inventory))
+	require.Len(t, inventory.Files, len(testBundleEntries))
+	assert.Equal(t, "bin/tt", inventory.Files[2].Path)
+	assert.Equal(t, digest("tt"), inventory.Files[2].SHA256)
+}
@@ -0,0 +1,59 @@
+package pack
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/util"
+)
+
+const (
+	// DefaultIORetries is a default number of retries of the failed binary copying.
+	DefaultIORetries = 3
+	// maxIORetryDelay bounds the delay between the copying retries.
+	maxIORetryDelay = 5 * time.Second
+)
+
+// ioRetryDelay is a delay before the first retry. It is doubled for each next retry.
+var ioRetryDelay = 200 * time.Millisecond
+
+// isPermanentIOError returns true if retrying the operation failed with the error
+// does not make sense.
+func isPermanentIOError(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) ||
+		isNoSpaceError(err)
+}
+
+// retryIO runs the I/O operation on the path. The transient failures, for example,
+// I/O errors of a network filesystem, are retried up to IORetries times with an
+// exponential backoff. The permanent failures are returned immediately.
+func retryIO(packCtx *PackCtx, path string, operation func() error) error {
+	ctx := packCtx.operation.context()
+	delay := ioRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := operation()
+		if err == nil || isPermanentIOError(err) || attempt >= packCtx.IORetries {
+			return err
+		}
+		log.Debugf("Failed to process %s: %s. Retrying in %s (%d/%d)", path, err, delay,
+			attempt+1, packCtx.IORetries)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s, retrying is canceled: %s", err, ctx.Err())
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxIORetryDelay {
+			delay = maxIORetryDelay
+		}
+	}
+}
+
+// copyBinary copies the binary into the bundle retrying the transient failures.
+func copyBinary(packCtx *PackCtx, src, dst string) error {
+	return retryIO(packCtx, src, func() error {
+		return util.CopyFileDeep(src, dst)
+	})
+}
@@ -0,0 +1,74 @@
+package pack
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_retryIO(t *testing.T) {
+	defer func(delay time.Duration) { ioRetryDelay = delay }(ioRetryDelay)
+	ioRetryDelay = time.Millisecond
+
+	transientErr := &fs.PathError{Op: "read", Path: "tarantool", Err: syscall.EIO}
+	cases := []struct {
+		name          string
+		retries       int
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{"success", 3, nil, 1, nil},
+		{"transient failure", 3, []error{transientErr, transientErr}, 3, nil},
+		{"retries exceeded", 2, []error{transientErr, transientErr, transientErr}, 3,
+			transientErr},
+		{"no retries", 0, []error{transientErr}, 1, transientErr},
+		{"missing file", 3, []error{fs.ErrNotExist}, 1, fs.ErrNotExist},
+		{"permission denied", 3, []error{fmt.Errorf("copy: %w", fs.ErrPermission)}, 1,
+			fs.ErrPermission},
+		{"no space left", 3, []error{fmt.Errorf("copy: %w", syscall.ENOSPC)}, 1,
+			syscall.ENOSPC},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			err := retryIO(&PackCtx{IORetries: tc.retries}, "tarantool", func() error {
+				calls++
+				if calls <= len(tc.errs) {
+					return tc.errs[calls-1]
+				}
+				return nil
+			})
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.expectedErr), err)
+			}
+		})
+	}
+}
+
+func Test_copyBinary(t *testing.T) {
+	srcDir := t.TempDir()
+	src := filepath.Join(srcDir, "tarantool")
+	require.NoError(t, os.WriteFile(src, []byte("binary"), 0755))
+	dst := filepath.Join(t.TempDir(), "tarantool")
+
+	packCtx := &PackCtx{IORetries: DefaultIORetries}
+	require.NoError(t, copyBinary(packCtx, src, dst))
+	content, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(content))
+
+	err = copyBinary(packCtx, filepath.Join(srcDir, "missing"), dst)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
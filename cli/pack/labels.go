@@ -0,0 +1,51 @@
+package pack
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// debLabelFieldPrefix is a prefix of the Deb control file fields containing the labels.
+const debLabelFieldPrefix = "X-Tt-Label-"
+
+// labelKeyRe matches the label key.
+var labelKeyRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// parseLabels parses key=value labels. The keys must be unique and consist of letters,
+// digits, '.', '_' and '-'. The values must be single-line.
+func parseLabels(labels []string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(labels))
+	for _, label := range labels {
+		key, value, found := strings.Cut(label, "=")
+		if !found || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", label)
+		}
+		if !labelKeyRe.MatchString(key) {
+			return nil, fmt.Errorf("invalid label %q: key must consist of letters, digits,"+
+				" '.', '_' and '-'", label)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid label %q: value must be single-line", label)
+		}
+		if _, found := parsed[key]; found {
+			return nil, fmt.Errorf("label %q is passed several times", key)
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
+
+// getDebLabelFields returns the Deb control file fields with the labels sorted by key.
+func getDebLabelFields(labels map[string]string) []string {
+	fields := make([]string, 0, len(labels))
+	for key, value := range labels {
+		fields = append(fields, debLabelFieldPrefix+key+": "+value)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,40 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseLabels(t *testing.T) {
+	labels, err := parseLabels(nil)
+	require.NoError(t, err)
+	assert.Nil(t, labels)
+
+	labels, err = parseLabels([]string{"git.sha=abc123", "build_number=42",
+		"pipeline-url=https://ci.example.com/job?id=1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"git.sha":      "abc123",
+		"build_number": "42",
+		"pipeline-url": "https://ci.example.com/job?id=1",
+	}, labels)
+
+	tests := map[string]string{
+		"build":        `invalid label "build": expected key=value`,
+		"=42":          `invalid label "=42": expected key=value`,
+		"build=":       `invalid label "build=": expected key=value`,
+		"build id=42":  `invalid label "build id=42": key must consist of letters, digits,`,
+		".build=42":    `invalid label ".build=42": key must consist of letters, digits,`,
+		"build=4\n2":   `invalid label "build=4\n2": value must be single-line`,
+		"build:id=42:": `invalid label "build:id=42:": key must consist of letters, digits,`,
+	}
+	for label, expectedErr := range tests {
+		_, err = parseLabels([]string{label})
+		assert.ErrorContains(t, err, expectedErr)
+	}
+
+	_, err = parseLabels([]string{"build=1", "build=2"})
+	assert.EqualError(t, err, `label "build" is passed several times`)
+}
@@ -0,0 +1,41 @@
+package pack
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// ListApps prints the names and the source paths of the applications discovered
+// for packing. The package is not created.
+func ListApps(packCtx *PackCtx, writer io.Writer) error {
+	if packCtx.SourceDir != "" {
+		for _, appName := range packCtx.AppList {
+			fmt.Fprintf(writer, "%s\t%s\n", appName, packCtx.SourceDir)
+		}
+		return nil
+	}
+
+	appNames := make([]string, 0, len(packCtx.AppsInfo))
+	for appName := range packCtx.AppsInfo {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+	for _, appName := range appNames {
+		instances := packCtx.AppsInfo[appName]
+		if len(instances) == 0 {
+			return fmt.Errorf("application %q does not have any instances", appName)
+		}
+		appPath := instances[0].AppDir
+		if instances[0].IsFileApp {
+			appPath = instances[0].InstanceScript
+		}
+		resolvedAppPath, err := filepath.EvalSymlinks(appPath)
+		if err != nil {
+			return fmt.Errorf("cannot resolve application %q path: %s", appName, err)
+		}
+		fmt.Fprintf(writer, "%s\t%s\n", appName, resolvedAppPath)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package pack
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func TestListApps(t *testing.T) {
+	envDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	instancesDir := filepath.Join(envDir, "instances.enabled")
+	realAppDir := filepath.Join(envDir, "apps", "real_app")
+	require.NoError(t, os.MkdirAll(realAppDir, 0755))
+	require.NoError(t, os.Mkdir(instancesDir, 0755))
+	require.NoError(t, os.Symlink(realAppDir, filepath.Join(instancesDir, "app")))
+	scriptPath := filepath.Join(instancesDir, "script.lua")
+	require.NoError(t, os.WriteFile(scriptPath, nil, 0644))
+
+	packCtx := PackCtx{AppsInfo: map[string][]running.InstanceCtx{
+		"script": {{AppDir: filepath.Join(instancesDir, "script"), IsFileApp: true,
+			InstanceScript: scriptPath}},
+		"app": {{AppDir: filepath.Join(instancesDir, "app")}},
+	}}
+	var output bytes.Buffer
+	require.NoError(t, ListApps(&packCtx, &output))
+	assert.Equal(t, "app\t"+realAppDir+"\nscript\t"+scriptPath+"\n", output.String())
+
+	output.Reset()
+	packCtx = PackCtx{SourceDir: "/opt/bundle", AppList: []string{"app1", "app2"}}
+	require.NoError(t, ListApps(&packCtx, &output))
+	assert.Equal(t, "app1\t/opt/bundle\napp2\t/opt/bundle\n", output.String())
+}
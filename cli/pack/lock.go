@@ -0,0 +1,125 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/apex/log"
+)
+
+const (
+	// DefaultLockTimeout is a default time to wait for the package lock held by another
+	// pack operation.
+	DefaultLockTimeout = time.Minute
+	// lockFileSuffix is a suffix of the package lock file.
+	lockFileSuffix = ".lock"
+	// lockPollInterval is an interval of the package lock acquiring attempts.
+	lockPollInterval = 100 * time.Millisecond
+)
+
+// packageLock is an exclusive lock of the package path held while the package is written.
+type packageLock struct {
+	// file is the locked lock file.
+	file *os.File
+}
+
+// getLockFilePath returns the path of the lock file of the package.
+func getLockFilePath(packagePath string) string {
+	return filepath.Join(filepath.Dir(packagePath),
+		"."+filepath.Base(packagePath)+lockFileSuffix)
+}
+
+// tryLockFile tries to lock the lock file without blocking. Returns nil file if
+// the lock is held by another process.
+func tryLockFile(lockPath string) (*os.File, error) {
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open lock file: %s", err)
+	}
+	if err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if err == syscall.EWOULDBLOCK {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot lock %s: %s", lockPath, err)
+	}
+	// The lock file may be removed by the previous holder after we opened it, so the lock
+	// is valid only if the file is still at the lock path.
+	fileStat, err := file.Stat()
+	if err == nil {
+		var pathStat os.FileInfo
+		if pathStat, err = os.Stat(lockPath); err == nil && os.SameFile(fileStat, pathStat) {
+			return file, nil
+		}
+	}
+	file.Close()
+	return nil, nil
+}
+
+// lockPackage locks the package path, so the pack operations writing the same package do
+// not clobber each other's temporary files. The lock held by another pack operation is
+// waited for LockTimeout, ErrLocked is reported if it is not released. Nil lock is
+// returned if NoLock is set.
+func lockPackage(packCtx *PackCtx, packagePath string) (*packageLock, error) {
+	if packCtx.NoLock {
+		return nil, nil
+	}
+	lockPath := getLockFilePath(packagePath)
+	ctx := packCtx.operation.context()
+	deadline := time.Now().Add(packCtx.LockTimeout)
+	waitLogged := false
+	for {
+		file, err := tryLockFile(lockPath)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			return &packageLock{file: file}, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("%w: %s is locked, use --no-lock to write it anyway",
+				ErrLocked, packagePath)
+		}
+		if !waitLogged {
+			log.Infof("Waiting for %s to be written by another pack operation.", packagePath)
+			waitLogged = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// lockArtifact locks the package path for the rest of the pack operation, so the package
+// file and its sidecar files (checksum, volumes, signature, SBOM) are written
+// exclusively. The lock is released by Pack.
+func lockArtifact(packCtx *PackCtx, packagePath string) error {
+	lock, err := lockPackage(packCtx, packagePath)
+	if err != nil {
+		return err
+	}
+	packCtx.artifactLock = lock
+	return nil
+}
+
+// unlockArtifact releases the artifact lock taken by lockArtifact.
+func unlockArtifact(packCtx *PackCtx) {
+	packCtx.artifactLock.unlock()
+	packCtx.artifactLock = nil
+}
+
+// unlock removes the lock file and releases the lock.
+func (lock *packageLock) unlock() {
+	if lock == nil {
+		return
+	}
+	if err := os.Remove(lock.file.Name()); err != nil {
+		log.Warnf("Failed to remove lock file %s: %s", lock.file.Name(), err)
+	}
+	lock.file.Close()
+}
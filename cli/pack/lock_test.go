@@ -0,0 +1,82 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/util"
+)
+
+func Test_lockPackage(t *testing.T) {
+	packagePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	lockPath := filepath.Join(filepath.Dir(packagePath), ".bundle.tar.gz.lock")
+
+	lock, err := lockPackage(&PackCtx{}, packagePath)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.FileExists(t, lockPath)
+
+	// The lock held by another operation is not waited if the timeout is zero.
+	_, err = lockPackage(&PackCtx{}, packagePath)
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.ErrorContains(t, err, packagePath+" is locked, use --no-lock")
+
+	// Locking is disabled.
+	noLock, err := lockPackage(&PackCtx{NoLock: true}, packagePath)
+	require.NoError(t, err)
+	assert.Nil(t, noLock)
+	noLock.unlock()
+
+	// The lock is waited until it is released.
+	heldLock := lock
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		heldLock.unlock()
+	}()
+	lock, err = lockPackage(&PackCtx{LockTimeout: 5 * time.Second}, packagePath)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	lock.unlock()
+	assert.NoFileExists(t, lockPath)
+}
+
+func TestPackLocked(t *testing.T) {
+	arch, err := util.GetArch()
+	require.NoError(t, err)
+	sourceDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "app", "init.lua"),
+		[]byte("print(1)"), 0644))
+	outputDir := t.TempDir()
+	packagePath := filepath.Join(outputDir, "bundle-1.0.0."+arch+".tar.gz")
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	newPackCtx := func() *PackCtx {
+		return &PackCtx{Type: Tgz, Name: "bundle", Version: "1.0.0", SourceDir: sourceDir,
+			OutputDir: outputDir, WithChecksum: true,
+			Archive: ArchiveCtx{CompressionLevel: DefaultCompressionLevel}}
+	}
+
+	// Neither the package nor its sidecar files are written while the path is locked.
+	lock, err := lockPackage(&PackCtx{}, packagePath)
+	require.NoError(t, err)
+	_, err = Pack(&cmdcontext.CmdCtx{}, newPackCtx(), opts)
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.NoFileExists(t, packagePath)
+	assert.NoFileExists(t, packagePath+checksumFileSuffix)
+	lock.unlock()
+
+	// The lock is held until the sidecar files are written and released by Pack.
+	packCtx := newPackCtx()
+	_, err = Pack(&cmdcontext.CmdCtx{}, packCtx, opts)
+	require.NoError(t, err)
+	assert.FileExists(t, packagePath)
+	assert.FileExists(t, packagePath+checksumFileSuffix)
+	assert.Nil(t, packCtx.artifactLock)
+	assert.NoFileExists(t, getLockFilePath(packagePath))
+}
@@ -0,0 +1,60 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// maintainerScriptParams contains the parameters for the maintainer scripts rendering.
+type maintainerScriptParams struct {
+	// Name is a package name.
+	Name string
+	// Version is a package version.
+	Version string
+	// Prefix is an absolute path in the target system where the environment is installed.
+	Prefix string
+	// AppList is a sorted list of packed applications.
+	AppList []string
+}
+
+// loadMaintainerScripts renders the user install and remove scripts set in pack context.
+// The scripts are text/template templates, so the scripts without template actions are
+// used as is.
+func loadMaintainerScripts(packCtx *PackCtx, cliOpts *config.CliOpts) error {
+	params := maintainerScriptParams{
+		Name:    packCtx.Name,
+		Version: getVersion(packCtx, cliOpts, defaultVersion),
+		Prefix:  "/" + getInstallPrefix(packCtx),
+		AppList: append([]string{}, packCtx.AppList...),
+	}
+	sort.Strings(params.AppList)
+
+	scripts := []struct {
+		path    string
+		content *string
+	}{
+		{packCtx.RpmDeb.PreInst, &packCtx.RpmDeb.preInstScript},
+		{packCtx.RpmDeb.PostInst, &packCtx.RpmDeb.postInstScript},
+		{packCtx.RpmDeb.PreRm, &packCtx.RpmDeb.preRmScript},
+		{packCtx.RpmDeb.PostRm, &packCtx.RpmDeb.postRmScript},
+	}
+	for _, script := range scripts {
+		if script.path == "" {
+			continue
+		}
+		content, err := os.ReadFile(script.path)
+		if err != nil {
+			return fmt.Errorf("cannot read script %q: %s", script.path, err)
+		}
+		scriptTemplate := string(content)
+		if *script.content, err = util.GetTextTemplatedStr(&scriptTemplate,
+			params); err != nil {
+			return fmt.Errorf("failed to render script %q: %s", script.path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_loadMaintainerScripts(t *testing.T) {
+	scriptsDir := t.TempDir()
+	preInstPath := filepath.Join(scriptsDir, "preinst.sh")
+	postInstPath := filepath.Join(scriptsDir, "postinst.sh")
+	require.NoError(t, os.WriteFile(preInstPath, []byte("echo ${HOME}\n"), 0755))
+	require.NoError(t, os.WriteFile(postInstPath, []byte(
+		"echo {{ .Name }} {{ .Version }} {{ .Prefix }}\n"+
+			"{{ range .AppList }}chown -R tarantool {{ $.Prefix }}/{{ . }}\n{{ end }}"), 0755))
+
+	packCtx := PackCtx{Name: "env", Version: "1.2.3", AppList: []string{"app2", "app1"},
+		RpmDeb: RpmDebCtx{PreInst: preInstPath, PostInst: postInstPath, InstallPrefix: "/opt"}}
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	require.NoError(t, loadMaintainerScripts(&packCtx, cliOpts))
+	// Script without template actions is not changed.
+	assert.Equal(t, "echo ${HOME}\n", packCtx.RpmDeb.preInstScript)
+	assert.Equal(t, "echo env 1.2.3 /opt\nchown -R tarantool /opt/app1\n"+
+		"chown -R tarantool /opt/app2\n", packCtx.RpmDeb.postInstScript)
+
+	require.NoError(t, os.WriteFile(postInstPath, []byte("echo {{ .Unknown }}"), 0755))
+	assert.ErrorContains(t, loadMaintainerScripts(&packCtx, cliOpts),
+		`failed to render script "`+postInstPath+`"`)
+
+	require.NoError(t, os.WriteFile(postInstPath, []byte("echo {{ .Name"), 0755))
+	assert.ErrorContains(t, loadMaintainerScripts(&packCtx, cliOpts),
+		`failed to render script "`+postInstPath+`"`)
+
+	// Remove scripts are rendered the same way.
+	require.NoError(t, os.WriteFile(postInstPath, []byte("echo {{ .Name }}"), 0755))
+	packCtx.RpmDeb.PreRm = preInstPath
+	packCtx.RpmDeb.PostRm = postInstPath
+	require.NoError(t, loadMaintainerScripts(&packCtx, cliOpts))
+	assert.Equal(t, "echo ${HOME}\n", packCtx.RpmDeb.preRmScript)
+	assert.Equal(t, "echo env", packCtx.RpmDeb.postRmScript)
+
+	packCtx.RpmDeb.PostInst = filepath.Join(scriptsDir, "missing.sh")
+	assert.ErrorContains(t, loadMaintainerScripts(&packCtx, cliOpts), "cannot read script")
+}
+
+func Test_writeMaintainerScript(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "postinst.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("echo {{ .Name }}"), 0700))
+
+	destDir := t.TempDir()
+	require.NoError(t, writeMaintainerScript(destDir, PostInstScriptName, scriptPath,
+		"echo env"))
+	content, err := os.ReadFile(filepath.Join(destDir, PostInstScriptName))
+	require.NoError(t, err)
+	assert.Equal(t, "echo env", string(content))
+	stat, err := os.Stat(filepath.Join(destDir, PostInstScriptName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), stat.Mode().Perm())
+}
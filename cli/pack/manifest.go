@@ -0,0 +1,192 @@
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/version"
+)
+
+// manifestFileName is a name of the file describing the bundle content.
+const manifestFileName = "manifest.json"
+
+// manifestApp describes an application in the bundle.
+type manifestApp struct {
+	// Name is an application name.
+	Name string `json:"name"`
+	// Files is a count of application files in the bundle.
+	Files int `json:"files"`
+	// Version is an application version. It is the package version if the application
+	// version is not overridden.
+	Version string `json:"version"`
+}
+
+// manifestBinary describes a binary included into the bundle.
+type manifestBinary struct {
+	// Name is a binary name: tarantool or tt.
+	Name string `json:"name"`
+	// Version is a detected binary version. It is empty if the version is not detected.
+	Version string `json:"version,omitempty"`
+	// SHA256 is a checksum of the bundled binary.
+	SHA256 string `json:"sha256"`
+}
+
+// bundleManifest is a machine-readable description of the bundle.
+type bundleManifest struct {
+	// Name is a package name.
+	Name string `json:"name"`
+	// Version is a package version.
+	Version string `json:"version"`
+	// TtVersion is a version of tt packed the bundle.
+	TtVersion string `json:"tt_version"`
+	// TarantoolVersion is a version of tarantool binary included into the bundle.
+	TarantoolVersion string `json:"tarantool_version,omitempty"`
+	// BuildTime is a bundle build time in RFC 3339 format.
+	BuildTime string `json:"build_time"`
+	// Apps is a list of packed applications.
+	Apps []manifestApp `json:"apps"`
+	// Labels are the key=value metadata set with --label option.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Binaries are the binaries included into the bundle with --with-binaries option.
+	Binaries []manifestBinary `json:"binaries,omitempty"`
+}
+
+// getBuildTime returns the bundle build time. SOURCE_DATE_EPOCH is used if it is set
+// to support reproducible builds.
+func getBuildTime(packCtx *PackCtx) time.Time {
+	if packCtx.sourceDateEpoch != nil {
+		return *packCtx.sourceDateEpoch
+	}
+	return time.Now().UTC()
+}
+
+// countFiles returns a count of non-directory entries in the passed path.
+func countFiles(path string) (int, error) {
+	count := 0
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// getBinaryVersion returns the version of the bundled binary.
+func getBinaryVersion(packCtx *PackCtx, name, path string) (string, error) {
+	if name == "tarantool" {
+		tntCli := cmdcontext.TarantoolCli{Executable: path}
+		tntVersion, err := tntCli.GetVersion()
+		return tntVersion.Str, err
+	}
+	if packCtx.TtBinary == "" && packCtx.archBinDir == "" {
+		// The running tt is bundled.
+		return version.GetVersion(true, false), nil
+	}
+	ttVersion, err := cmdcontext.GetTtVersion(path)
+	return ttVersion.Str, err
+}
+
+// getManifestBinaries returns the description of the bundled binaries sorted by name.
+func getManifestBinaries(packCtx *PackCtx) ([]manifestBinary, error) {
+	names := make([]string, 0, len(packCtx.bundledBinaries))
+	for name := range packCtx.bundledBinaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	binaries := make([]manifestBinary, 0, len(names))
+	for _, name := range names {
+		path := packCtx.bundledBinaries[name]
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s binary: %s", name, err)
+		}
+		digest, err := sha256Hex(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s binary: %s", name, err)
+		}
+		binVersion, err := getBinaryVersion(packCtx, name, path)
+		if err != nil {
+			log.Warnf("Failed to get %s version for %s: %s", name, manifestFileName, err)
+		}
+		binaries = append(binaries, manifestBinary{Name: name, Version: binVersion,
+			SHA256: digest})
+	}
+	return binaries, nil
+}
+
+// getPackerVersion returns the version of the running tt that packs the bundle.
+func getPackerVersion() string {
+	return version.GetVersion(true, false)
+}
+
+// generateManifest writes manifest.json file into the bundle root.
+func generateManifest(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, cliOpts *config.CliOpts,
+	bundleEnvPath string) error {
+	log.Infof("Generate %s file", manifestFileName)
+
+	var err error
+	manifest := bundleManifest{
+		Name:      packCtx.Name,
+		Version:   getVersion(packCtx, cliOpts, defaultVersion),
+		TtVersion: getPackerVersion(),
+		BuildTime: getBuildTime(packCtx).Format(time.RFC3339),
+		Apps:      []manifestApp{},
+		Labels:    packCtx.labels,
+	}
+
+	if isTarantoolBundled(packCtx) && cmdCtx.Cli.TarantoolCli.Executable != "" {
+		if tntVersion, err := cmdCtx.Cli.TarantoolCli.GetVersion(); err != nil {
+			log.Warnf("Failed to get tarantool version for %s: %s", manifestFileName, err)
+		} else {
+			manifest.TarantoolVersion = tntVersion.Str
+		}
+	}
+
+	if packCtx.WithBinaries {
+		if manifest.Binaries, err = getManifestBinaries(packCtx); err != nil {
+			return err
+		}
+	}
+
+	for appName, instances := range packCtx.AppsInfo {
+		if len(instances) == 0 {
+			continue
+		}
+		// Script application is a single file.
+		filesCount := 1
+		if !instances[0].IsFileApp {
+			appPath := getDestAppDir(bundleEnvPath, appName, packCtx, cliOpts)
+			if filesCount, err = countFiles(appPath); err != nil {
+				return fmt.Errorf("failed to count %q application files: %s", appName, err)
+			}
+		}
+		manifest.Apps = append(manifest.Apps, manifestApp{Name: appName, Files: filesCount,
+			Version: getAppVersion(packCtx, appName, manifest.Version)})
+	}
+	sort.Slice(manifest.Apps, func(i, j int) bool {
+		return manifest.Apps[i].Name < manifest.Apps[j].Name
+	})
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %s", manifestFileName, err)
+	}
+	manifestPath := filepath.Join(bundleEnvPath, manifestFileName)
+	if err = os.WriteFile(manifestPath, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %s", manifestPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package pack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func Test_generateManifest(t *testing.T) {
+	epoch := time.Unix(1700000000, 0).UTC()
+	bundleDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(bundleDir, []string{"app", "app/lib"}))
+	require.NoError(t, test_helpers.CreateFiles(bundleDir, []string{
+		"app/init.lua", "app/lib/mod.lua", "script.lua"}))
+
+	packCtx := &PackCtx{
+		Name:            "bundle",
+		Version:         "1.2.3",
+		WithoutBinaries: true,
+		sourceDateEpoch: &epoch,
+		AppsInfo: map[string][]running.InstanceCtx{
+			"script": {{AppName: "script", IsFileApp: true}},
+			"app":    {{AppName: "app"}},
+		},
+	}
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	require.NoError(t, generateManifest(&cmdcontext.CmdCtx{}, packCtx, cliOpts, bundleDir))
+
+	content, err := os.ReadFile(filepath.Join(bundleDir, manifestFileName))
+	require.NoError(t, err)
+	var manifest bundleManifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	assert.Equal(t, "bundle", manifest.Name)
+	assert.Equal(t, "1.2.3", manifest.Version)
+	assert.NotEmpty(t, manifest.TtVersion)
+	assert.Empty(t, manifest.TarantoolVersion)
+	assert.Equal(t, "2023-11-14T22:13:20Z", manifest.BuildTime)
+	assert.Equal(t, []manifestApp{{"app", 2, "1.2.3"}, {"script", 1, "1.2.3"}}, manifest.Apps)
+	assert.Nil(t, manifest.Labels)
+	assert.NotContains(t, string(content), "labels")
+
+	packCtx.labels = map[string]string{"git.sha": "abc123", "build": "42"}
+	require.NoError(t, generateManifest(&cmdcontext.CmdCtx{}, packCtx, cliOpts, bundleDir))
+	content, err = os.ReadFile(filepath.Join(bundleDir, manifestFileName))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	assert.Equal(t, packCtx.labels, manifest.Labels)
+
+	packCtx.appVersions = map[string]string{"script": "2.0.0"}
+	require.NoError(t, generateManifest(&cmdcontext.CmdCtx{}, packCtx, cliOpts, bundleDir))
+	content, err = os.ReadFile(filepath.Join(bundleDir, manifestFileName))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	assert.Equal(t, []manifestApp{{"app", 2, "1.2.3"}, {"script", 1, "2.0.0"}}, manifest.Apps)
+}
+
+func Test_generateManifestBinaries(t *testing.T) {
+	bundleDir := t.TempDir()
+	tarantoolPath := filepath.Join(bundleDir, "tarantool")
+	ttPath := filepath.Join(bundleDir, "tt")
+	require.NoError(t, os.WriteFile(tarantoolPath,
+		[]byte("#!/bin/sh\necho Tarantool 2.11.1\n"), 0755))
+	require.NoError(t, os.WriteFile(ttPath, []byte("#!/bin/sh\necho 2.3.0.1a2b3c4\n"), 0755))
+
+	packCtx := &PackCtx{
+		Name:            "bundle",
+		Version:         "1.2.3",
+		WithBinaries:    true,
+		TtBinary:        ttPath,
+		bundledBinaries: map[string]string{"tarantool": tarantoolPath, "tt": ttPath},
+	}
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	require.NoError(t, generateManifest(&cmdcontext.CmdCtx{}, packCtx, cliOpts, bundleDir))
+
+	content, err := os.ReadFile(filepath.Join(bundleDir, manifestFileName))
+	require.NoError(t, err)
+	var manifest bundleManifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	require.Len(t, manifest.Binaries, 2)
+	assert.Equal(t, "tarantool", manifest.Binaries[0].Name)
+	assert.Equal(t, "2.11.1", manifest.Binaries[0].Version)
+	assert.Len(t, manifest.Binaries[0].SHA256, 64)
+	assert.Equal(t, "tt", manifest.Binaries[1].Name)
+	assert.Equal(t, "2.3.0.1a2b3c4", manifest.Binaries[1].Version)
+	assert.NotEqual(t, manifest.Binaries[0].SHA256, manifest.Binaries[1].SHA256)
+
+	// The binaries are recorded only if --with-binaries is set.
+	packCtx.WithBinaries = false
+	require.NoError(t, generateManifest(&cmdcontext.CmdCtx{}, packCtx, cliOpts, bundleDir))
+	content, err = os.ReadFile(filepath.Join(bundleDir, manifestFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "binaries")
+}
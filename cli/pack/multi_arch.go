@@ -0,0 +1,199 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/search"
+	"github.com/tarantool/tt/cli/util"
+	"github.com/tarantool/tt/cli/version"
+)
+
+// archIndependent contains the names of the architecture-independent packages.
+var archIndependent = []string{"all", "noarch"}
+
+// getPackageArches returns the architectures of the package type set by comma-separated
+// --deb-arch or --rpm-arch list.
+func getPackageArches(packCtx *PackCtx, packType string) []string {
+	arches := ""
+	switch packType {
+	case Deb:
+		arches = packCtx.RpmDeb.DebArch
+	case Rpm:
+		arches = packCtx.RpmDeb.RpmArch
+	}
+	if arches == "" {
+		return nil
+	}
+	return strings.Split(arches, ",")
+}
+
+// checkPackageArches checks each architecture of the comma-separated list is one of the
+// known architecture names of the package format.
+func checkPackageArches(format, arches string, known []string) error {
+	for _, arch := range strings.Split(arches, ",") {
+		if err := checkPackageArch(format, arch, known); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpandPackageArches returns the pack context for each architecture of the RPM or Deb
+// package. The binaries of the package architectures other than the host one are looked
+// for among the binaries installed by tt install into the environment binaries directory.
+// The context is returned as is if the package has one architecture.
+func ExpandPackageArches(packCtx *PackCtx, packType string,
+	cliOpts *config.CliOpts) ([]PackCtx, error) {
+	arches := getPackageArches(packCtx, packType)
+	if len(arches) < 2 {
+		return []PackCtx{*packCtx}, nil
+	}
+	if packCtx.UseDocker {
+		return nil, fmt.Errorf("several package architectures cannot be packed with" +
+			" --use-docker flag")
+	}
+	if packCtx.TargetArch != "" {
+		return nil, fmt.Errorf("--target-arch flag cannot be used while packing several" +
+			" architectures, the binaries architecture is the package one")
+	}
+	if packCtx.FileName != "" && !strings.Contains(packCtx.FileName, "{arch}") {
+		return nil, fmt.Errorf("filename %q must contain {arch} placeholder while packing"+
+			" several architectures", packCtx.FileName)
+	}
+
+	archCtxs := make([]PackCtx, 0, len(arches))
+	for i, arch := range arches {
+		var err error
+		if util.Find(arches[:i], arch) != -1 {
+			return nil, fmt.Errorf("%s package architecture %q is passed several times",
+				packType, arch)
+		}
+		archCtx := *packCtx
+		if packType == Deb {
+			err = checkPackageArch("Deb", arch, debArches)
+			archCtx.RpmDeb.DebArch = arch
+		} else {
+			err = checkPackageArch("RPM", arch, rpmArches)
+			archCtx.RpmDeb.RpmArch = arch
+		}
+		if err != nil {
+			return nil, err
+		}
+		if util.Find(archIndependent, arch) != -1 {
+			if !packCtx.WithoutBinaries {
+				return nil, fmt.Errorf("architecture-independent %s package %q cannot"+
+					" contain binaries, use --without-binaries flag", packType, arch)
+			}
+			archCtxs = append(archCtxs, archCtx)
+			continue
+		}
+		goArch, err := normalizeArch(arch)
+		if err != nil {
+			return nil, err
+		}
+		archCtx.TargetArch = goArch
+		archCtx.crossArch = goArch != runtime.GOARCH
+		if archCtx.crossArch && !packCtx.WithoutBinaries && packCtx.SourceDir == "" {
+			archCtx.archBinDir = cliOpts.Env.BinDir
+		}
+		archCtxs = append(archCtxs, archCtx)
+	}
+	return archCtxs, nil
+}
+
+// findArchBinary returns the path of the program binary built for the package
+// architecture. The binary is looked for among the <program>_<version> binaries installed
+// by tt install. The requested version is used if it is set, the latest installed version
+// built for the architecture otherwise.
+func findArchBinary(packCtx *PackCtx, programs []string, reqVersion string) (string, error) {
+	entries, err := os.ReadDir(packCtx.archBinDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read binaries directory: %s", err)
+	}
+	reqVersion = strings.TrimPrefix(reqVersion, "v")
+
+	foundPath := ""
+	var foundVersion version.Version
+	for _, entry := range entries {
+		for _, program := range programs {
+			versionStr, found := strings.CutPrefix(entry.Name(), program+version.FsSeparator)
+			if !found || entry.IsDir() {
+				continue
+			}
+			versionStr = strings.TrimPrefix(versionStr, "v")
+			if reqVersion != "" && versionStr != reqVersion {
+				continue
+			}
+			binPath := filepath.Join(packCtx.archBinDir, entry.Name())
+			arches, err := getBinaryArches(binPath)
+			if err != nil || util.Find(arches, packCtx.TargetArch) == -1 {
+				continue
+			}
+			if reqVersion != "" {
+				return binPath, nil
+			}
+			binVersion, err := version.Parse(versionStr)
+			if err != nil {
+				continue
+			}
+			if foundPath == "" || version.IsLess(foundVersion, binVersion) {
+				foundPath, foundVersion = binPath, binVersion
+			}
+		}
+	}
+	if foundPath != "" {
+		return foundPath, nil
+	}
+
+	name := programs[0]
+	if reqVersion != "" {
+		name += " " + reqVersion
+	}
+	return "", fmt.Errorf("cannot include %s into the %s package: %w: %s built for %s is not"+
+		" installed in %s, install it with tt install or use --without-binaries flag",
+		programs[0], packCtx.TargetArch, ErrBinaryNotFound, name, packCtx.TargetArch,
+		packCtx.archBinDir)
+}
+
+// getArchTarantool returns the path of the tarantool binary built for the package
+// architecture. The version set by --tarantool-version flag or pinned by the applications
+// is used if any.
+func getArchTarantool(packCtx *PackCtx) (string, error) {
+	tntVersion := packCtx.TarantoolVersion
+	if tntVersion == "" {
+		var err error
+		if tntVersion, err = getPinnedTarantoolVersion(packCtx); err != nil {
+			return "", err
+		}
+	}
+	return findArchBinary(packCtx, []string{search.ProgramCe, search.ProgramEe}, tntVersion)
+}
+
+// checkArchRocks checks the bundle applications do not have rocks if the package
+// architecture is not the host one. The rocks are built on the host, so their binary
+// modules cannot be run on the package architecture.
+func checkArchRocks(bundleEnvPath string, packCtx *PackCtx, cliOpts *config.CliOpts) error {
+	if !packCtx.crossArch {
+		return nil
+	}
+	appNames := make([]string, 0, len(packCtx.AppsInfo))
+	for appName := range packCtx.AppsInfo {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+	for _, appName := range appNames {
+		appDir := getDestAppDir(bundleEnvPath, appName, packCtx, cliOpts)
+		if util.IsDir(filepath.Join(appDir, ".rocks")) {
+			return fmt.Errorf("application %q rocks are built for %s and cannot be packed"+
+				" into the %s package, use --without-rocks flag", appName, runtime.GOARCH,
+				packCtx.TargetArch)
+		}
+	}
+	return nil
+}
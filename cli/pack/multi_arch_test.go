@@ -0,0 +1,178 @@
+package pack
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func TestExpandPackageArches(t *testing.T) {
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{BinDir: "/env/bin"}}
+
+	packCtx := PackCtx{RpmDeb: RpmDebCtx{DebArch: "arm64"}}
+	archCtxs, err := ExpandPackageArches(&packCtx, Deb, cliOpts)
+	require.NoError(t, err)
+	assert.Equal(t, []PackCtx{packCtx}, archCtxs)
+
+	packCtx = PackCtx{RpmDeb: RpmDebCtx{DebArch: "amd64,arm64", RpmArch: "x86_64,aarch64"}}
+	for _, packType := range []string{Deb, Rpm} {
+		archCtxs, err = ExpandPackageArches(&packCtx, packType, cliOpts)
+		require.NoError(t, err)
+		require.Len(t, archCtxs, 2)
+		for i, goArch := range []string{"amd64", "arm64"} {
+			assert.Equal(t, goArch, archCtxs[i].TargetArch)
+			expectedBinDir := ""
+			if goArch != runtime.GOARCH {
+				expectedBinDir = "/env/bin"
+			}
+			assert.Equal(t, expectedBinDir, archCtxs[i].archBinDir)
+			assert.Equal(t, goArch != runtime.GOARCH, archCtxs[i].crossArch)
+		}
+	}
+	assert.Equal(t, "aarch64", archCtxs[1].RpmDeb.RpmArch)
+
+	tests := []struct {
+		packCtx     PackCtx
+		expectedErr string
+	}{
+		{PackCtx{RpmDeb: RpmDebCtx{DebArch: "amd64,amd64"}},
+			`deb package architecture "amd64" is passed several times`},
+		{PackCtx{RpmDeb: RpmDebCtx{DebArch: "amd64,sparc"}},
+			`unsupported Deb package architecture "sparc"`},
+		{PackCtx{RpmDeb: RpmDebCtx{DebArch: "amd64,all"}},
+			`architecture-independent deb package "all" cannot contain binaries`},
+		{PackCtx{TargetArch: "arm64", RpmDeb: RpmDebCtx{DebArch: "amd64,arm64"}},
+			"--target-arch flag cannot be used while packing several architectures"},
+		{PackCtx{FileName: "{name}.deb", RpmDeb: RpmDebCtx{DebArch: "amd64,arm64"}},
+			`filename "{name}.deb" must contain {arch} placeholder`},
+		{PackCtx{UseDocker: true, RpmDeb: RpmDebCtx{DebArch: "amd64,arm64"}},
+			"several package architectures cannot be packed with --use-docker flag"},
+	}
+	for _, tt := range tests {
+		_, err = ExpandPackageArches(&tt.packCtx, Deb, cliOpts)
+		assert.ErrorContains(t, err, tt.expectedErr)
+	}
+
+	packCtx = PackCtx{WithoutBinaries: true, RpmDeb: RpmDebCtx{DebArch: "arm64,all"}}
+	archCtxs, err = ExpandPackageArches(&packCtx, Deb, cliOpts)
+	require.NoError(t, err)
+	assert.Equal(t, "all", archCtxs[1].RpmDeb.DebArch)
+	assert.Empty(t, archCtxs[0].archBinDir)
+}
+
+func TestExpandPackageArchesOtherTypes(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "instances.enabled", "app")
+	require.NoError(t, os.MkdirAll(appDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), nil, 0644))
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = envDir
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{
+		InstancesEnabled: filepath.Join(envDir, "instances.enabled"),
+		BinDir:           filepath.Join(envDir, "bin")}}
+
+	// The architectures list of one type does not break packing of the other types,
+	// e.g. tt pack tgz,rpm,deb --deb-arch amd64,arm64 --rpm-arch x86_64,aarch64.
+	packCtx := PackCtx{WithoutBinaries: true,
+		RpmDeb: RpmDebCtx{DebArch: "amd64,arm64", RpmArch: "x86_64,aarch64"}}
+	for _, packType := range []string{Tgz, Rpm, Deb} {
+		archCtxs, err := ExpandPackageArches(&packCtx, packType, cliOpts)
+		require.NoError(t, err)
+		if packType == Tgz {
+			require.Len(t, archCtxs, 1)
+		} else {
+			require.Len(t, archCtxs, 2)
+		}
+		for i := range archCtxs {
+			require.NoError(t, FillCtx(&cmdCtx, &archCtxs[i], cliOpts, []string{packType}))
+		}
+	}
+
+	packCtx = PackCtx{RpmDeb: RpmDebCtx{DebArch: "amd64,sparc"}}
+	archCtxs, err := ExpandPackageArches(&packCtx, Tgz, cliOpts)
+	require.NoError(t, err)
+	assert.ErrorContains(t, FillCtx(&cmdCtx, &archCtxs[0], cliOpts, []string{Tgz}),
+		`unsupported Deb package architecture "sparc"`)
+}
+
+// writeElfHeader writes the ELF header of the binary built for the machine.
+func writeElfHeader(t *testing.T, path string, machine elf.Machine) {
+	header := elf.Header64{
+		Type:    uint16(elf.ET_EXEC),
+		Machine: uint16(machine),
+		Version: uint32(elf.EV_CURRENT),
+		Ehsize:  uint16(binary.Size(elf.Header64{})),
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	buf := bytes.Buffer{}
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, header))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0755))
+}
+
+func Test_copyBinariesArchBinDir(t *testing.T) {
+	binDir := t.TempDir()
+	writeElfHeader(t, filepath.Join(binDir, "tarantool_2.11.1"), elf.EM_AARCH64)
+	writeElfHeader(t, filepath.Join(binDir, "tarantool_3.0.0"), elf.EM_AARCH64)
+	writeElfHeader(t, filepath.Join(binDir, "tarantool_3.1.0"), elf.EM_X86_64)
+	writeElfHeader(t, filepath.Join(binDir, "tt_2.1.0"), elf.EM_X86_64)
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.TarantoolCli.Executable = "/usr/bin/tarantool"
+	newOpts := &config.CliOpts{Env: &config.TtEnvOpts{BinDir: "bin"}}
+	packCtx := PackCtx{TargetArch: "arm64", archBinDir: binDir, crossArch: true}
+
+	bundleDir := t.TempDir()
+	err := copyBinaries(bundleDir, &packCtx, &cmdCtx, newOpts)
+	assert.ErrorIs(t, err, ErrBinaryNotFound)
+	assert.ErrorContains(t, err, "cannot include tt into the arm64 package: binary is not"+
+		" found: tt built for arm64 is not installed in "+binDir)
+
+	// The latest installed version built for the package architecture is bundled.
+	writeElfHeader(t, filepath.Join(binDir, "tt_v2.0.0"), elf.EM_AARCH64)
+	require.NoError(t, copyBinaries(bundleDir, &packCtx, &cmdCtx, newOpts))
+	for name, src := range map[string]string{"tarantool": "tarantool_3.0.0", "tt": "tt_v2.0.0"} {
+		assert.Equal(t, filepath.Join(bundleDir, "bin", name), packCtx.bundledBinaries[name])
+		expected, err := os.ReadFile(filepath.Join(binDir, src))
+		require.NoError(t, err)
+		actual, err := os.ReadFile(filepath.Join(bundleDir, "bin", name))
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	// The requested version must be installed for the package architecture.
+	packCtx.TarantoolVersion = "3.1.0"
+	err = copyBinaries(bundleDir, &packCtx, &cmdCtx, newOpts)
+	assert.ErrorIs(t, err, ErrBinaryNotFound)
+	assert.ErrorContains(t, err, "tarantool 3.1.0 built for arm64 is not installed")
+	packCtx.TarantoolVersion = "v2.11.1"
+	require.NoError(t, copyBinaries(bundleDir, &packCtx, &cmdCtx, newOpts))
+}
+
+func Test_checkArchRocks(t *testing.T) {
+	bundleDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "app", ".rocks"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "other"), 0755))
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	packCtx := PackCtx{TargetArch: "arm64",
+		AppsInfo: map[string][]running.InstanceCtx{"app": nil, "other": nil}}
+
+	assert.NoError(t, checkArchRocks(bundleDir, &packCtx, cliOpts))
+	packCtx.crossArch = true
+	assert.EqualError(t, checkArchRocks(bundleDir, &packCtx, cliOpts),
+		`application "app" rocks are built for `+runtime.GOARCH+" and cannot be packed into"+
+			" the arm64 package, use --without-rocks flag")
+	delete(packCtx.AppsInfo, "app")
+	assert.NoError(t, checkArchRocks(bundleDir, &packCtx, cliOpts))
+}
@@ -0,0 +1,23 @@
+package pack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+// isNoSpaceError returns true if the error is caused by the full file system. The error
+// text is checked too, because the wrapped errors are often formatted without wrapping.
+func isNoSpaceError(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || strings.Contains(err.Error(), syscall.ENOSPC.Error())
+}
+
+// wrapNoSpaceError reports the full file system error as ErrNoSpace naming the directory
+// being written. Other errors are returned as is.
+func wrapNoSpaceError(err error, dir string) error {
+	if err == nil || errors.Is(err, ErrNoSpace) || !isNoSpaceError(err) {
+		return err
+	}
+	return fmt.Errorf("%w in %s: %s", ErrNoSpace, dir, err)
+}
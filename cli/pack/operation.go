@@ -0,0 +1,119 @@
+package pack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/apex/log"
+)
+
+// packOperation tracks the running pack operation: the last processed file to report and
+// the outputs to remove if the operation is canceled. Nil operation is not tracked.
+type packOperation struct {
+	ctx   context.Context
+	mutex sync.Mutex
+	// lastFile is a path of the last file being processed.
+	lastFile string
+	// outputs are the paths of the temporary directories and files being created.
+	outputs []string
+}
+
+// context returns the operation context. Background context is returned for the nil
+// operation.
+func (operation *packOperation) context() context.Context {
+	if operation == nil {
+		return context.Background()
+	}
+	return operation.ctx
+}
+
+// processFile records the file being processed. Returns an error if the operation is
+// canceled.
+func (operation *packOperation) processFile(path string) error {
+	if operation == nil {
+		return nil
+	}
+	operation.mutex.Lock()
+	operation.lastFile = path
+	operation.mutex.Unlock()
+	return operation.ctx.Err()
+}
+
+// addOutput records the path created by the operation.
+func (operation *packOperation) addOutput(path string) {
+	if operation == nil {
+		return
+	}
+	operation.mutex.Lock()
+	operation.outputs = append(operation.outputs, path)
+	operation.mutex.Unlock()
+}
+
+// finishOutput forgets the completed output, so it is not removed on the cancellation.
+func (operation *packOperation) finishOutput(path string) {
+	if operation == nil {
+		return
+	}
+	operation.mutex.Lock()
+	defer operation.mutex.Unlock()
+	for i, output := range operation.outputs {
+		if output == path {
+			operation.outputs = append(operation.outputs[:i], operation.outputs[i+1:]...)
+			return
+		}
+	}
+}
+
+// cleanup removes the recorded outputs not completed by the operation and returns the last
+// processed file.
+func (operation *packOperation) cleanup() string {
+	operation.mutex.Lock()
+	defer operation.mutex.Unlock()
+	for _, output := range operation.outputs {
+		if err := os.RemoveAll(output); err != nil {
+			log.Warnf("Failed to remove %s: %s", output, err)
+		}
+	}
+	return operation.lastFile
+}
+
+// RunPackOperation runs the pack operation for the pack contexts. The operation is
+// canceled on SIGINT or SIGTERM signal or if it is not completed in timeout. The partial
+// output of the canceled operation is removed and ErrInterrupted or ErrTimeout naming
+// the last processed file is returned. The operation stages check the context, so
+// the outputs are removed after the operation returns. The operation is not limited in time
+// if the timeout is zero.
+func RunPackOperation(timeout time.Duration, packCtxs []*PackCtx, run func() error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	operation := &packOperation{ctx: ctx}
+	for _, packCtx := range packCtxs {
+		packCtx.operation = operation
+	}
+
+	err := run()
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	lastFile := operation.cleanup()
+
+	err = ErrInterrupted
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+	if lastFile == "" {
+		return err
+	}
+	return fmt.Errorf("%w, last processed file: %s", err, lastFile)
+}
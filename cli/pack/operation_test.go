@@ -0,0 +1,146 @@
+package pack
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPackOperation(t *testing.T) {
+	packCtx := PackCtx{}
+	require.NoError(t, RunPackOperation(0, []*PackCtx{&packCtx}, func() error {
+		_, hasDeadline := packCtx.operation.context().Deadline()
+		assert.False(t, hasDeadline)
+		return nil
+	}))
+
+	require.NoError(t, RunPackOperation(time.Minute, []*PackCtx{&packCtx}, func() error {
+		return packCtx.operation.processFile("app/init.lua")
+	}))
+	assert.Equal(t, "app/init.lua", packCtx.operation.lastFile)
+}
+
+func TestRunPackOperationTimeout(t *testing.T) {
+	outputDir := t.TempDir()
+	packagePath := filepath.Join(outputDir, "bundle.tar.gz")
+	tmpDir := filepath.Join(outputDir, "tmp")
+	require.NoError(t, os.Mkdir(tmpDir, 0755))
+
+	finishedPath := filepath.Join(outputDir, "bundle.deb")
+
+	packCtx := PackCtx{}
+	err := RunPackOperation(100*time.Millisecond, []*PackCtx{&packCtx}, func() error {
+		packCtx.operation.addOutput(finishedPath)
+		assert.NoError(t, os.WriteFile(finishedPath, []byte("package"), 0644))
+		packCtx.operation.finishOutput(finishedPath)
+
+		packCtx.operation.addOutput(tmpDir)
+		packCtx.operation.addOutput(packagePath)
+		assert.NoError(t, os.WriteFile(packagePath, []byte("partial"), 0644))
+		assert.NoError(t, packCtx.operation.processFile("/mnt/nfs/app/data.bin"))
+		ctx := packCtx.operation.context()
+		<-ctx.Done()
+		// The outputs are removed after the operation returns.
+		assert.FileExists(t, packagePath)
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.EqualError(t, err,
+		"pack operation timed out after 100ms, last processed file: /mnt/nfs/app/data.bin")
+	assert.NoDirExists(t, tmpDir)
+	assert.NoFileExists(t, packagePath)
+	assert.FileExists(t, finishedPath)
+}
+
+func TestRunPackOperationCanceledStage(t *testing.T) {
+	packCtx := PackCtx{}
+	err := RunPackOperation(50*time.Millisecond, []*PackCtx{&packCtx}, func() error {
+		<-packCtx.operation.context().Done()
+		return packCtx.operation.processFile("bundle/app/init.lua")
+	})
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	packCtx.operation = nil
+	assert.NoError(t, packCtx.operation.processFile("init.lua"))
+}
+
+func TestRunPackOperationInterrupted(t *testing.T) {
+	tmpPath := filepath.Join(t.TempDir(), ".bundle.tar.gz.tmp")
+	packCtx := PackCtx{}
+	err := RunPackOperation(0, []*PackCtx{&packCtx}, func() error {
+		packCtx.operation.addOutput(tmpPath)
+		assert.NoError(t, os.WriteFile(tmpPath, []byte("partial"), 0644))
+		assert.NoError(t, packCtx.operation.processFile("app/init.lua"))
+		assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGINT))
+		ctx := packCtx.operation.context()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.EqualError(t, err,
+		"pack operation is interrupted, last processed file: app/init.lua")
+	assert.NoFileExists(t, tmpPath)
+}
+
+func Test_writePackageFile(t *testing.T) {
+	packagePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	tmpPath := filepath.Join(filepath.Dir(packagePath), ".bundle.tar.gz.tmp")
+	packCtx := PackCtx{}
+
+	err := writePackageFile(&packCtx, packagePath, func(path string) error {
+		assert.Equal(t, tmpPath, path)
+		assert.NoError(t, os.WriteFile(path, []byte("partial"), 0644))
+		return errors.New("write failed")
+	})
+	assert.EqualError(t, err, "write failed")
+	assert.NoFileExists(t, tmpPath)
+	assert.NoFileExists(t, packagePath)
+
+	require.NoError(t, writePackageFile(&packCtx, packagePath, func(path string) error {
+		return os.WriteFile(path, []byte("package"), 0644)
+	}))
+	assert.NoFileExists(t, tmpPath)
+	content, err := os.ReadFile(packagePath)
+	require.NoError(t, err)
+	assert.Equal(t, "package", string(content))
+}
+
+func Test_writePackageFileNoSpace(t *testing.T) {
+	packageDir := t.TempDir()
+	packagePath := filepath.Join(packageDir, "bundle.tar.gz")
+	packCtx := PackCtx{}
+
+	err := writePackageFile(&packCtx, packagePath, func(path string) error {
+		assert.NoError(t, os.WriteFile(path, []byte("partial"), 0644))
+		return fmt.Errorf("failed to write archive: %s",
+			&os.PathError{Op: "write", Path: path, Err: syscall.ENOSPC})
+	})
+	assert.ErrorIs(t, err, ErrNoSpace)
+	assert.ErrorContains(t, err, "not enough disk space in "+packageDir)
+	assert.NoFileExists(t, filepath.Join(packageDir, ".bundle.tar.gz.tmp"))
+	assert.NoFileExists(t, packagePath)
+
+	// The error is wrapped once.
+	assert.Equal(t, err, wrapNoSpaceError(err, os.TempDir()))
+	otherErr := errors.New("write failed")
+	assert.Equal(t, otherErr, wrapNoSpaceError(otherErr, packageDir))
+}
+
+func Test_writePackageFileArtifactMode(t *testing.T) {
+	packagePath := filepath.Join(t.TempDir(), "bundle.deb")
+	artifactMode := os.FileMode(0664)
+	packCtx := PackCtx{artifactMode: &artifactMode}
+	require.NoError(t, writePackageFile(&packCtx, packagePath, func(path string) error {
+		return os.WriteFile(path, []byte("package"), 0600)
+	}))
+	info, err := os.Stat(packagePath)
+	require.NoError(t, err)
+	assert.Equal(t, artifactMode, info.Mode().Perm())
+}
@@ -5,47 +5,135 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/configure"
 	"github.com/tarantool/tt/cli/running"
 	"github.com/tarantool/tt/cli/util"
 )
 
+// PackageType is a type of the result package.
 type PackageType string
 
 const (
-	Tgz    = "tgz"
-	Rpm    = "rpm"
-	Deb    = "deb"
-	Docker = "docker"
+	Tgz      = "tgz"
+	Zip      = "zip"
+	Rpm      = "rpm"
+	Deb      = "deb"
+	Docker   = "docker"
+	AppImage = "appimage"
 )
 
+// String returns the package type name.
+func (packageType PackageType) String() string {
+	return string(packageType)
+}
+
+// SupportedTypes returns package types supported by the packer.
+func SupportedTypes() []PackageType {
+	return []PackageType{Tgz, Zip, Deb, Rpm, Docker, AppImage}
+}
+
+// WarnIgnored reports the flag or option ignored for the package. The message is logged
+// at debug level in quiet mode.
+func WarnIgnored(packCtx *PackCtx, format string, args ...interface{}) {
+	if packCtx.Quiet {
+		log.Debugf(format, args...)
+	} else {
+		log.Warnf(format, args...)
+	}
+}
+
+// ConfigFreeOpts detaches the command context from the loaded tt environment
+// configuration and returns the default options to pack with. The current working
+// directory is used as the environment directory.
+func ConfigFreeOpts(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx) (*config.CliOpts, error) {
+	if packCtx.SourceDir == "" {
+		return nil, fmt.Errorf("--config-free flag requires --source-dir flag: " +
+			"applications cannot be discovered without tt environment configuration")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get path of current dir: %s", err)
+	}
+	cmdCtx.Cli.ConfigPath = ""
+	cmdCtx.Cli.ConfigDir = cwd
+	return configure.GetDefaultCliOpts(), nil
+}
+
+// DiscoverApps returns the names of the environment applications available for packing.
+func DiscoverApps(cmdCtx *cmdcontext.CmdCtx, cliOpts *config.CliOpts) ([]string, error) {
+	return util.CollectAppList(cmdCtx.Cli.ConfigDir, cliOpts.Env.InstancesEnabled, true)
+}
+
 // initAppsInfo collects environment applications info, set related pack context fields.
 func initAppsInfo(cliOpts *config.CliOpts, cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx) error {
 	// Collect applications info.
 	var err error
+	appsDir := cliOpts.Env.InstancesEnabled
+	if appsDir == "." {
+		appsDir = cmdCtx.Cli.ConfigDir
+	}
 	appList := []string{}
 	if packCtx.AppList == nil {
-		appList, err = util.CollectAppList(cmdCtx.Cli.ConfigDir, cliOpts.Env.InstancesEnabled,
-			true)
-		if err != nil {
+		if appList, err = DiscoverApps(cmdCtx, cliOpts); err != nil {
 			return err
 		}
+		if len(appList) > 1 {
+			if cwdApp := findCwdApp(appList, appsDir); cwdApp != "" {
+				log.Infof("Current directory is an application directory, packing %q only.",
+					cwdApp)
+				appList = []string{cwdApp}
+			}
+		}
 	} else {
+		// missingApps are the applications from the application list file which are
+		// not found. All of them are reported at once.
+		missingApps := []string{}
+		// unmatchedApps are the application list entries which are not applications.
+		unmatchedApps := []string{}
 		for _, appName := range packCtx.AppList {
-			if util.IsApp(filepath.Join(cliOpts.Env.InstancesEnabled, appName)) {
+			appPath, err := resolveAppEntry(appsDir, appName)
+			if err != nil {
+				return err
+			}
+			if util.IsApp(appPath) {
 				appList = append(appList, appName)
+			} else if packCtx.appListFileApps[appName] {
+				missingApps = append(missingApps, appName)
 			} else {
 				log.Warnf("Skip packing of '%s': specified name is not an application.", appName)
+				unmatchedApps = append(unmatchedApps, appName)
 			}
 		}
+		if len(missingApps) > 0 {
+			return fmt.Errorf("applications listed in %q are not found: %s",
+				packCtx.AppListFile, strings.Join(missingApps, ", "))
+		}
+		if len(appList) == 0 && len(unmatchedApps) > 0 && !packCtx.AllowEmpty {
+			return fmt.Errorf("%w: application list entries matched nothing: %s, "+
+				"use --allow-empty to pack the environment without applications", ErrNoApps,
+				strings.Join(unmatchedApps, ", "))
+		}
 	}
 
 	if len(appList) == 0 {
-		err = fmt.Errorf("there are no apps found in instance_enabled directory")
-		return err
+		// The package is named after the application in these modes.
+		if !packCtx.AllowEmpty || packCtx.CartridgeCompat || cliOpts.Env.InstancesEnabled == "." {
+			return fmt.Errorf("%w in instance_enabled directory", ErrNoApps)
+		}
+		log.Warnf("There are no applications to pack, the package contains the environment only.")
+		packCtx.AppList = appList
+		packCtx.AppsInfo = map[string][]running.InstanceCtx{}
+		return nil
+	}
+	if !packCtx.AllowDuplicateApps {
+		if err = checkDuplicateApps(appsDir, appList); err != nil {
+			return err
+		}
 	}
 	packCtx.AppList = appList
 	packCtx.AppsInfo, err = running.CollectInstancesForApps(packCtx.AppList, cliOpts,
@@ -56,6 +144,131 @@ func initAppsInfo(cliOpts *config.CliOpts, cmdCtx *cmdcontext.CmdCtx, packCtx *P
 	return nil
 }
 
+// resolveAppEntry returns the real path of the application entry in the instances
+// directory. The entry may be a symlink to the application placed elsewhere, the name
+// of the entry is kept as the application name. A symlink loop is reported as an error.
+func resolveAppEntry(appsDir, appName string) (string, error) {
+	appPath := filepath.Join(appsDir, appName)
+	resolvedPath, err := filepath.EvalSymlinks(appPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return appPath, nil
+		}
+		return "", fmt.Errorf("cannot resolve application %q path %q: %s", appName, appPath,
+			err)
+	}
+	return resolvedPath, nil
+}
+
+// checkDuplicateApps checks the applications from different sources do not resolve to
+// the same application name, which is the entry name without .lua suffix. Otherwise one
+// application silently overwrites another in the bundle.
+func checkDuplicateApps(appsDir string, appList []string) error {
+	appNames := []string{}
+	appSources := map[string][]string{}
+	for _, appEntry := range appList {
+		appPath, err := resolveAppEntry(appsDir, appEntry)
+		if err != nil {
+			return err
+		}
+		appName := strings.TrimSuffix(appEntry, ".lua")
+		if _, found := appSources[appName]; !found {
+			appNames = append(appNames, appName)
+		}
+		if util.Find(appSources[appName], appPath) == -1 {
+			appSources[appName] = append(appSources[appName], appPath)
+		}
+	}
+	for _, appName := range appNames {
+		if len(appSources[appName]) > 1 {
+			return fmt.Errorf("duplicate application name %q is resolved from: %s, use"+
+				" --allow-duplicate-apps to pack one of them", appName,
+				strings.Join(appSources[appName], ", "))
+		}
+	}
+	return nil
+}
+
+// findSourceDirApps returns the names of the applications in the prebuilt bundle directory.
+// The directory itself, its entries and the entries of instances.enabled directory
+// are checked.
+func findSourceDirApps(sourceDir string) ([]string, error) {
+	if util.IsApp(sourceDir) {
+		return []string{filepath.Base(sourceDir)}, nil
+	}
+	appList := []string{}
+	for _, appsDir := range []string{sourceDir,
+		filepath.Join(sourceDir, configure.InstancesEnabledDirName)} {
+		entries, err := os.ReadDir(appsDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if util.IsApp(filepath.Join(appsDir, entry.Name())) {
+				appList = append(appList, entry.Name())
+			}
+		}
+	}
+	return appList, nil
+}
+
+// initSourceDir checks the prebuilt bundle directory and sets related pack context fields.
+func initSourceDir(packCtx *PackCtx) error {
+	if packCtx.IntegrityPrivateKey != "" {
+		return errors.New("cannot pack with integrity checks from the source directory")
+	}
+
+	sourceDir, err := filepath.Abs(packCtx.SourceDir)
+	if err != nil {
+		return fmt.Errorf("cannot get absolute path of source directory %q: %s",
+			packCtx.SourceDir, err)
+	}
+	if stat, err := os.Stat(sourceDir); err != nil {
+		return fmt.Errorf("cannot access source directory %q: %s", sourceDir, err)
+	} else if !stat.IsDir() {
+		return fmt.Errorf("source directory %q is not a directory", sourceDir)
+	}
+
+	appList, err := findSourceDirApps(sourceDir)
+	if err != nil {
+		return fmt.Errorf("failed to find applications in %q: %s", sourceDir, err)
+	}
+	if len(appList) == 0 {
+		return fmt.Errorf("%w in source directory %q", ErrNoApps, sourceDir)
+	}
+	packCtx.SourceDir = sourceDir
+	packCtx.AppList = appList
+	if packCtx.Name == "" {
+		packCtx.Name = filepath.Base(sourceDir)
+	}
+	return nil
+}
+
+// findCwdApp returns the name of the application from the list, which directory is the
+// current working directory. Empty string is returned if there is no such application.
+func findCwdApp(appList []string, appsDir string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if cwd, err = filepath.EvalSymlinks(cwd); err != nil || !util.IsApp(cwd) {
+		return ""
+	}
+	if appsDir, err = filepath.Abs(appsDir); err != nil {
+		return ""
+	}
+	for _, appName := range appList {
+		appPath, err := filepath.EvalSymlinks(filepath.Join(appsDir, appName))
+		if err == nil && appPath == cwd {
+			return appName
+		}
+	}
+	return ""
+}
+
 // getPackageName return result environment name for the package.
 func getPackageName(cmdCtx cmdcontext.CmdCtx) (string, error) {
 	if len(cmdCtx.Cli.ConfigDir) == 0 {
@@ -90,6 +303,82 @@ func setBundleName(packCtx *PackCtx, cliOpts *config.CliOpts) {
 	packCtx.Name = filepath.Base(filepath.Dir(packCtx.configFilePath))
 }
 
+// prepareOutputDir creates the output directory if it does not exist and checks
+// the result package can be written into it.
+func prepareOutputDir(packCtx *PackCtx) error {
+	outputDir, err := filepath.Abs(packCtx.OutputDir)
+	if err != nil {
+		return fmt.Errorf("cannot get absolute path of output directory %q: %s",
+			packCtx.OutputDir, err)
+	}
+	if err = os.MkdirAll(outputDir, dirPermissions); err != nil {
+		return fmt.Errorf("cannot create output directory %q: %s", outputDir, err)
+	}
+	if err = checkDirWritable(outputDir); err != nil {
+		return fmt.Errorf("output directory %q is not writable: %s", outputDir, err)
+	}
+	packCtx.OutputDir = outputDir
+	return nil
+}
+
+// applyConfigOpts sets pack context fields not set by the command line flags
+// from the pack section of tt configuration. Rpm and deb specific options are applied
+// for these package types only.
+func applyConfigOpts(packCtx *PackCtx, packOpts *config.PackOpts, packageType string) {
+	if packOpts == nil {
+		return
+	}
+	type stringOpt struct {
+		value    *string
+		cfgValue string
+	}
+	opts := []stringOpt{
+		{&packCtx.Name, packOpts.Name},
+		{&packCtx.Version, packOpts.Version},
+		{&packCtx.OutputDir, packOpts.OutputDir},
+	}
+	if packageType == Rpm || packageType == Deb {
+		opts = append(opts, []stringOpt{
+			{&packCtx.RpmDeb.DepsFile, packOpts.DepsFile},
+			{&packCtx.RpmDeb.PreInst, packOpts.PreInst},
+			{&packCtx.RpmDeb.PostInst, packOpts.PostInst},
+			{&packCtx.RpmDeb.PreRm, packOpts.PreRm},
+			{&packCtx.RpmDeb.PostRm, packOpts.PostRm},
+			{&packCtx.RpmDeb.InstallPrefix, packOpts.InstallPrefix},
+		}...)
+		if len(packCtx.RpmDeb.Deps) == 0 {
+			packCtx.RpmDeb.Deps = packOpts.Deps
+		}
+	}
+	for _, opt := range opts {
+		if *opt.value == "" {
+			*opt.value = opt.cfgValue
+		}
+	}
+	if len(packCtx.Exclude) == 0 {
+		packCtx.Exclude = packOpts.Exclude
+	}
+}
+
+// initGitVersion sets the package version from git if it is requested.
+func initGitVersion(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx) error {
+	if !packCtx.VersionFromGit {
+		return nil
+	}
+	version, err := getVersionFromGit(getGitVersionDir(cmdCtx, packCtx), packCtx.AllowDirty)
+	if err != nil {
+		return err
+	}
+	// The version is checked before the bundle is collected.
+	if packCtx.Type == Rpm {
+		if err = checkRpmVersion(version); err != nil {
+			return fmt.Errorf("invalid RPM version got from git: %s", err)
+		}
+	}
+	packCtx.Version = version
+	return nil
+}
+
 // FillCtx fills pack context.
 func FillCtx(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, cliOpts *config.CliOpts,
 	args []string) error {
@@ -98,18 +387,265 @@ func FillCtx(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, cliOpts *config.CliOpt
 	}
 
 	packCtx.RpmDeb.pkgFilesInfo = make(map[string]packFileInfo)
+	packCtx.progress = newProgressTracker(packCtx.ProgressReporter)
+
+	applyConfigOpts(packCtx, cliOpts.Pack, args[0])
+
+	if packCtx.OutputDir != "" {
+		if err := prepareOutputDir(packCtx); err != nil {
+			return err
+		}
+	}
+	if err := initTmpDir(packCtx); err != nil {
+		return err
+	}
+
+	if packCtx.CacheDir != "" {
+		if err := initCacheDir(packCtx); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if packCtx.BinariesLock != "" {
+		if packCtx.binariesLock, err = loadBinariesLock(packCtx.BinariesLock); err != nil {
+			return err
+		}
+	}
+	if packCtx.sourceDateEpoch, err = getSourceDateEpoch(); err != nil {
+		return err
+	}
+
+	if packCtx.excludePatterns, err = parseExcludePatterns(packCtx.Exclude); err != nil {
+		return err
+	}
+	if packCtx.includePatterns, err = parseIncludePatterns(packCtx.Include); err != nil {
+		return err
+	}
+	if packCtx.Archive.storePatterns, err = parsePathGlobs("store",
+		packCtx.Archive.StoreGlobs); err != nil {
+		return err
+	}
+	if packCtx.labels, err = parseLabels(packCtx.Labels); err != nil {
+		return err
+	}
+	if packCtx.extraFiles, err = parseExtraFiles(packCtx.ExtraFiles); err != nil {
+		return err
+	}
+	if packCtx.ArtifactMode != "" {
+		if packCtx.artifactMode, err = parseArtifactMode(packCtx.ArtifactMode); err != nil {
+			return err
+		}
+	}
+	if packCtx.WarnSize != "" {
+		if packCtx.warnSize, err = parseSize(packCtx.WarnSize); err != nil {
+			return err
+		}
+	}
+	if packCtx.MaxSize != "" {
+		if packCtx.maxSize, err = parseSize(packCtx.MaxSize); err != nil {
+			return err
+		}
+	}
+	if packCtx.Archive.DedupMinSize != "" {
+		packCtx.Archive.dedupMinSize, err = parseSize(packCtx.Archive.DedupMinSize)
+		if err != nil {
+			return err
+		}
+	}
+	if packCtx.Archive.SplitSize != "" {
+		if packCtx.Archive.splitSize, err = parseSize(packCtx.Archive.SplitSize); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.TargetArch, err = normalizeArch(packCtx.TargetArch); err != nil {
+		return err
+	}
+
+	if err = checkFileNameTemplate(packCtx.FileName); err != nil {
+		return err
+	}
+	if packCtx.Archive.BundleRoot != "" {
+		if err = checkBundleRoot(packCtx.Archive.BundleRoot); err != nil {
+			return err
+		}
+	}
+	if packCtx.RpmDeb.DebArch != "" {
+		if err = checkPackageArches("Deb", packCtx.RpmDeb.DebArch, debArches); err != nil {
+			return err
+		}
+	}
+	if packCtx.RpmDeb.RpmArch != "" {
+		if err = checkPackageArches("RPM", packCtx.RpmDeb.RpmArch, rpmArches); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.Destination != "" {
+		if err := initDestination(packCtx); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.PostPackHook != "" {
+		if err := checkPostPackHook(packCtx); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.RpmDeb.SystemdUnitTemplateFile != "" {
+		if err := loadSystemdUnitTemplate(packCtx); err != nil {
+			return err
+		}
+	}
 
 	if (packCtx.IntegrityPrivateKey != "") && packCtx.CartridgeCompat {
 		return errors.New("cannot pack with integrity checks in cartridge-compat mode")
 	}
 
+	if packCtx.TarantoolVersion != "" && !packCtx.UseDocker {
+		binDir := ""
+		if cliOpts.Env != nil {
+			binDir = cliOpts.Env.BinDir
+		}
+		if err := selectTarantoolVersion(cmdCtx, packCtx, binDir); err != nil {
+			return err
+		}
+	}
+
+	if err := initExplicitBinaries(cmdCtx, packCtx); err != nil {
+		return err
+	}
+
 	packCtx.TarantoolIsSystem = cmdCtx.Cli.IsSystem
 	packCtx.TarantoolExecutable = cmdCtx.Cli.TarantoolCli.Executable
 	packCtx.configFilePath = cmdCtx.Cli.ConfigPath
 	packCtx.Type = args[0]
 
+	if packCtx.RpmDeb.SignKey != "" && (packCtx.Type == Rpm || packCtx.Type == Deb) {
+		if err := checkSignKey(packCtx.RpmDeb.SignKey); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.Type == Rpm {
+		if err := checkRpmScriptletInterpreter(
+			packCtx.RpmDeb.RpmScriptletInterpreter); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.Type == Rpm || packCtx.Type == Deb {
+		if _, _, err := parsePackageRelations(packCtx); err != nil {
+			return err
+		}
+		if packCtx.RpmDeb.fileModeRules, err = parseFileModeRules(
+			packCtx.RpmDeb.FileModes); err != nil {
+			return err
+		}
+		if packCtx.RpmDeb.rpmConfigFileRes, err = parseConfigFileGlobs(
+			packCtx.RpmDeb.RpmConfigFiles); err != nil {
+			return err
+		}
+		if packCtx.RpmDeb.debConffileRes, err = parseConfigFileGlobs(
+			packCtx.RpmDeb.DebConffiles); err != nil {
+			return err
+		}
+		if packCtx.RpmDeb.Changelog != "" {
+			if err := loadChangelog(packCtx); err != nil {
+				return err
+			}
+		}
+	}
+	if packCtx.Type == Deb && packCtx.RpmDeb.Maintainer != "" {
+		if err := checkDebMaintainer(packCtx.RpmDeb.Maintainer); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.Type == Tgz && packCtx.Archive.BaseTgz != "" {
+		if err := initBaseTgz(packCtx); err != nil {
+			return err
+		}
+	}
+	if packCtx.Type == Tgz && packCtx.Archive.DeltaAgainst != "" {
+		if err := initDeltaAgainst(packCtx); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.ConfigOverlay != "" {
+		if err := initConfigOverlay(packCtx); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.SourceDir != "" {
+		if err := initSourceDir(packCtx); err != nil {
+			return err
+		}
+		if err := initGitVersion(cmdCtx, packCtx); err != nil {
+			return err
+		}
+		if packCtx.Type == Rpm || packCtx.Type == Deb {
+			return loadMaintainerScripts(packCtx, cliOpts)
+		}
+		return nil
+	}
+
+	if packCtx.AppListFile != "" {
+		if err := mergeAppListFile(packCtx); err != nil {
+			return err
+		}
+	}
 	if err := initAppsInfo(cliOpts, cmdCtx, packCtx); err != nil {
-		return fmt.Errorf("error collect applications info: %s", err)
+		return fmt.Errorf("error collect applications info: %w", err)
+	}
+	if packCtx.NoRebuild && !packCtx.WithoutRocks {
+		warnNotBuiltApps(packCtx)
+	}
+	if err := parseAppVersions(packCtx); err != nil {
+		return err
+	}
+
+	if packCtx.Since != "" {
+		if err := initSinceFiles(packCtx); err != nil {
+			return err
+		}
+	}
+	if packCtx.FollowConfig {
+		if err := initConfiguredFiles(packCtx); err != nil {
+			return err
+		}
+	}
+	if len(packCtx.excludePatterns) > 0 {
+		if err := initBundleAppDirs(packCtx, cliOpts); err != nil {
+			return err
+		}
+	}
+
+	// The version or the binary set by the flag overrides the applications pins. The pin
+	// selects the bundled tarantool, so it is ignored if the tarantool is not bundled.
+	if packCtx.TarantoolVersion == "" && packCtx.TarantoolBinary == "" && !packCtx.UseDocker &&
+		isTarantoolBundled(packCtx) {
+		binDir := ""
+		if cliOpts.Env != nil {
+			binDir = cliOpts.Env.BinDir
+		}
+		if err := selectPinnedTarantoolVersion(cmdCtx, packCtx, binDir); err != nil {
+			return err
+		}
+	}
+
+	if packCtx.Type == Rpm || packCtx.Type == Deb {
+		if err := checkSystemdAppNames(packCtx); err != nil {
+			return err
+		}
+	}
+
+	if err := initGitVersion(cmdCtx, packCtx); err != nil {
+		return err
 	}
 
 	setBundleName(packCtx, cliOpts)
@@ -118,5 +654,11 @@ func FillCtx(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, cliOpts *config.CliOpt
 		return fmt.Errorf("cannot pack multiple applications in cartridge compat mode")
 	}
 
+	if packCtx.Type == Rpm || packCtx.Type == Deb {
+		if err := loadMaintainerScripts(packCtx, cliOpts); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
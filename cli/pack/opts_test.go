@@ -0,0 +1,335 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+)
+
+func Test_findCwdApp(t *testing.T) {
+	appsDir, err := filepath.Abs("testdata/env1/instances.enabled")
+	require.NoError(t, err)
+	appList := []string{"multi", "script_app", "single"}
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		name        string
+		cwd         string
+		expectedApp string
+	}{
+		{
+			name:        "Application directory",
+			cwd:         "testdata/env1/single",
+			expectedApp: "single",
+		},
+		{
+			name:        "Application directory via instances enabled symlink",
+			cwd:         "testdata/env1/instances.enabled/multi",
+			expectedApp: "multi",
+		},
+		{
+			name:        "Environment root",
+			cwd:         "testdata/env1",
+			expectedApp: "",
+		},
+		{
+			name:        "Not an application directory",
+			cwd:         "testdata/env1/bin",
+			expectedApp: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.Chdir(filepath.Join(wd, tt.cwd)))
+			defer os.Chdir(wd)
+			assert.Equal(t, tt.expectedApp, findCwdApp(appList, appsDir))
+		})
+	}
+}
+
+func Test_prepareOutputDir(t *testing.T) {
+	baseDir := t.TempDir()
+
+	packCtx := PackCtx{OutputDir: filepath.Join(baseDir, "out", "packages")}
+	require.NoError(t, prepareOutputDir(&packCtx))
+	assert.DirExists(t, filepath.Join(baseDir, "out", "packages"))
+	entries, err := os.ReadDir(packCtx.OutputDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	packagePath, err := getPackageFilePath(&packCtx, "bundle.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(baseDir, "out", "packages", "bundle.tar.gz"), packagePath)
+
+	// Output directory path is a file.
+	filePath := filepath.Join(baseDir, "file")
+	require.NoError(t, os.WriteFile(filePath, []byte{}, 0644))
+	packCtx = PackCtx{OutputDir: filePath}
+	assert.ErrorContains(t, prepareOutputDir(&packCtx), "cannot create output directory")
+
+	// Read-only output directory.
+	if os.Getuid() != 0 {
+		readOnlyDir := filepath.Join(baseDir, "read_only")
+		require.NoError(t, os.Mkdir(readOnlyDir, 0555))
+		packCtx = PackCtx{OutputDir: readOnlyDir}
+		assert.ErrorContains(t, prepareOutputDir(&packCtx), "is not writable")
+	}
+}
+
+func Test_initSourceDir(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "bundle", "instances.enabled", "app"),
+		0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(baseDir, "bundle", "instances.enabled", "app", "init.lua"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "bundle", "script.lua"), nil, 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(baseDir, "empty"), 0755))
+
+	packCtx := PackCtx{SourceDir: filepath.Join(baseDir, "bundle")}
+	require.NoError(t, initSourceDir(&packCtx))
+	assert.Equal(t, "bundle", packCtx.Name)
+	assert.ElementsMatch(t, []string{"script.lua", "app"}, packCtx.AppList)
+
+	packCtx = PackCtx{SourceDir: filepath.Join(baseDir, "bundle", "instances.enabled", "app"),
+		Name: "custom"}
+	require.NoError(t, initSourceDir(&packCtx))
+	assert.Equal(t, "custom", packCtx.Name)
+	assert.Equal(t, []string{"app"}, packCtx.AppList)
+
+	packCtx = PackCtx{SourceDir: filepath.Join(baseDir, "empty")}
+	err := initSourceDir(&packCtx)
+	assert.ErrorContains(t, err, "there are no apps found in source directory")
+	assert.ErrorIs(t, err, ErrNoApps)
+
+	packCtx = PackCtx{SourceDir: filepath.Join(baseDir, "missing")}
+	assert.ErrorContains(t, initSourceDir(&packCtx), "cannot access source directory")
+
+	packCtx = PackCtx{SourceDir: filepath.Join(baseDir, "bundle", "script.lua")}
+	assert.ErrorContains(t, initSourceDir(&packCtx), "is not a directory")
+}
+
+func TestConfigFreeOpts(t *testing.T) {
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigPath = "/env/tt.yaml"
+	cmdCtx.Cli.ConfigDir = "/env"
+
+	_, err := ConfigFreeOpts(&cmdCtx, &PackCtx{ConfigFree: true})
+	assert.ErrorContains(t, err, "--config-free flag requires --source-dir flag")
+	assert.Equal(t, "/env/tt.yaml", cmdCtx.Cli.ConfigPath)
+
+	cliOpts, err := ConfigFreeOpts(&cmdCtx, &PackCtx{ConfigFree: true, SourceDir: "bundle"})
+	require.NoError(t, err)
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Empty(t, cmdCtx.Cli.ConfigPath)
+	assert.Equal(t, cwd, cmdCtx.Cli.ConfigDir)
+	assert.Nil(t, cliOpts.Pack)
+	require.NotNil(t, cliOpts.Env)
+}
+
+func Test_applyConfigOpts(t *testing.T) {
+	packOpts := config.PackOpts{
+		Name:     "cfg_name",
+		Version:  "1.0.0",
+		Deps:     []string{"tarantool>=2.10"},
+		PreInst:  "/cfg/preinst.sh",
+		PostInst: "/cfg/postinst.sh",
+		Exclude:  []string{"*.log"},
+	}
+
+	packCtx := PackCtx{Name: "flag_name", RpmDeb: RpmDebCtx{PostInst: "/flag/postinst.sh"}}
+	applyConfigOpts(&packCtx, &packOpts, Deb)
+	assert.Equal(t, "flag_name", packCtx.Name)
+	assert.Equal(t, "1.0.0", packCtx.Version)
+	assert.Equal(t, []string{"tarantool>=2.10"}, packCtx.RpmDeb.Deps)
+	assert.Equal(t, "/cfg/preinst.sh", packCtx.RpmDeb.PreInst)
+	assert.Equal(t, "/flag/postinst.sh", packCtx.RpmDeb.PostInst)
+	assert.Equal(t, []string{"*.log"}, packCtx.Exclude)
+
+	// Rpm and deb options are not applied for archives.
+	packCtx = PackCtx{}
+	applyConfigOpts(&packCtx, &packOpts, Tgz)
+	assert.Equal(t, "cfg_name", packCtx.Name)
+	assert.Empty(t, packCtx.RpmDeb.Deps)
+	assert.Empty(t, packCtx.RpmDeb.PreInst)
+
+	// Missing section.
+	packCtx = PackCtx{}
+	applyConfigOpts(&packCtx, nil, Deb)
+	assert.Equal(t, PackCtx{}, packCtx)
+}
+
+func TestWarnIgnored(t *testing.T) {
+	logger := log.Log.(*log.Logger)
+	prevHandler, prevLevel := logger.Handler, logger.Level
+	defer func() {
+		logger.Handler, logger.Level = prevHandler, prevLevel
+	}()
+	handler := memory.New()
+	logger.Handler, logger.Level = handler, log.DebugLevel
+
+	WarnIgnored(&PackCtx{}, "--%s flag will be ignored", "deps")
+	WarnIgnored(&PackCtx{Quiet: true}, "--%s flag will be ignored", "all")
+	require.Len(t, handler.Entries, 2)
+	assert.Equal(t, log.WarnLevel, handler.Entries[0].Level)
+	assert.Equal(t, "--deps flag will be ignored", handler.Entries[0].Message)
+	assert.Equal(t, log.DebugLevel, handler.Entries[1].Level)
+	assert.Equal(t, "--all flag will be ignored", handler.Entries[1].Message)
+}
+
+func Test_resolveAppEntry(t *testing.T) {
+	appsDir := t.TempDir()
+	realAppDir := filepath.Join(t.TempDir(), "real_app")
+	require.NoError(t, os.Mkdir(realAppDir, 0755))
+	require.NoError(t, os.Symlink(realAppDir, filepath.Join(appsDir, "app")))
+	require.NoError(t, os.Symlink("loop2", filepath.Join(appsDir, "loop1")))
+	require.NoError(t, os.Symlink("loop1", filepath.Join(appsDir, "loop2")))
+
+	appPath, err := resolveAppEntry(appsDir, "app")
+	require.NoError(t, err)
+	expected, err := filepath.EvalSymlinks(realAppDir)
+	require.NoError(t, err)
+	assert.Equal(t, expected, appPath)
+
+	appPath, err = resolveAppEntry(appsDir, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(appsDir, "missing"), appPath)
+
+	_, err = resolveAppEntry(appsDir, "loop1")
+	assert.ErrorContains(t, err, `cannot resolve application "loop1" path`)
+}
+
+func Test_checkDuplicateApps(t *testing.T) {
+	envDir := t.TempDir()
+	appsDir := filepath.Join(envDir, "instances.enabled")
+	require.NoError(t, os.MkdirAll(filepath.Join(appsDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appsDir, "app", "init.lua"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appsDir, "app.lua"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appsDir, "script.lua"), nil, 0644))
+	require.NoError(t, os.Symlink("app", filepath.Join(appsDir, "alias")))
+
+	// The same application listed twice and an alias of the application are not duplicates.
+	require.NoError(t, checkDuplicateApps(appsDir, []string{"app", "app", "alias", "script.lua"}))
+
+	err := checkDuplicateApps(appsDir, []string{"script.lua", "app", "app.lua"})
+	assert.EqualError(t, err, `duplicate application name "app" is resolved from: `+
+		filepath.Join(appsDir, "app")+", "+filepath.Join(appsDir, "app.lua")+
+		", use --allow-duplicate-apps to pack one of them")
+
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = envDir
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: appsDir}}
+	packCtx := PackCtx{}
+	err = initAppsInfo(cliOpts, &cmdCtx, &packCtx)
+	assert.ErrorContains(t, err, `duplicate application name "app"`)
+
+	packCtx = PackCtx{AllowDuplicateApps: true}
+	require.NoError(t, initAppsInfo(cliOpts, &cmdCtx, &packCtx))
+	assert.Contains(t, packCtx.AppsInfo, "app")
+}
+
+func TestSupportedTypes(t *testing.T) {
+	for _, packageType := range SupportedTypes() {
+		packCtx := PackCtx{Type: packageType.String()}
+		packer, err := CreatePacker(&packCtx)
+		require.NoError(t, err)
+		assert.NotNil(t, packer, packageType)
+	}
+	packer, err := CreatePacker(&PackCtx{Type: "exe"})
+	assert.Nil(t, packer)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.EqualError(t, err, `unsupported package type "exe", available types: `+
+		`tgz, zip, deb, rpm, docker, appimage`)
+	assert.Equal(t, "appimage", PackageType(AppImage).String())
+}
+
+func TestDiscoverApps(t *testing.T) {
+	envDir := t.TempDir()
+	appsDir := filepath.Join(envDir, "instances.enabled")
+	require.NoError(t, test_helpers.CreateDirs(appsDir, []string{"app1", "app2", "empty"}))
+	require.NoError(t, test_helpers.CreateFiles(appsDir, []string{"app1/init.lua",
+		"app2/init.lua", "script.lua"}))
+
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = envDir
+	appList, err := DiscoverApps(&cmdCtx, &config.CliOpts{
+		Env: &config.TtEnvOpts{InstancesEnabled: appsDir}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"app1", "app2", "script.lua"}, appList)
+}
+
+func Test_initAppsInfoEmpty(t *testing.T) {
+	envDir := t.TempDir()
+	appsDir := filepath.Join(envDir, "instances.enabled")
+	require.NoError(t, os.MkdirAll(filepath.Join(appsDir, "notapp"), 0755))
+
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = envDir
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: appsDir}}
+	packCtx := PackCtx{AppList: []string{"notapp", "missing"}}
+	err := initAppsInfo(cliOpts, &cmdCtx, &packCtx)
+	assert.ErrorIs(t, err, ErrNoApps)
+	assert.ErrorContains(t, err, "application list entries matched nothing: notapp, missing")
+
+	packCtx = PackCtx{}
+	assert.ErrorIs(t, initAppsInfo(cliOpts, &cmdCtx, &packCtx), ErrNoApps)
+
+	packCtx = PackCtx{AppList: []string{"notapp"}, AllowEmpty: true}
+	require.NoError(t, initAppsInfo(cliOpts, &cmdCtx, &packCtx))
+	assert.Empty(t, packCtx.AppList)
+	assert.Empty(t, packCtx.AppsInfo)
+
+	packCtx = PackCtx{AllowEmpty: true, CartridgeCompat: true}
+	assert.ErrorIs(t, initAppsInfo(cliOpts, &cmdCtx, &packCtx), ErrNoApps)
+}
+
+func TestFillCtxNoApps(t *testing.T) {
+	envDir := t.TempDir()
+	appsDir := filepath.Join(envDir, "instances.enabled")
+	require.NoError(t, os.MkdirAll(appsDir, 0755))
+
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = envDir
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: appsDir}}
+	err := FillCtx(&cmdCtx, &PackCtx{}, cliOpts, []string{"tgz"})
+	assert.ErrorIs(t, err, ErrNoApps)
+	assert.ErrorContains(t, err, "error collect applications info")
+}
+
+func TestFillCtxTarantoolPinSystemTarantool(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "instances.enabled", "app")
+	require.NoError(t, os.MkdirAll(appDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "init.lua"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, tarantoolPinFileName),
+		[]byte("tarantool: 3.0.0\n"), 0644))
+
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.ConfigDir = envDir
+	cmdCtx.Cli.IsSystem = true
+	cmdCtx.Cli.TarantoolCli.Executable = "/usr/bin/tarantool"
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{
+		InstancesEnabled: filepath.Join(envDir, "instances.enabled"),
+		BinDir:           filepath.Join(envDir, "bin")}}
+
+	// The system tarantool is not bundled, so the pinned version is not required.
+	packCtx := PackCtx{}
+	require.NoError(t, FillCtx(&cmdCtx, &packCtx, cliOpts, []string{"tgz"}))
+	assert.True(t, packCtx.TarantoolIsSystem)
+	assert.Equal(t, "/usr/bin/tarantool", cmdCtx.Cli.TarantoolCli.Executable)
+
+	packCtx = PackCtx{WithBinaries: true}
+	assert.ErrorContains(t, FillCtx(&cmdCtx, &packCtx, cliOpts, []string{"tgz"}),
+		"tarantool 3.0.0 is not installed in the environment")
+}
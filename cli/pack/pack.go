@@ -1,6 +1,12 @@
 package pack
 
-import "github.com/tarantool/tt/cli/running"
+import (
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/tarantool/tt/cli/running"
+)
 
 // PackCtx contains all flags for tt pack command.
 type PackCtx struct {
@@ -10,42 +16,316 @@ type PackCtx struct {
 	Name string
 	// Version contains the version of packing bundle.
 	Version string
+	// VersionFromGit means to get the version of packing bundle by `git describe --tags`.
+	VersionFromGit bool
+	// AllowDirty means not to mark the version got from git as dirty if the working
+	// tree has uncommitted changes.
+	AllowDirty bool
 	// AppList contains applications to be packed.
 	AppList []string
-	// FileName contains the name of file of result package.
+	// AllowDuplicateApps means not to fail if the applications from different sources
+	// have the same name. One of them overwrites another in the bundle.
+	AllowDuplicateApps bool
+	// ConfigOverlay is a path to the config file written to the application cluster
+	// config in the bundle. A single application must be packed.
+	ConfigOverlay string
+	// ConfigOverlayMode is ConfigOverlayReplace to replace the application config with
+	// the overlay or ConfigOverlayMerge to deep-merge the overlay into it.
+	// ConfigOverlayReplace is used if it is not set.
+	ConfigOverlayMode string
+	// Since is a git ref. Only the application files changed since the ref are packed
+	// if it is set.
+	Since string
+	// NormalizePermissions means to set 0755 mode for the directories and the executables
+	// detected by a shebang or an ELF header and 0644 mode for other files of the bundle.
+	NormalizePermissions bool
+	// PruneEmptyDirs means to remove the directories left without files after the bundle
+	// content is collected and filtered. The empty directories are kept by default.
+	PruneEmptyDirs bool
+	// AllowEmpty means not to fail if there are no applications to pack or the include
+	// patterns leave the applications without files.
+	AllowEmpty bool
+	// AppListFile is a path to the file with the names of applications to be packed,
+	// one name per line. The names are merged with AppList.
+	AppListFile string
+	// SourceDir is a prebuilt bundle directory. It is packed as is, applications
+	// discovery is skipped if it is set.
+	SourceDir string
+	// GitURL is a URL of the git repository to clone and pack instead of the current
+	// environment. The repository is removed after packing.
+	GitURL string
+	// GitRef is a branch, a tag or a commit hash of GitURL repository to pack. The default
+	// branch is packed if it is not set.
+	GitRef string
+	// ConfigFree means not to use the tt environment configuration. The package is
+	// built from the flags only, so a prebuilt bundle directory is required.
+	ConfigFree bool
+	// FileName contains the name of file of result package. It may contain {name},
+	// {version}, {type}, {arch} and {date} placeholders.
 	FileName string
+	// OutputDir is a directory where the result package is written.
+	// Current working directory is used if it is not set.
+	OutputDir string
+	// TmpDir is a directory for the intermediate files of packing. TMPDIR or the system
+	// temporary directory is used if it is not set.
+	TmpDir string
 	// WithBinaries put binaries into the package regardless if tarantool is system or not.
 	WithBinaries bool
 	// WithoutBinaries ignores binaries regardless if tarantool is system or not.
 	WithoutBinaries bool
+	// StripDebug means to strip debug symbols from the binaries included into the package.
+	StripDebug bool
+	// BinariesLock is a path to the file with expected SHA256 checksums of tarantool and
+	// tt binaries to include into the package.
+	BinariesLock string
+	// AllowExternalSymlinks allows packing of symlinks pointing outside of the application
+	// or package directory. Targets of such application symlinks are copied.
+	AllowExternalSymlinks bool
+	// TargetArch is an architecture the packed binaries must be built for.
+	// Host architecture is used if it is not set.
+	TargetArch string
 	// WithoutModules ignores external modules.
 	WithoutModules bool
+	// IncludeRuntimeDirs means to copy the application log, run and data directories found
+	// in the application source. They are skipped by default.
+	IncludeRuntimeDirs bool
+	// WithoutRocks excludes the applications .rocks directories and skips the rocks building.
+	WithoutRocks bool
+	// FollowConfig means to pack the applications as they are configured for running:
+	// the instance scripts of the multi-instance applications not defined by their
+	// instances or cluster config are not packed.
+	FollowConfig bool
+	// NoRebuild means to pack the applications as is without building the rocks. The
+	// applications having a rockspec are rebuilt by default.
+	NoRebuild bool
 	// TarantoolExecutable is a path to tarantool executable path
 	TarantoolExecutable string
 	// TarantoolIsSystem shows if tarantool is system.
 	TarantoolIsSystem bool
-	// ArchiveCtx contains flags specific for tgz type.
+	// ArchiveCtx contains flags specific for tgz and zip types.
 	Archive ArchiveCtx
 	// RpmDeb contains all information about rpm and deb type of packing.
 	RpmDeb RpmDebCtx
+	// Image contains flags specific for docker image type.
+	Image ImageCtx
 	// UseDocker is set if a package must be built in docker container.
 	UseDocker bool
 	// CartridgeCompat enables backward compatibility with cartridge cli.
 	CartridgeCompat bool
-	// TarantoolVersion specifies the version of the tarantool for pack in docker.
+	// TarantoolVersion specifies the version of the tarantool to include into the package.
+	// The version is installed in docker image or taken from the environment bin_dir.
+	// If it is not set, the version pinned in the applications .tarantool.yml files is
+	// taken from the environment bin_dir.
 	TarantoolVersion string
+	// TarantoolBinary is a path to the tarantool executable to include into the package
+	// instead of the environment one.
+	TarantoolBinary string
+	// TtBinary is a path to the tt executable to include into the package instead of
+	// the running one.
+	TtBinary string
+	// WithChecksum means to write SHA256 checksum file next to the result package.
+	WithChecksum bool
+	// Exclude contains gitignore-style patterns of application files to skip while packing.
+	// The patterns are matched against the bundle-relative paths.
+	Exclude []string
+	// Include contains globs of bundle-relative paths to pack. Other paths are skipped if
+	// it is set. The excluded files are not packed even if they match the globs.
+	Include []string
+	// Labels contains key=value metadata written into the bundle manifest and the Deb
+	// control file. RPM package has no custom header fields for them.
+	Labels []string
+	// AppVersions contains app=version overrides of the packed applications versions
+	// written into the bundle manifest. The package version is used for other applications.
+	AppVersions []string
+	// ExtraFiles contains src:dst mappings of the files to copy into the bundle. The
+	// destination is relative to the bundle environment directory.
+	ExtraFiles []string
+	// Force allows the extra files to overwrite the existing bundle paths.
+	Force bool
+	// ArtifactMode is an octal permission bits of the result package file. The mode of
+	// the created file is kept if it is not set.
+	ArtifactMode string
+	// WarnSize is a human-readable size of the package file to log a warning if the
+	// package exceeds it.
+	WarnSize string
+	// MaxSize is a human-readable maximum size of the package file. Packing fails and
+	// the package is not written if it exceeds the size.
+	MaxSize string
+	// Jobs is a number of workers collecting the files to pack.
+	// runtime.NumCPU() is used if it is not set.
+	Jobs int
+	// IORetries is a number of retries of the binary copying failed with a transient
+	// error. The permanent errors, like a missing file, are not retried.
+	IORetries int
+	// CacheDir is a directory to keep the applications sources between packs. Only
+	// the application files changed since the previous pack are copied if it is set.
+	CacheDir string
+	// Timeout bounds the whole pack operation. The operation is not limited if it is zero.
+	Timeout time.Duration
+	// Sbom means to write CycloneDX JSON SBOM of the bundled applications, rocks and
+	// binaries into the bundle.
+	Sbom bool
+	// SbomArtifact means to write the SBOM next to the package too.
+	SbomArtifact bool
+	// NoLock means to write the package without locking its path.
+	NoLock bool
+	// LockTimeout is a time to wait for the package path lock held by another pack
+	// operation. The lock is not waited if it is zero.
+	LockTimeout time.Duration
+	// OutputFormat is a format of the pack result printed by the command: OutputText
+	// or OutputJSON. Output of the commands run while packing goes to stderr in JSON mode.
+	OutputFormat string
+	// Quiet means to report the ignored flags at debug level instead of warnings.
+	Quiet bool
+	// DryRun means to print the list of files to be packed without creating a package.
+	DryRun bool
+	// ListApps means to print the applications discovered for packing with their source
+	// paths instead of creating the package.
+	ListApps bool
+	// PrintSize means to print the uncompressed and compressed sizes of the package
+	// content instead of creating the package.
+	PrintSize bool
+	// Destination is a scp://[user@]host[:port][/path] URL to upload the created
+	// package to.
+	Destination string
+	// Identity is a private key file used to authenticate on the destination host.
+	// ssh agent is used if it is not set.
+	Identity string
+	// PostPackHook is an executable to run after the package is created.
+	PostPackHook string
 	// IntegrityPrivateKey contains the path to private key for signing hash files.
 	IntegrityPrivateKey string
+	// WithIntegrityIndex means to write the .integrity index of the bundle files
+	// checksums into the bundle root.
+	WithIntegrityIndex bool
+	// ProgressReporter receives packing progress notifications. Progress is not
+	// reported if it is not set.
+	ProgressReporter ProgressReporter
 	// Application info collected from tt env.
 	AppsInfo map[string][]running.InstanceCtx
 	// ConfigFilePath is a path to tt env configuration file.
 	configFilePath string
+	// artifactPath is a path of the created package. It is a docker image name
+	// for the docker image type.
+	artifactPath string
+	// progress tracks packing progress for ProgressReporter.
+	progress *progressTracker
+	// excludePatterns are compiled Exclude patterns.
+	excludePatterns []ignorePattern
+	// bundleAppDirs are the slash-separated application directories relative to the bundle
+	// environment directory keyed by the resolved application directory.
+	bundleAppDirs map[string]string
+	// includePatterns are compiled Include globs.
+	includePatterns []*regexp.Regexp
+	// labels are parsed Labels.
+	labels map[string]string
+	// appVersions are parsed AppVersions keyed by the application name.
+	appVersions map[string]string
+	// extraFiles are parsed ExtraFiles mappings.
+	extraFiles []extraFile
+	// artifactMode is a parsed ArtifactMode.
+	artifactMode *os.FileMode
+	// archBinDir is a directory with the binaries installed by tt install to look for
+	// the binaries built for the package architecture. It is set if several
+	// architectures are packed and the package one is not the host architecture.
+	archBinDir string
+	// integrityHashes are the checksums of the bundle files collected for the integrity
+	// index.
+	integrityHashes *integrityHashes
+	// artifactLock is the lock of the package path held until the package and its
+	// sidecar files are written.
+	artifactLock *packageLock
+	// crossArch shows if several architectures are packed and the package one is not
+	// the host architecture.
+	crossArch bool
+	// sbom is the generated SBOM content.
+	sbom []byte
+	// artifactTmpDir is a directory for the temporary package files. They are written
+	// next to the package if it is not set.
+	artifactTmpDir string
+	// bundledBinaries are the bundle paths of the copied binaries keyed by the binary
+	// name.
+	bundledBinaries map[string]string
+	// warnSize and maxSize are parsed WarnSize and MaxSize in bytes.
+	warnSize, maxSize int64
+	// sinceFiles are the slash-separated relative paths of the files changed since
+	// the Since git ref keyed by the application name.
+	sinceFiles map[string]map[string]bool
+	// configuredFiles are the slash-separated relative paths of the files defined by
+	// the application instances keyed by the resolved application directory.
+	configuredFiles map[string]map[string]bool
+	// configOverlay is the parsed ConfigOverlay content.
+	configOverlay map[interface{}]interface{}
+	// destination is a parsed Destination.
+	destination *scpDestination
+	// appListFileApps contains the application names from AppListFile.
+	appListFileApps map[string]bool
+	// operation tracks the running pack operation to stop it on timeout or signal.
+	operation *packOperation
+	// binariesLock contains the binaries checksums loaded from BinariesLock file.
+	binariesLock map[string]string
+	// sharedContent is a bundle content shared by the packages built in one invocation.
+	sharedContent *sharedBundleContent
+	// sourceDateEpoch is a time from SOURCE_DATE_EPOCH environment variable. It is used
+	// for reproducible builds if set.
+	sourceDateEpoch *time.Time
 }
 
-// ArchiveCtx contains flags specific for tgz type.
+// ArchiveCtx contains flags specific for tgz and zip types.
 type ArchiveCtx struct {
 	// All means pack all artifacts from bundle, including pid files etc.
 	All bool
+	// Compressor is a tarball compressor: CompressorGzip or CompressorZstd.
+	// Gzip is used if it is not set.
+	Compressor string
+	// CompressionLevel is a compression level in the compressor range: from 0
+	// (no compression) to 9 (best compression) for gzip, from 1 to 22 for zstd.
+	// Default is 6.
+	CompressionLevel int
+	// PreserveSymlinks means to keep application symlinks as links instead of copying
+	// the target contents. Symlinks are dereferenced by default.
+	PreserveSymlinks bool
+	// BaseTgz is a path to the tarball to layer the package onto. The package files
+	// override the conflicting files of the base tarball.
+	BaseTgz string
+	// BundleRoot is a top-level directory name of the tgz and zip bundle content.
+	// The content is placed into the archive root if it is not set.
+	BundleRoot string
+	// DeltaAgainst is a path to the previous tarball to create the delta against.
+	// The delta is written next to the tarball with .delta suffix.
+	DeltaAgainst string
+	// Dedup means to pack the byte-identical duplicate files of tgz as hardlinks to the
+	// first of them.
+	Dedup bool
+	// DedupMinSize is a human-readable size of the smallest file to deduplicate.
+	// 4 KiB is used if it is not set.
+	DedupMinSize string
+	// StoreGlobs are globs of the zip entry paths to store without compression, for
+	// example, the already compressed files. A gzip or zstd tarball is compressed as a
+	// whole stream, so the globs apply to zip only.
+	StoreGlobs []string
+	// SplitSize is a human-readable size of the tarball volumes. The tarball is split
+	// into the volumes with an index if it is set.
+	SplitSize string
+
+	// dedupMinSize is parsed DedupMinSize in bytes.
+	dedupMinSize int64
+	// storePatterns are compiled StoreGlobs.
+	storePatterns []*regexp.Regexp
+	// splitSize is parsed SplitSize in bytes.
+	splitSize int64
+	// splitTarballPath is the path of the tarball to split into volumes.
+	splitTarballPath string
+	// volumes are the paths of the written tarball volumes.
+	volumes []string
+}
+
+// ImageCtx contains flags specific for docker image type.
+type ImageCtx struct {
+	// From is a base image. DefaultImageBase is used if it is not set.
+	From string
+	// Tag is the result image name. <name>:<version> is used if it is not set.
+	Tag string
 }
 
 // RpmDebCtx contains flags specific for RPM/DEB type.
@@ -57,13 +337,92 @@ type RpmDebCtx struct {
 	PreInst string
 	// PostInst is a path to post-install script.
 	PostInst string
+	// PreRm is a path to pre-remove script.
+	PreRm string
+	// PostRm is a path to post-remove script.
+	PostRm string
 	// Deps is dependencies list. Format:
 	// dependency_06>=4
 	Deps []string
 	// DepsFile is a path to a file of dependencies.
 	DepsFile string
+	// Conflicts is a list of packages conflicting with the package. Format is the same
+	// as for Deps.
+	Conflicts []string
+	// Provides is a list of virtual packages provided by the package. Format is the same
+	// as for Deps.
+	Provides []string
 	// SystemdUnitParamsFile is a path to file with systemd unit parameters.
 	SystemdUnitParamsFile string
+	// SystemdUnitTemplateFile is a path to text/template file of systemd unit.
+	// Built-in template is used if it is not set.
+	SystemdUnitTemplateFile string
+	// InstallPrefix is a path in the target system where the environment is installed.
+	// /usr/share/tarantool is used if it is not set.
+	InstallPrefix string
+	// SignKey is a GPG key id or a path to the key file to sign the package with.
+	SignKey string
+	// RpmEpoch is an epoch of the RPM package. Epoch is not set if it is zero.
+	RpmEpoch uint
+	// RpmRelease is a release of the RPM package. "1" is used if it is not set.
+	RpmRelease string
+	// RpmScriptletInterpreter is an interpreter of the RPM install and remove scripts:
+	// /bin/sh, /bin/bash or lua for the rpm embedded Lua. The user scripts must be
+	// written for the interpreter. "/bin/sh" is used if it is not set.
+	RpmScriptletInterpreter string
+	// DebArch is an architecture of the Deb package. Host architecture is used if it is
+	// not set. A package is built for each architecture of comma-separated list.
+	DebArch string
+	// RpmArch is an architecture of the RPM package. Host architecture is used if it is
+	// not set. A package is built for each architecture of comma-separated list.
+	RpmArch string
+	// Changelog is a path to the changelog file in RPM or debian changelog format.
+	Changelog string
+	// Maintainer is a maintainer of the package in "Name <email>" format.
+	// "Tarantool developer" is used if it is not set.
+	Maintainer string
+	// Homepage is an URL of the package home page. It is not set by default.
+	Homepage string
+	// License is a license of the package. "N/A" is used for RPM package if it is not set.
+	// It is written to the copyright file of Deb package.
+	License string
+	// Description is a description of the package, the first line is a summary.
+	// "Tarantool environment: <name>" is used if it is not set.
+	Description string
+	// rpmChangelog contains parsed RPM changelog entries.
+	rpmChangelog []changelogEntry
+	// debChangelog is a content of debian changelog file.
+	debChangelog string
+	// preInstScript is a rendered content of the pre-install script.
+	preInstScript string
+	// postInstScript is a rendered content of the post-install script.
+	postInstScript string
+	// preRmScript is a rendered content of the pre-remove script.
+	preRmScript string
+	// postRmScript is a rendered content of the post-remove script.
+	postRmScript string
+	// systemdUnitTemplate is a content of systemd unit template file.
+	systemdUnitTemplate string
+	// FileModes are the specs of the package files mode and ownership in
+	// <glob>=<mode>:<owner>:<group> format.
+	FileModes []string
 	// pkgFilesInfo files info to modify in result rpm/deb package.
 	pkgFilesInfo map[string]packFileInfo
+	// fileModeRules are parsed FileModes specs.
+	fileModeRules []fileModeRule
+	// RpmConfigFiles are the globs of the RPM package files marked as
+	// %config(noreplace), so the upgrade does not overwrite the changed files.
+	RpmConfigFiles []string
+	// rpmConfigFileRes are the compiled RpmConfigFiles globs.
+	rpmConfigFileRes []*regexp.Regexp
+	// DebConffiles are the globs of the Deb package files listed in conffiles, so
+	// the upgrade does not overwrite the changed files.
+	DebConffiles []string
+	// debConffileRes are the compiled DebConffiles globs.
+	debConffileRes []*regexp.Regexp
+	// CreateRuntimeDirs means to add the data, log and run directories of the environment
+	// and its applications to the package, so they exist after the installation.
+	CreateRuntimeDirs bool
+	// runtimeDirs are the package paths of the runtime directories to create.
+	runtimeDirs []string
 }
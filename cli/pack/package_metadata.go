@@ -0,0 +1,85 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// defaultRpmLicense is a license of the RPM package if it is not set.
+	defaultRpmLicense = "N/A"
+	// debCopyrightFileName is a name of the copyright file installed by deb package.
+	debCopyrightFileName = "copyright"
+)
+
+// debMaintainerRe matches the Deb package maintainer in "Name <email>" format.
+var debMaintainerRe = regexp.MustCompile(`^[^<>\s][^<>]*\s<[^<>@\s]+@[^<>@\s]+>$`)
+
+// checkDebMaintainer checks the maintainer has "Name <email>" format required by Deb.
+func checkDebMaintainer(maintainer string) error {
+	if !debMaintainerRe.MatchString(maintainer) {
+		return fmt.Errorf("invalid maintainer %q: expected \"Name <email>\" format",
+			maintainer)
+	}
+	return nil
+}
+
+// getPackageMaintainer returns the maintainer of the RPM or Deb package.
+func getPackageMaintainer(packCtx *PackCtx) string {
+	if packCtx.RpmDeb.Maintainer != "" {
+		return packCtx.RpmDeb.Maintainer
+	}
+	return defaultMaintainer
+}
+
+// getPackageDescription returns the description of the RPM or Deb package. The first
+// line of the description is a package summary.
+func getPackageDescription(packCtx *PackCtx) string {
+	if packCtx.RpmDeb.Description != "" {
+		return packCtx.RpmDeb.Description
+	}
+	return "Tarantool environment: " + packCtx.Name
+}
+
+// getRpmLicense returns the license of the RPM package.
+func getRpmLicense(packCtx *PackCtx) string {
+	if packCtx.RpmDeb.License != "" {
+		return packCtx.RpmDeb.License
+	}
+	return defaultRpmLicense
+}
+
+// formatDebDescription formats the description as a Deb control field value: the lines
+// following the synopsis are indented and empty lines are replaced with " .".
+func formatDebDescription(description string) string {
+	lines := strings.Split(strings.TrimSpace(description), "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			lines[i] = " ."
+		} else {
+			lines[i] = " " + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeDebCopyright writes the machine-readable copyright file with the package license
+// into the package documentation directory.
+func writeDebCopyright(packageDataDir string, packCtx *PackCtx) error {
+	docDir := filepath.Join(packageDataDir, "usr", "share", "doc", packCtx.Name)
+	if err := os.MkdirAll(docDir, dirPermissions); err != nil {
+		return err
+	}
+	content := "Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/\n" +
+		"Upstream-Name: " + packCtx.Name + "\n"
+	if packCtx.RpmDeb.Homepage != "" {
+		content += "Source: " + packCtx.RpmDeb.Homepage + "\n"
+	}
+	content += "\nFiles: *\nCopyright: " + getPackageMaintainer(packCtx) + "\n" +
+		"License: " + packCtx.RpmDeb.License + "\n"
+	return os.WriteFile(filepath.Join(docDir, debCopyrightFileName), []byte(content),
+		filePermissions)
+}
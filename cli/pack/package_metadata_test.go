@@ -0,0 +1,56 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkDebMaintainer(t *testing.T) {
+	for _, maintainer := range []string{"Jane Doe <jane@example.com>",
+		"Tarantool Team <dev@tarantool.org>"} {
+		assert.NoError(t, checkDebMaintainer(maintainer))
+	}
+	for _, maintainer := range []string{"Jane Doe", "<jane@example.com>",
+		"Jane Doe <jane>", "Jane Doe <jane@example.com> extra", " <jane@example.com>"} {
+		assert.EqualError(t, checkDebMaintainer(maintainer), `invalid maintainer "`+
+			maintainer+`": expected "Name <email>" format`)
+	}
+}
+
+func Test_getPackageMetadataDefaults(t *testing.T) {
+	packCtx := PackCtx{Name: "app"}
+	assert.Equal(t, "Tarantool developer", getPackageMaintainer(&packCtx))
+	assert.Equal(t, "Tarantool environment: app", getPackageDescription(&packCtx))
+	assert.Equal(t, "N/A", getRpmLicense(&packCtx))
+
+	packCtx.RpmDeb = RpmDebCtx{Maintainer: "Jane <jane@example.com>", License: "MIT",
+		Description: "App"}
+	assert.Equal(t, "Jane <jane@example.com>", getPackageMaintainer(&packCtx))
+	assert.Equal(t, "App", getPackageDescription(&packCtx))
+	assert.Equal(t, "MIT", getRpmLicense(&packCtx))
+}
+
+func Test_formatDebDescription(t *testing.T) {
+	assert.Equal(t, "Summary", formatDebDescription("Summary\n"))
+	assert.Equal(t, "Summary\n First line\n .\n Second line",
+		formatDebDescription("Summary\nFirst line\n\nSecond line"))
+}
+
+func Test_writeDebCopyright(t *testing.T) {
+	dataDir := t.TempDir()
+	packCtx := PackCtx{Name: "app", RpmDeb: RpmDebCtx{License: "BSD-2-Clause",
+		Homepage: "https://example.com/app"}}
+	require.NoError(t, writeDebCopyright(dataDir, &packCtx))
+	content, err := os.ReadFile(filepath.Join(dataDir, "usr", "share", "doc", "app",
+		debCopyrightFileName))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/\n"+
+			"Upstream-Name: app\nSource: https://example.com/app\n\n"+
+			"Files: *\nCopyright: Tarantool developer\nLicense: BSD-2-Clause\n",
+		string(content))
+}
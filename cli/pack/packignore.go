@@ -0,0 +1,239 @@
+package pack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tarantool/tt/cli/config"
+)
+
+// ignoreFileName is a name of the file containing patterns of paths to skip while packing.
+// The file has gitignore syntax.
+const ignoreFileName = ".packignore"
+
+// ignorePattern is a compiled pattern from .packignore file.
+type ignorePattern struct {
+	// re is a regular expression to match slash-separated relative path.
+	re *regexp.Regexp
+	// negate is set for the patterns prefixed with '!', re-including matched paths.
+	negate bool
+	// dirOnly is set for the patterns with trailing slash, matching directories only.
+	dirOnly bool
+}
+
+// globToRegexp converts gitignore glob to the regular expression string.
+func globToRegexp(glob string) (string, error) {
+	var re strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' &&
+				(i == 0 || glob[i-1] == '/') && (i+2 == len(glob) || glob[i+2] == '/') {
+				if i+2 == len(glob) {
+					// Trailing "**" matches everything inside.
+					re.WriteString(".*")
+				} else {
+					// Leading or middle "**/" matches zero or more directories.
+					re.WriteString("(?:.*/)?")
+				}
+				i += 2
+				continue
+			}
+			re.WriteString("[^/]*")
+		case '?':
+			re.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end == -1 {
+				return "", fmt.Errorf("unterminated character class in %q", glob)
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			re.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return re.String(), nil
+}
+
+// parseIgnorePattern compiles a single .packignore line. Returns false if the line
+// does not contain a pattern.
+func parseIgnorePattern(line string) (ignorePattern, bool, error) {
+	pattern := ignorePattern{}
+	line = strings.TrimRight(line, " \t")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern, false, nil
+	}
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return pattern, false, nil
+	}
+
+	// A pattern with a slash in the beginning or in the middle is relative to the root.
+	// Otherwise, it matches at any level.
+	prefix := "^(?:.*/)?"
+	if strings.Contains(line, "/") {
+		prefix = "^"
+		line = strings.TrimPrefix(line, "/")
+	}
+	reStr, err := globToRegexp(line)
+	if err != nil {
+		return pattern, false, err
+	}
+	if pattern.re, err = regexp.Compile(prefix + reStr + "$"); err != nil {
+		return pattern, false, err
+	}
+	return pattern, true, nil
+}
+
+// loadIgnorePatterns loads patterns from the ignore file in the passed directory.
+// Nil slice is returned if there is no ignore file.
+func loadIgnorePatterns(dir string) ([]ignorePattern, error) {
+	ignoreFilePath := filepath.Join(dir, ignoreFileName)
+	file, err := os.Open(ignoreFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot open %q: %s", ignoreFilePath, err)
+	}
+	defer file.Close()
+
+	var patterns []ignorePattern
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		pattern, ok, err := parseIgnorePattern(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in %q at line %d: %s",
+				ignoreFilePath, lineNum, err)
+		}
+		if ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %q: %s", ignoreFilePath, err)
+	}
+	return patterns, nil
+}
+
+// matchIgnorePatterns checks the slash-separated relative path against the patterns.
+// The last matching pattern wins.
+func matchIgnorePatterns(patterns []ignorePattern, relPath string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if pattern.re.MatchString(relPath) {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
+
+// isIgnored checks if the slash-separated relative path is ignored by the patterns.
+// The path is ignored if any of its parent directories is ignored.
+func isIgnored(patterns []ignorePattern, relPath string, isDir bool) bool {
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if matchIgnorePatterns(patterns, strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return matchIgnorePatterns(patterns, relPath, isDir)
+}
+
+// parseExcludePatterns compiles the patterns passed with --exclude flag.
+func parseExcludePatterns(excludes []string) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	for _, exclude := range excludes {
+		pattern, ok, err := parseIgnorePattern(exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %s", exclude, err)
+		}
+		if ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, nil
+}
+
+// ignoreFilter returns a filter func to skip the application files, which match
+// the patterns from ignore files or the extra patterns. The patterns from the environment
+// directory ignore file are applied first, so the application ignore file patterns layer
+// on top of them. The ignore files patterns are matched against the application-relative
+// paths. The extra patterns are matched against the bundle-relative paths, the application
+// is placed to the bundleAppDir of the bundle. The file is skipped if it is ignored by
+// the ignore files or by the extra patterns.
+func ignoreFilter(envDir, srcAppPath, bundleAppDir string,
+	extraPatterns []ignorePattern) (func(srcInfo os.FileInfo, src string) bool, error) {
+	var patterns []ignorePattern
+	if envDir != "" {
+		envPatterns, err := loadIgnorePatterns(envDir)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, envPatterns...)
+	}
+	appPatterns, err := loadIgnorePatterns(srcAppPath)
+	if err != nil {
+		return nil, err
+	}
+	patterns = append(patterns, appPatterns...)
+
+	return func(srcInfo os.FileInfo, src string) bool {
+		relPath, err := filepath.Rel(srcAppPath, src)
+		if err != nil {
+			return false
+		}
+		relPath = filepath.ToSlash(relPath)
+		if relPath == ignoreFileName {
+			return true
+		}
+		// Symlinks are matched by the link path, not by the target path.
+		return isIgnored(patterns, relPath, srcInfo.IsDir()) ||
+			isIgnored(extraPatterns, path.Join(bundleAppDir, relPath), srcInfo.IsDir())
+	}, nil
+}
+
+// initBundleAppDirs collects the bundle-relative directories of the applications keyed by
+// the resolved application directory to match the exclude patterns against the bundle
+// paths. The single file applications are not filtered.
+func initBundleAppDirs(packCtx *PackCtx, cliOpts *config.CliOpts) error {
+	packCtx.bundleAppDirs = map[string]string{}
+	for appName, instances := range packCtx.AppsInfo {
+		if len(instances) == 0 || instances[0].IsFileApp {
+			continue
+		}
+		appDir, err := filepath.EvalSymlinks(instances[0].AppDir)
+		if err != nil {
+			return fmt.Errorf("cannot apply --exclude to application %q: %s", appName, err)
+		}
+		packCtx.bundleAppDirs[appDir] = getBundleAppDir(appName, packCtx, cliOpts)
+	}
+	return nil
+}
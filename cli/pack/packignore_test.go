@@ -0,0 +1,218 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func Test_isIgnored(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		path     string
+		isDir    bool
+		ignored  bool
+	}{
+		{[]string{"*.swp"}, "init.lua", false, false},
+		{[]string{"*.swp"}, ".init.lua.swp", false, true},
+		{[]string{"*.swp"}, "lib/.mod.lua.swp", false, true},
+		{[]string{"/*.swp"}, "lib/.mod.lua.swp", false, false},
+		{[]string{"test/"}, "test", true, true},
+		{[]string{"test/"}, "test", false, false},
+		{[]string{"test/"}, "test/unit/init_test.lua", false, true},
+		{[]string{"test/"}, "lib/test/fixture.lua", false, true},
+		{[]string{"/test/"}, "lib/test/fixture.lua", false, false},
+		{[]string{"lib/*.tmp"}, "lib/a.tmp", false, true},
+		{[]string{"lib/*.tmp"}, "lib/sub/a.tmp", false, false},
+		{[]string{"lib/**/*.tmp"}, "lib/sub/dir/a.tmp", false, true},
+		{[]string{"lib/**/*.tmp"}, "lib/a.tmp", false, true},
+		{[]string{"**/fixtures"}, "a/b/fixtures", true, true},
+		{[]string{"data/**"}, "data/snap/00.snap", false, true},
+		{[]string{"data/**"}, "data", true, false},
+		{[]string{"*.log", "!keep.log"}, "app.log", false, true},
+		{[]string{"*.log", "!keep.log"}, "keep.log", false, false},
+		{[]string{"logs/", "!logs/keep.log"}, "logs/keep.log", false, true},
+		{[]string{"file?.txt"}, "file1.txt", false, true},
+		{[]string{"file[0-9].txt"}, "filea.txt", false, false},
+		{[]string{"file[!0-9].txt"}, "filea.txt", false, true},
+		{[]string{`\#hash`}, "#hash", false, true},
+		{[]string{"# comment", ""}, "# comment", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var patterns []ignorePattern
+			for _, line := range tt.patterns {
+				pattern, ok, err := parseIgnorePattern(line)
+				require.NoError(t, err)
+				if ok {
+					patterns = append(patterns, pattern)
+				}
+			}
+			assert.Equal(t, tt.ignored, isIgnored(patterns, tt.path, tt.isDir),
+				"patterns: %v", tt.patterns)
+		})
+	}
+}
+
+func Test_parseIgnorePatternInvalid(t *testing.T) {
+	_, _, err := parseIgnorePattern("file[0-9.txt")
+	assert.ErrorContains(t, err, "unterminated character class")
+}
+
+func Test_copyAppSrcWithPackIgnore(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "app")
+	outsideDir := filepath.Join(envDir, "outside")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{"test", "lib", "tmp"}))
+	require.NoError(t, os.Mkdir(outsideDir, 0755))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua", "init.lua.swp",
+		"test/app_test.lua", "lib/mod.lua", "lib/mod.tmp", "tmp/keep.tmp"}))
+	require.NoError(t, test_helpers.CreateFiles(outsideDir, []string{"shared.lua"}))
+	// Symlink in the ignored directory pointing outside.
+	require.NoError(t, os.Symlink(filepath.Join(outsideDir, "shared.lua"),
+		filepath.Join(appDir, "test", "shared.lua")))
+	// Symlink in not ignored directory pointing to the ignored directory.
+	require.NoError(t, os.Symlink(filepath.Join(appDir, "test", "app_test.lua"),
+		filepath.Join(appDir, "lib", "app_test.lua")))
+
+	require.NoError(t, os.WriteFile(filepath.Join(envDir, ignoreFileName),
+		[]byte("*.swp\n*.tmp\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, ignoreFileName),
+		[]byte("# Tests.\ntest/\n!tmp/keep.tmp\n"), 0644))
+
+	packCtx := PackCtx{
+		configFilePath: filepath.Join(envDir, "tt.yaml"),
+		Archive:        ArchiveCtx{PreserveSymlinks: true},
+	}
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{}, appDir,
+		dstDir))
+
+	assert.FileExists(t, filepath.Join(dstDir, "init.lua"))
+	assert.FileExists(t, filepath.Join(dstDir, "lib", "mod.lua"))
+	assert.FileExists(t, filepath.Join(dstDir, "tmp", "keep.tmp"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "init.lua.swp"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "lib", "mod.tmp"))
+	assert.NoFileExists(t, filepath.Join(dstDir, ignoreFileName))
+	assert.NoDirExists(t, filepath.Join(dstDir, "test"))
+
+	stat, err := os.Lstat(filepath.Join(dstDir, "lib", "app_test.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSymlink, stat.Mode().Type())
+}
+
+func Test_parseExcludePatterns(t *testing.T) {
+	patterns, err := parseExcludePatterns([]string{"*.log", "tmp/**", ""})
+	require.NoError(t, err)
+	assert.Len(t, patterns, 2)
+
+	_, err = parseExcludePatterns([]string{"*.log", "file[0-9.txt"})
+	assert.ErrorContains(t, err, `invalid exclude pattern "file[0-9.txt"`)
+}
+
+func Test_copyAppSrcWithExclude(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{"tmp", "lib", "data"}))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua", "app.log",
+		"lib/mod.lua", "lib/mod.swp", "tmp/data.txt", "data/keep.txt"}))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, ignoreFileName),
+		[]byte("*.swp\n"), 0644))
+
+	// The patterns are matched against the bundle-relative paths.
+	excludePatterns, err := parseExcludePatterns([]string{"*.log", "app/tmp/**", "data/**"})
+	require.NoError(t, err)
+	cliOpts := config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	packCtx := PackCtx{configFilePath: filepath.Join(envDir, "tt.yaml"),
+		excludePatterns: excludePatterns,
+		AppsInfo:        map[string][]running.InstanceCtx{"app": {{AppDir: appDir}}}}
+	require.NoError(t, initBundleAppDirs(&packCtx, &cliOpts))
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &cliOpts, appDir, dstDir))
+
+	assert.FileExists(t, filepath.Join(dstDir, "init.lua"))
+	assert.FileExists(t, filepath.Join(dstDir, "lib", "mod.lua"))
+	assert.FileExists(t, filepath.Join(dstDir, "data", "keep.txt"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "app.log"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "lib", "mod.swp"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "tmp", "data.txt"))
+
+	// The application is the bundle root in the single application environment.
+	cliOpts.Env.InstancesEnabled = "."
+	require.NoError(t, initBundleAppDirs(&packCtx, &cliOpts))
+	dstDir = filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &cliOpts, appDir, dstDir))
+	assert.FileExists(t, filepath.Join(dstDir, "tmp", "data.txt"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "data", "keep.txt"))
+}
+
+func Test_copyAppSrcWithoutRocks(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{
+		".rocks/share/tarantool/rocks", "lib/.rocks"}))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua", "app.log",
+		".rocks/share/tarantool/rocks/manifest", "lib/.rocks/mod.lua"}))
+
+	excludePatterns, err := parseExcludePatterns([]string{"*.log"})
+	require.NoError(t, err)
+	packCtx := PackCtx{configFilePath: filepath.Join(envDir, "tt.yaml"),
+		excludePatterns: excludePatterns, WithoutRocks: true}
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{}, appDir,
+		dstDir))
+
+	assert.FileExists(t, filepath.Join(dstDir, "init.lua"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "app.log"))
+	assert.NoDirExists(t, filepath.Join(dstDir, ".rocks"))
+	// Only the application root .rocks directory is skipped.
+	assert.FileExists(t, filepath.Join(dstDir, "lib", ".rocks", "mod.lua"))
+
+	packCtx.WithoutRocks = false
+	dstDir = filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &config.CliOpts{}, appDir,
+		dstDir))
+	assert.FileExists(t, filepath.Join(dstDir, rocksManifestPath))
+}
+
+func Test_copyAppSrcRuntimeDirs(t *testing.T) {
+	envDir := t.TempDir()
+	appDir := filepath.Join(envDir, "app")
+	require.NoError(t, test_helpers.CreateDirs(appDir, []string{"state/logs/inst",
+		"state/run/inst", "state/data/inst", "var/log"}))
+	require.NoError(t, test_helpers.CreateFiles(appDir, []string{"init.lua",
+		"state/logs/inst/tt.log", "state/run/inst/tt.pid", "state/data/inst/0.snap",
+		"var/log/keep.lua"}))
+
+	// Custom runtime directories are skipped, the default ones are not.
+	cliOpts := config.CliOpts{App: &config.AppOpts{
+		LogDir:   "state/logs",
+		RunDir:   "state/run",
+		WalDir:   "state/data",
+		MemtxDir: "state/data",
+		VinylDir: "state/data",
+	}}
+	packCtx := PackCtx{configFilePath: filepath.Join(envDir, "tt.yaml")}
+	dstDir := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &cliOpts, appDir, dstDir))
+	assert.FileExists(t, filepath.Join(dstDir, "init.lua"))
+	assert.FileExists(t, filepath.Join(dstDir, "var", "log", "keep.lua"))
+	for _, dir := range []string{"logs", "run", "data"} {
+		assert.NoDirExists(t, filepath.Join(dstDir, "state", dir))
+	}
+
+	packCtx.IncludeRuntimeDirs = true
+	dstDir = filepath.Join(t.TempDir(), "app")
+	require.NoError(t, copyAppSrc(context.Background(), &packCtx, &cliOpts, appDir, dstDir))
+	assert.FileExists(t, filepath.Join(dstDir, "state", "logs", "inst", "tt.log"))
+	assert.FileExists(t, filepath.Join(dstDir, "state", "run", "inst", "tt.pid"))
+	assert.FileExists(t, filepath.Join(dstDir, "state", "data", "inst", "0.snap"))
+}
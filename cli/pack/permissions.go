@@ -0,0 +1,83 @@
+package pack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// normalizedFileMode is a mode of the regular files with normalized permissions.
+	normalizedFileMode os.FileMode = 0644
+	// normalizedExecMode is a mode of the directories and executables with normalized
+	// permissions.
+	normalizedExecMode os.FileMode = 0755
+)
+
+// executableMagics are the file content prefixes of the executables: a script shebang
+// and an ELF header.
+var executableMagics = [][]byte{[]byte("#!"), []byte("\x7fELF")}
+
+// isExecutableFile returns true if the file content starts with a shebang or an ELF
+// header. The source permission bits are not trusted, so other files are not executable.
+func isExecutableFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, 4)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false, err
+	}
+	for _, magic := range executableMagics {
+		if bytes.HasPrefix(head[:n], magic) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// normalizePermissions sets 0755 mode for the directories and the executables and
+// 0644 mode for other regular files in the bundle. Symlinks are kept as is.
+func normalizePermissions(bundlePath string) error {
+	return filepath.WalkDir(bundlePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		mode := normalizedFileMode
+		switch {
+		case entry.IsDir():
+			mode = normalizedExecMode
+		case entry.Type().IsRegular():
+			isExec, err := isExecutableFile(path)
+			if err != nil {
+				return err
+			}
+			if isExec {
+				mode = normalizedExecMode
+			}
+		default:
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModePerm == mode && info.Mode()&(fs.ModeSetuid|fs.ModeSetgid|
+			fs.ModeSticky) == 0 {
+			return nil
+		}
+		if err = os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("cannot normalize permissions of %q: %s", path, err)
+		}
+		return nil
+	})
+}
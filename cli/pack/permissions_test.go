@@ -0,0 +1,47 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_normalizePermissions(t *testing.T) {
+	bundleDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "app", "bin"), 0777))
+	files := map[string]string{
+		"app/init.lua":    "box.cfg{}",
+		"app/bin/run.sh":  "#!/bin/sh\necho run",
+		"app/bin/binary":  "\x7fELF\x02\x01",
+		"app/empty":       "",
+		"app/bin/private": "secret",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(bundleDir, name), []byte(content), 0777))
+	}
+	require.NoError(t, os.Chmod(filepath.Join(bundleDir, "app/bin/private"), 0600))
+	require.NoError(t, os.Symlink("init.lua", filepath.Join(bundleDir, "app", "link.lua")))
+
+	require.NoError(t, normalizePermissions(bundleDir))
+
+	expectedModes := map[string]os.FileMode{
+		"app":             0755,
+		"app/bin":         0755,
+		"app/init.lua":    0644,
+		"app/bin/run.sh":  0755,
+		"app/bin/binary":  0755,
+		"app/empty":       0644,
+		"app/bin/private": 0644,
+	}
+	for name, mode := range expectedModes {
+		info, err := os.Stat(filepath.Join(bundleDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, mode, info.Mode().Perm(), name)
+	}
+	info, err := os.Lstat(filepath.Join(bundleDir, "app", "link.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSymlink, info.Mode().Type())
+}
@@ -0,0 +1,96 @@
+package pack
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+// countingWriter discards the written data counting its size.
+type countingWriter struct {
+	size int64
+}
+
+// Write counts the passed data size.
+func (writer *countingWriter) Write(data []byte) (int, error) {
+	writer.size += int64(len(data))
+	return len(data), nil
+}
+
+// getCompressedSize returns the size of the package content compressed the same way as
+// the packer of the package type does. The payload size is returned for Deb and RPM
+// packages. Returns true if the size is estimated.
+func getCompressedSize(packCtx *PackCtx, bundlePath string) (int64, bool, error) {
+	counter := countingWriter{}
+	var err error
+	estimated := false
+	switch packCtx.Type {
+	case Tgz:
+		err = writeCompressedTar(bundlePath, &counter, packCtx)
+	case Zip:
+		err = writeZip(bundlePath, &counter, packCtx)
+	case Deb, Rpm:
+		err = writeTgz(bundlePath, &counter, packCtx, gzip.DefaultCompression)
+		estimated = true
+	default:
+		return 0, false, fmt.Errorf("size printing is not supported for %s packages",
+			packCtx.Type)
+	}
+	return counter.size, estimated, err
+}
+
+// getUncompressedSize returns the total size of the files to pack.
+func getUncompressedSize(packCtx *PackCtx, bundlePath string) (int64, error) {
+	files, err := collectPackFiles(packCtx, bundlePath)
+	if err != nil {
+		return 0, err
+	}
+	var totalSize int64
+	for _, file := range files {
+		if file.info.Mode().IsRegular() {
+			totalSize += file.info.Size()
+		}
+	}
+	return totalSize, nil
+}
+
+// PrintSize prepares the bundle the same way as packers do and prints its uncompressed
+// size and the compressed size for each of the package types. The result packages are
+// not created. The pack contexts must differ in the package type only.
+func PrintSize(cmdCtx *cmdcontext.CmdCtx, packCtxs []*PackCtx, opts *config.CliOpts,
+	writer io.Writer) error {
+	bundlePath, err := prepareBundle(cmdCtx, packCtxs[0], opts, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := os.RemoveAll(bundlePath)
+		if err != nil {
+			log.Warnf("Failed to remove a temporary directory %s: %s",
+				bundlePath, err.Error())
+		}
+	}()
+
+	uncompressedSize, err := getUncompressedSize(packCtxs[0], bundlePath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(writer, "Uncompressed: %d bytes\n", uncompressedSize)
+	for _, packCtx := range packCtxs {
+		size, estimated, err := getCompressedSize(packCtx, bundlePath)
+		if err != nil {
+			return err
+		}
+		note := ""
+		if estimated {
+			note = " (estimated by payload)"
+		}
+		fmt.Fprintf(writer, "%s: %d bytes%s\n", packCtx.Type, size, note)
+	}
+	return nil
+}
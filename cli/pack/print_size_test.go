@@ -0,0 +1,51 @@
+package pack
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func TestPrintSize(t *testing.T) {
+	sourceDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "app", "init.lua"),
+		[]byte(strings.Repeat("box.cfg{}\n", 100)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "tt.yaml"), []byte("env:\n"),
+		0644))
+
+	// Modification times are clamped to get the same archives for the copied bundle.
+	sourceDateEpoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	packCtxs := []*PackCtx{}
+	for _, packageType := range []string{Tgz, Zip, Deb} {
+		packCtxs = append(packCtxs, &PackCtx{Type: packageType, SourceDir: sourceDir,
+			Archive:         ArchiveCtx{CompressionLevel: DefaultCompressionLevel},
+			sourceDateEpoch: &sourceDateEpoch})
+	}
+	output := bytes.Buffer{}
+	require.NoError(t, PrintSize(&cmdcontext.CmdCtx{}, packCtxs, &config.CliOpts{}, &output))
+
+	// The sizes are the same as of the archives written to the buffer.
+	tgz, zip, payload := bytes.Buffer{}, bytes.Buffer{}, bytes.Buffer{}
+	require.NoError(t, writeCompressedTar(sourceDir, &tgz, packCtxs[0]))
+	require.NoError(t, writeZip(sourceDir, &zip, packCtxs[1]))
+	require.NoError(t, writeTgz(sourceDir, &payload, packCtxs[2], gzip.DefaultCompression))
+	assert.Equal(t, fmt.Sprintf("Uncompressed: 1005 bytes\ntgz: %d bytes\nzip: %d bytes\n"+
+		"deb: %d bytes (estimated by payload)\n", tgz.Len(), zip.Len(), payload.Len()),
+		output.String())
+}
+
+func Test_getCompressedSizeUnsupported(t *testing.T) {
+	_, _, err := getCompressedSize(&PackCtx{Type: Docker}, t.TempDir())
+	assert.EqualError(t, err, "size printing is not supported for docker packages")
+}
@@ -0,0 +1,102 @@
+package pack
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// ProgressReporter receives notifications about packing progress.
+type ProgressReporter interface {
+	// FileAdded is called when a file is added to the result package.
+	FileAdded(path string, size int64)
+	// Done is called when the package is created. total is a summary size
+	// of the added files.
+	Done(total int64)
+}
+
+// NopProgressReporter is a progress reporter that ignores all notifications.
+type NopProgressReporter struct {
+}
+
+// FileAdded does nothing.
+func (NopProgressReporter) FileAdded(path string, size int64) {
+}
+
+// Done does nothing.
+func (NopProgressReporter) Done(total int64) {
+}
+
+// terminalProgressReporter prints the packing progress line to the terminal.
+type terminalProgressReporter struct {
+	writer io.Writer
+	// files is a count of added files.
+	files int
+	// size is a summary size of added files.
+	size int64
+	// lastPrint is a time of the last progress line update.
+	lastPrint time.Time
+}
+
+// progressPrintInterval is a minimum interval between progress line updates.
+const progressPrintInterval = 100 * time.Millisecond
+
+// NewTerminalProgressReporter creates a progress reporter that updates a progress line
+// in the terminal attached to the writer.
+func NewTerminalProgressReporter(writer io.Writer) ProgressReporter {
+	return &terminalProgressReporter{writer: writer}
+}
+
+// FileAdded updates the progress line.
+func (reporter *terminalProgressReporter) FileAdded(path string, size int64) {
+	reporter.files++
+	reporter.size += size
+	if time.Since(reporter.lastPrint) < progressPrintInterval {
+		return
+	}
+	reporter.lastPrint = time.Now()
+	fmt.Fprintf(reporter.writer, "\r\033[KPacking: %d files, %d bytes", reporter.files,
+		reporter.size)
+}
+
+// Done prints the final progress line.
+func (reporter *terminalProgressReporter) Done(total int64) {
+	fmt.Fprintf(reporter.writer, "\r\033[KPacked: %d files, %d bytes\n", reporter.files, total)
+	reporter.files = 0
+	reporter.size = 0
+	reporter.lastPrint = time.Time{}
+}
+
+// progressTracker forwards the packing progress to the reporter and counts
+// the summary size of added files. Nil tracker ignores all notifications.
+type progressTracker struct {
+	reporter ProgressReporter
+	total    int64
+}
+
+// newProgressTracker creates a tracker for the passed reporter. Nil is returned
+// if there is no reporter.
+func newProgressTracker(reporter ProgressReporter) *progressTracker {
+	if reporter == nil {
+		return nil
+	}
+	return &progressTracker{reporter: reporter}
+}
+
+// fileAdded reports the added file.
+func (tracker *progressTracker) fileAdded(path string, size int64) {
+	if tracker == nil {
+		return
+	}
+	tracker.total += size
+	tracker.reporter.FileAdded(path, size)
+}
+
+// done reports the package is created.
+func (tracker *progressTracker) done() {
+	if tracker == nil {
+		return
+	}
+	tracker.reporter.Done(tracker.total)
+	tracker.total = 0
+}
@@ -0,0 +1,61 @@
+package pack
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProgressReporter struct {
+	files map[string]int64
+	total int64
+	done  int
+}
+
+func (reporter *testProgressReporter) FileAdded(path string, size int64) {
+	reporter.files[path] = size
+}
+
+func (reporter *testProgressReporter) Done(total int64) {
+	reporter.total = total
+	reporter.done++
+}
+
+func TestProgressReporter(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(srcDir, "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "app", "init.lua"),
+		[]byte("return 1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "tt.yaml"),
+		[]byte("env: {}\n"), 0644))
+
+	reporter := &testProgressReporter{files: map[string]int64{}}
+	packCtx := PackCtx{progress: newProgressTracker(reporter)}
+	require.NoError(t, writeTgzArchive(srcDir, filepath.Join(t.TempDir(), "bundle.tar.gz"),
+		packCtx, DefaultCompressionLevel))
+	packCtx.progress.done()
+
+	assert.Equal(t, map[string]int64{"app/init.lua": 8, "tt.yaml": 8}, reporter.files)
+	assert.Equal(t, int64(16), reporter.total)
+	assert.Equal(t, 1, reporter.done)
+
+	// Nil tracker ignores notifications.
+	var tracker *progressTracker
+	tracker.fileAdded("file", 1)
+	tracker.done()
+	assert.Nil(t, newProgressTracker(nil))
+}
+
+func TestTerminalProgressReporter(t *testing.T) {
+	var out bytes.Buffer
+	reporter := NewTerminalProgressReporter(&out)
+	reporter.FileAdded("app/init.lua", 8)
+	reporter.FileAdded("tt.yaml", 8)
+	reporter.Done(16)
+	assert.Equal(t, "\r\033[KPacking: 1 files, 8 bytes\r\033[KPacked: 2 files, 16 bytes\n",
+		out.String())
+}
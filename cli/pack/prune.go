@@ -0,0 +1,48 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/apex/log"
+)
+
+// pruneDir removes the empty subdirectories of the directory recursively and returns
+// true if the directory has no entries left. The symlinks are kept as files, the
+// subdirectories for which keep returns true are kept as is.
+func pruneDir(dir string, keep func(path string) bool) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	left := len(entries)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subDir := filepath.Join(dir, entry.Name())
+		if keep != nil && keep(subDir) {
+			continue
+		}
+		isEmpty, err := pruneDir(subDir, keep)
+		if err != nil {
+			return false, err
+		}
+		if isEmpty {
+			log.Debugf("Removing empty directory %s", subDir)
+			if err = os.Remove(subDir); err != nil {
+				return false, err
+			}
+			left--
+		}
+	}
+	return left == 0, nil
+}
+
+// pruneEmptyDirs removes the directories containing no files from the bundle. The bundle
+// root and the directories for which keep returns true are kept. All the directories are
+// checked if keep is nil.
+func pruneEmptyDirs(bundlePath string, keep func(path string) bool) error {
+	_, err := pruneDir(bundlePath, keep)
+	return err
+}
@@ -0,0 +1,41 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+)
+
+func Test_pruneEmptyDirs(t *testing.T) {
+	bundleDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(bundleDir, []string{
+		"app/lib",
+		"app/var/log",
+		"app/var/run",
+		"bin",
+		"links",
+		"modules/empty/nested",
+	}))
+	require.NoError(t, test_helpers.CreateFiles(bundleDir, []string{
+		"app/init.lua", "app/lib/mod.lua"}))
+	require.NoError(t, os.Symlink("../bin", filepath.Join(bundleDir, "links", "bin")))
+
+	require.NoError(t, pruneEmptyDirs(bundleDir, nil))
+	assert.FileExists(t, filepath.Join(bundleDir, "app", "init.lua"))
+	assert.FileExists(t, filepath.Join(bundleDir, "app", "lib", "mod.lua"))
+	// The symlink is kept even if its target is removed.
+	_, err := os.Lstat(filepath.Join(bundleDir, "links", "bin"))
+	assert.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(bundleDir, "app", "var"))
+	assert.NoDirExists(t, filepath.Join(bundleDir, "bin"))
+	assert.NoDirExists(t, filepath.Join(bundleDir, "modules"))
+
+	// The bundle root is kept.
+	emptyDir := t.TempDir()
+	require.NoError(t, pruneEmptyDirs(emptyDir, nil))
+	assert.DirExists(t, emptyDir)
+}
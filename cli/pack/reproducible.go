@@ -0,0 +1,58 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// sourceDateEpochEnv is an environment variable used for reproducible builds.
+// See https://reproducible-builds.org/specs/source-date-epoch/.
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// getSourceDateEpoch returns the time set in SOURCE_DATE_EPOCH environment variable.
+// Nil is returned if the variable is not set.
+func getSourceDateEpoch() (*time.Time, error) {
+	sourceDateEpoch := os.Getenv(sourceDateEpochEnv)
+	if sourceDateEpoch == "" {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(sourceDateEpoch, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %s", sourceDateEpochEnv,
+			sourceDateEpoch, err)
+	}
+	epoch := time.Unix(seconds, 0).UTC()
+	return &epoch, nil
+}
+
+// clampModTime returns the modification time not later than the epoch.
+func clampModTime(modTime, epoch time.Time) time.Time {
+	if modTime.After(epoch) {
+		return epoch
+	}
+	return modTime
+}
+
+// clampModTimes clamps modification times of all files in the directory
+// including symlinks to the epoch.
+func clampModTimes(dirPath string, epoch time.Time) error {
+	return filepath.Walk(dirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fileInfo.ModTime().After(epoch) {
+			return nil
+		}
+		times := []unix.Timeval{unix.NsecToTimeval(epoch.UnixNano()),
+			unix.NsecToTimeval(epoch.UnixNano())}
+		if err := unix.Lutimes(filePath, times); err != nil {
+			return fmt.Errorf("failed to set modification time of %q: %s", filePath, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,50 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getSourceDateEpoch(t *testing.T) {
+	t.Setenv(sourceDateEpochEnv, "")
+	epoch, err := getSourceDateEpoch()
+	require.NoError(t, err)
+	assert.Nil(t, epoch)
+
+	t.Setenv(sourceDateEpochEnv, "1700000000")
+	epoch, err = getSourceDateEpoch()
+	require.NoError(t, err)
+	require.NotNil(t, epoch)
+	assert.Equal(t, "2023-11-14T22:13:20Z", epoch.Format(time.RFC3339))
+
+	t.Setenv(sourceDateEpochEnv, "yesterday")
+	_, err = getSourceDateEpoch()
+	assert.ErrorContains(t, err, `invalid SOURCE_DATE_EPOCH value "yesterday"`)
+}
+
+func Test_clampModTimes(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old")
+	newFile := filepath.Join(dir, "new")
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.WriteFile(oldFile, []byte{}, 0644))
+	require.NoError(t, os.WriteFile(newFile, []byte{}, 0644))
+	require.NoError(t, os.Symlink("new", link))
+
+	epoch := time.Unix(1700000000, 0)
+	oldTime := time.Unix(1600000000, 0)
+	require.NoError(t, os.Chtimes(oldFile, oldTime, oldTime))
+
+	require.NoError(t, clampModTimes(dir, epoch))
+	for path, expected := range map[string]time.Time{
+		oldFile: oldTime, newFile: epoch, link: epoch} {
+		info, err := os.Lstat(path)
+		require.NoError(t, err)
+		assert.True(t, expected.Equal(info.ModTime()), "%s: %s", path, info.ModTime())
+	}
+}
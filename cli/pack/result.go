@@ -0,0 +1,73 @@
+package pack
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/util"
+)
+
+const (
+	// OutputText is a human-readable output format of the pack command.
+	OutputText = "text"
+	// OutputJSON is a machine-readable output format of the pack command.
+	OutputJSON = "json"
+)
+
+// PackResult describes the created package.
+type PackResult struct {
+	// Path is a path of the package file. It is a docker image name for the docker
+	// image type.
+	Path string `json:"path"`
+	// Size is a size of the package file.
+	Size int64 `json:"size,omitempty"`
+	// SHA256 is a hex-encoded SHA256 checksum of the package file.
+	SHA256 string `json:"sha256,omitempty"`
+	// Type is a package type.
+	Type string `json:"type"`
+	// Name is a package name.
+	Name string `json:"name"`
+	// Version is a package version.
+	Version string `json:"version"`
+}
+
+// PackResults describes all packages created by one pack command.
+type PackResults struct {
+	// Packages are the created packages in the order of the requested types.
+	Packages []PackResult `json:"packages"`
+}
+
+// GetPackResult returns the description of the package created with the pack context.
+func GetPackResult(packCtx *PackCtx, opts *config.CliOpts) (PackResult, error) {
+	result := PackResult{
+		Path:    packCtx.artifactPath,
+		Type:    packCtx.Type,
+		Name:    packCtx.Name,
+		Version: getVersion(packCtx, opts, defaultVersion),
+	}
+	if packCtx.Type == Docker {
+		return result, nil
+	}
+
+	stat, err := os.Stat(packCtx.artifactPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to get package file info: %s", err)
+	}
+	result.Size = stat.Size()
+	if result.SHA256, err = util.FileSHA256Hex(packCtx.artifactPath); err != nil {
+		return result, fmt.Errorf("failed to compute checksum of %q: %s",
+			packCtx.artifactPath, err)
+	}
+	return result, nil
+}
+
+// getCmdStdout returns the writer for the output of the commands run while packing.
+// Stdout is kept for the pack result in JSON output mode, so stderr is used.
+func getCmdStdout(packCtx *PackCtx) io.Writer {
+	if packCtx.OutputFormat == OutputJSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
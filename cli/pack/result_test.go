@@ -0,0 +1,57 @@
+package pack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func TestGetPackResult(t *testing.T) {
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	packagePath := filepath.Join(t.TempDir(), "bundle-1.0.0.x86_64.tar.gz")
+	require.NoError(t, os.WriteFile(packagePath, []byte("data"), 0644))
+
+	packCtx := PackCtx{Type: Tgz, Name: "bundle", Version: "1.0.0", artifactPath: packagePath}
+	result, err := GetPackResult(&packCtx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, PackResult{
+		Path:    packagePath,
+		Size:    4,
+		SHA256:  "3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7",
+		Type:    Tgz,
+		Name:    "bundle",
+		Version: "1.0.0",
+	}, result)
+
+	// Docker image is not a file.
+	packCtx = PackCtx{Type: Docker, Name: "bundle", Version: "1.0.0",
+		artifactPath: "bundle:1.0.0"}
+	result, err = GetPackResult(&packCtx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, PackResult{Path: "bundle:1.0.0", Type: Docker, Name: "bundle",
+		Version: "1.0.0"}, result)
+
+	packCtx = PackCtx{Type: Zip, artifactPath: filepath.Join(t.TempDir(), "missing.zip")}
+	_, err = GetPackResult(&packCtx, opts)
+	assert.ErrorContains(t, err, "failed to get package file info")
+}
+
+func TestPackResultsJSON(t *testing.T) {
+	results := PackResults{Packages: []PackResult{
+		{Path: "bundle.tar.gz", Size: 4, SHA256: "abc", Type: Tgz, Name: "bundle",
+			Version: "1.0.0"},
+		{Path: "bundle:1.0.0", Type: Docker, Name: "bundle", Version: "1.0.0"},
+	}}
+	data, err := json.Marshal(results)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"packages": [
+		{"path": "bundle.tar.gz", "size": 4, "sha256": "abc", "type": "tgz",
+			"name": "bundle", "version": "1.0.0"},
+		{"path": "bundle:1.0.0", "type": "docker", "name": "bundle", "version": "1.0.0"}
+	]}`, string(data))
+}
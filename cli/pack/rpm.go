@@ -5,12 +5,14 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/otiai10/copy"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/util"
+	"github.com/tarantool/tt/cli/version"
 )
 
 // rpmPacker is a structure that implements Packer interface
@@ -28,10 +30,11 @@ func (packer *rpmPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	}
 
 	// Create a package directory, where it will be built.
-	packageDir, err := os.MkdirTemp("", "")
+	packageDir, err := os.MkdirTemp(getTmpDir(packCtx), "")
 	if err != nil {
 		return err
 	}
+	packCtx.operation.addOutput(packageDir)
 	defer func() {
 		err := os.RemoveAll(packageDir)
 		if err != nil {
@@ -57,8 +60,8 @@ func (packer *rpmPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 
 	bundleName := packCtx.Name
 
-	packagingEnvInstallPath := filepath.Join(packageDir, "usr", "share", "tarantool",
-		bundleName)
+	installPrefix := getInstallPrefix(packCtx)
+	packagingEnvInstallPath := filepath.Join(packageDir, installPrefix, bundleName)
 	if opts.Env.InstancesEnabled == "." || packCtx.CartridgeCompat {
 		packagingEnvInstallPath = filepath.Dir(packagingEnvInstallPath)
 	}
@@ -71,7 +74,7 @@ func (packer *rpmPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	fileSystem := os.DirFS(packagingEnvInstallPath)
 	fs.WalkDir(fileSystem, ".", updatePermissions(packagingEnvInstallPath))
 
-	rpmSuffix, err := getRPMSuffix()
+	rpmSuffix, err := getRPMSuffix(packCtx)
 	if err != nil {
 		return err
 	}
@@ -79,9 +82,16 @@ func (packer *rpmPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 	if err != nil {
 		return err
 	}
+	if resPackagePath, err = getPackageFilePath(packCtx, resPackagePath); err != nil {
+		return err
+	}
+	if err = lockArtifact(packCtx, resPackagePath); err != nil {
+		return err
+	}
 
-	envSystemPath := filepath.Join("/", defaultEnvPrefix, bundleName)
-	err = initSystemdDir(packCtx, packageDir, envSystemPath)
+	envSystemPath := filepath.Join("/", installPrefix, bundleName)
+	err = initSystemdDir(packCtx, packageDir, envSystemPath,
+		getVersion(packCtx, opts, defaultVersion))
 	if err != nil {
 		return err
 	}
@@ -90,23 +100,59 @@ func (packer *rpmPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
 		return err
 	}
 
-	err = packRpm(cmdCtx, packCtx, opts, packageDir, resPackagePath)
-
+	err = writePackageFile(packCtx, resPackagePath, func(tmpPath string) error {
+		return packRpm(cmdCtx, packCtx, opts, packageDir, tmpPath)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create RPM package: %s", err)
 	}
 
+	packCtx.progress.done()
+	packCtx.artifactPath = resPackagePath
 	log.Infof("Created result RPM package: %s", resPackagePath)
 
+	if packCtx.WithChecksum {
+		return writeChecksumFile(resPackagePath)
+	}
 	return nil
 }
 
+// getRpmRelease returns the release of the RPM package.
+func getRpmRelease(packCtx *PackCtx) string {
+	release := packCtx.RpmDeb.RpmRelease
+	if release == "" {
+		release = defaultRpmRelease
+	}
+	if _, build := splitRpmVersion(packCtx.Version); build != "" {
+		release += "." + build
+	}
+	return release
+}
+
+// splitRpmVersion splits the build metadata of the package version, e.g. "dirty" of
+// "1.2.0+dirty". The metadata is not allowed in the RPM version, so it is moved into
+// the RPM release.
+func splitRpmVersion(packageVersion string) (string, string) {
+	packageVersion, build, _ := strings.Cut(packageVersion, "+")
+	return packageVersion, build
+}
+
+// checkRpmVersion checks the package version is valid for the RPM package.
+func checkRpmVersion(packageVersion string) error {
+	packageVersion, _ = splitRpmVersion(packageVersion)
+	_, err := version.Parse(packageVersion)
+	return err
+}
+
 // getRPMSuffix returns suffix for an RPM package.
-func getRPMSuffix() (string, error) {
-	arch, err := util.GetArch()
-	if err != nil {
-		return "", err
+func getRPMSuffix(packCtx *PackCtx) (string, error) {
+	arch := packCtx.RpmDeb.RpmArch
+	if arch == "" {
+		var err error
+		if arch, err = util.GetArch(); err != nil {
+			return "", err
+		}
 	}
-	debSuffix := "-1" + "." + arch + ".rpm"
-	return debSuffix, nil
+	rpmSuffix := "-" + getRpmRelease(packCtx) + "." + arch + ".rpm"
+	return rpmSuffix, nil
 }
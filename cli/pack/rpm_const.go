@@ -6,6 +6,10 @@ const (
 	defaultFileLang   = ""
 	defaultFileLinkTo = ""
 	emptyDigest       = ""
+	defaultRpmRelease = "1"
+	// rpmPackerPrefix precedes the tt version in the RPMVERSION tag, which records
+	// the tool built the package.
+	rpmPackerPrefix = "tt "
 
 	headerSignatures = 62
 	headerImmutable  = 63
@@ -15,6 +19,9 @@ const (
 	// XXX
 	fileFlag = 1 << 4
 	dirFlag  = 0
+	// configFlag marks the config file. Together with fileFlag, which is
+	// RPMFILE_NOREPLACE, it is %config(noreplace).
+	configFlag = 1 << 0
 
 	rpmTypeNull        = 0
 	rpmTypeChar        = 1
@@ -31,6 +38,8 @@ const (
 	signatureTagMD5         = 1004
 	signatureTagPayloadSize = 1007
 	signatureTagSHA1        = 269
+	signatureTagRSA         = 268
+	signatureTagPGP         = 1002
 
 	tagName              = 1000
 	tagVersion           = 1001
@@ -43,7 +52,9 @@ const (
 	tagOs                = 1021
 	tagArch              = 1022
 	tagLicense           = 1014
+	tagPackager          = 1015
 	tagGroup             = 1016
+	tagURL               = 1020
 	tagPayloadFormat     = 1124
 	tagPayloadCompressor = 1125
 	tagPayloadFlags      = 1126
@@ -51,6 +62,10 @@ const (
 	tagPostin            = 1024
 	tagPreinProg         = 1085
 	tagPostinProg        = 1086
+	tagPreun             = 1025
+	tagPostun            = 1026
+	tagPreunProg         = 1087
+	tagPostunProg        = 1088
 	tagDirNames          = 1118
 	tagBaseNames         = 1117
 	tagDirIndexes        = 1116
@@ -70,6 +85,15 @@ const (
 	tagRequireFlags      = 1048
 	tagRequireName       = 1049
 	tagRequireVersion    = 1050
+	tagProvideName       = 1047
+	tagProvideFlags      = 1112
+	tagProvideVersion    = 1113
+	tagConflictFlags     = 1053
+	tagConflictName      = 1054
+	tagConflictVersion   = 1055
+	tagChangelogTime     = 1080
+	tagChangelogName     = 1081
+	tagChangelogText     = 1082
 	tagPayloadDigest     = 5092
 	tagPayloadDigestAlgo = 5093
 
@@ -57,6 +57,13 @@ func getRPMRelation(relation string) int32 {
 
 // addDependenciesRPM writes all passed dependencies to the special rpm header.
 func addDependenciesRPM(rpmHeader *rpmTagSetType, deps PackDependencies) {
+	addRelationsRPM(rpmHeader, deps, tagRequireName, tagRequireFlags, tagRequireVersion)
+}
+
+// addRelationsRPM adds parsed package relations to the rpm header using the passed
+// name, flags and version tags.
+func addRelationsRPM(rpmHeader *rpmTagSetType, deps PackDependencies,
+	nameTag, flagsTag, versionTag int) {
 	if len(deps) == 0 {
 		return
 	}
@@ -80,11 +87,11 @@ func addDependenciesRPM(rpmHeader *rpmTagSetType, deps PackDependencies) {
 	}
 
 	rpmHeader.addTags([]rpmTagType{
-		{ID: tagRequireName, Type: rpmTypeStringArray,
+		{ID: nameTag, Type: rpmTypeStringArray,
 			Value: names},
-		{ID: tagRequireFlags, Type: rpmTypeInt32,
+		{ID: flagsTag, Type: rpmTypeInt32,
 			Value: relations},
-		{ID: tagRequireVersion, Type: rpmTypeStringArray,
+		{ID: versionTag, Type: rpmTypeStringArray,
 			Value: versions},
 	}...)
 }
@@ -92,33 +99,45 @@ func addDependenciesRPM(rpmHeader *rpmTagSetType, deps PackDependencies) {
 //go:embed templates/rpm_preinst.sh
 var rpmPreInstScriptContent string
 
-// addPreAndPostInstallScriptsRPM writes passed paths of pre-install
-// and post-install scripts to the rpm header.
-func addPreAndPostInstallScriptsRPM(rpmHeader *rpmTagSetType,
-	preInst string, postInst string) error {
-	preInstScript := rpmPreInstScriptContent
-	if preInst != "" {
-		userPreInstBytes, err := os.ReadFile(preInst)
-		if err != nil {
-			return fmt.Errorf("error reading preinst file %q: %s", preInst, err)
-		}
-		preInstScript += "\n" + string(userPreInstBytes)
+// addPreAndPostInstallScriptsRPM writes the rendered user pre-install and post-install
+// scripts to the rpm header after the built-in ones. The scripts are run by the
+// scriptlet interpreter.
+func addPreAndPostInstallScriptsRPM(rpmHeader *rpmTagSetType, rpmDeb *RpmDebCtx) {
+	preInstScript := getRpmPreInstScript(rpmDeb)
+	if rpmDeb.PreInst != "" {
+		preInstScript += "\n" + rpmDeb.preInstScript
 	}
 
 	postInstScript := postInstScriptContent
-	if preInst != "" {
-		userPostInstBytes, err := os.ReadFile(postInst)
-		if err != nil {
-			return fmt.Errorf("error reading postinst file %q: %s", postInst, err)
-		}
-		postInstScript += "\n" + string(userPostInstBytes)
+	if rpmDeb.PostInst != "" {
+		postInstScript += "\n" + rpmDeb.postInstScript
 	}
 
+	prog := getRpmScriptletProg(rpmDeb)
 	rpmHeader.addTags([]rpmTagType{
+		{ID: tagPreinProg, Type: rpmTypeString, Value: prog},
+		{ID: tagPostinProg, Type: rpmTypeString, Value: prog},
 		{ID: tagPrein, Type: rpmTypeString, Value: preInstScript},
 		{ID: tagPostin, Type: rpmTypeString, Value: postInstScript},
 	}...)
-	return nil
+}
+
+// addRemoveScriptsRPM writes the rendered user pre-remove and post-remove scripts
+// to the rpm header. The scripts are run by the scriptlet interpreter.
+func addRemoveScriptsRPM(rpmHeader *rpmTagSetType, rpmDeb *RpmDebCtx) {
+	prog := getRpmScriptletProg(rpmDeb)
+	if rpmDeb.PreRm != "" {
+		rpmHeader.addTags([]rpmTagType{
+			{ID: tagPreunProg, Type: rpmTypeString, Value: prog},
+			{ID: tagPreun, Type: rpmTypeString, Value: rpmDeb.preRmScript},
+		}...)
+	}
+	if rpmDeb.PostRm != "" {
+		rpmHeader.addTags([]rpmTagType{
+			{ID: tagPostunProg, Type: rpmTypeString, Value: prog},
+			{ID: tagPostun, Type: rpmTypeString, Value: rpmDeb.postRmScript},
+		}...)
+	}
 }
 
 // genRpmHeader generates rpm headers.
@@ -140,12 +159,13 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath, packageFilesDi
 	payloadSize := cpioFileInfo.Size()
 
 	// Generate fileinfo.
-	filesInfo, err := getFilesInfo(relPaths, packageFilesDir, packCtx.RpmDeb.pkgFilesInfo)
+	filesInfo, err := getFilesInfo(relPaths, packageFilesDir, &packCtx.RpmDeb,
+		packCtx.sourceDateEpoch != nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get files info: %s", err)
 	}
 
-	versionString := getVersion(packCtx, opts, defaultVersion)
+	versionString, _ := splitRpmVersion(getVersion(packCtx, opts, defaultVersion))
 
 	ver, err := version.Parse(versionString)
 	if err != nil {
@@ -161,18 +181,22 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath, packageFilesDi
 		strconv.FormatUint(ver.Minor, 10),
 		strconv.FormatUint(ver.Patch, 10),
 	}, ".")
-	releaseStr := strconv.FormatUint(ver.Release.Num, 10)
-	arch := getArch()
+	releaseStr := getRpmRelease(packCtx)
+	arch := getArch(packCtx)
+	description := getPackageDescription(packCtx)
+	summary, _, _ := strings.Cut(description, "\n")
 
 	rpmHeader.addTags([]rpmTagType{
 		{ID: tagName, Type: rpmTypeString, Value: name},
 		{ID: tagVersion, Type: rpmTypeString, Value: versionStr},
 		{ID: tagRelease, Type: rpmTypeString, Value: releaseStr},
-		{ID: tagSummary, Type: rpmTypeString, Value: ""},
-		{ID: tagDescription, Type: rpmTypeString, Value: ""},
+		{ID: tagSummary, Type: rpmTypeString, Value: summary},
+		{ID: tagDescription, Type: rpmTypeString, Value: description},
 
-		{ID: tagLicense, Type: rpmTypeString, Value: "N/A"},
+		{ID: tagLicense, Type: rpmTypeString, Value: getRpmLicense(packCtx)},
+		{ID: tagPackager, Type: rpmTypeString, Value: getPackageMaintainer(packCtx)},
 		{ID: tagGroup, Type: rpmTypeString, Value: "None"},
+		{ID: tagRpmVersion, Type: rpmTypeString, Value: rpmPackerPrefix + getPackerVersion()},
 		{ID: tagOs, Type: rpmTypeString, Value: "linux"},
 		{ID: tagArch, Type: rpmTypeString, Value: arch},
 
@@ -180,9 +204,6 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath, packageFilesDi
 		{ID: tagPayloadCompressor, Type: rpmTypeString, Value: "gzip"},
 		{ID: tagPayloadFlags, Type: rpmTypeString, Value: "5"},
 
-		{ID: tagPreinProg, Type: rpmTypeString, Value: "/bin/sh"},
-		{ID: tagPostinProg, Type: rpmTypeString, Value: "/bin/sh"},
-
 		{ID: tagDirNames, Type: rpmTypeStringArray, Value: filesInfo.DirNames},
 		{ID: tagBaseNames, Type: rpmTypeStringArray, Value: filesInfo.BaseNames},
 		{ID: tagDirIndexes, Type: rpmTypeInt32, Value: filesInfo.DirIndexes},
@@ -205,25 +226,45 @@ func genRpmHeader(relPaths []string, cpioPath, compresedCpioPath, packageFilesDi
 		{ID: tagPayloadDigestAlgo, Type: rpmTypeInt32, Value: []int32{int32(payloadDigestAlgo)}},
 	}...)
 
+	if packCtx.RpmDeb.Homepage != "" {
+		rpmHeader.addTags(rpmTagType{ID: tagURL, Type: rpmTypeString,
+			Value: packCtx.RpmDeb.Homepage})
+	}
+
+	if packCtx.RpmDeb.RpmEpoch > 0 {
+		rpmHeader.addTags(rpmTagType{ID: tagEpoch, Type: rpmTypeInt32,
+			Value: []int32{int32(packCtx.RpmDeb.RpmEpoch)}})
+	}
+
 	deps, err := parseAllDependencies(cmdCtx, packCtx)
 	if err != nil {
 		return nil, err
 	}
 
 	addDependenciesRPM(&rpmHeader, deps)
-	err = addPreAndPostInstallScriptsRPM(&rpmHeader, packCtx.RpmDeb.PreInst,
-		packCtx.RpmDeb.PostInst)
 
-	return rpmHeader, err
+	conflicts, provides, err := parsePackageRelations(packCtx)
+	if err != nil {
+		return nil, err
+	}
+	addRelationsRPM(&rpmHeader, conflicts, tagConflictName, tagConflictFlags,
+		tagConflictVersion)
+	addRelationsRPM(&rpmHeader, provides, tagProvideName, tagProvideFlags, tagProvideVersion)
+	addChangelogRPM(&rpmHeader, packCtx.RpmDeb.rpmChangelog)
+	addPreAndPostInstallScriptsRPM(&rpmHeader, &packCtx.RpmDeb)
+	addRemoveScriptsRPM(&rpmHeader, &packCtx.RpmDeb)
+
+	return rpmHeader, nil
 }
 
 // getFilesInfo returns the meta information about all items inside the passed
-// directory needed for packing it into rpm headers.
+// directory needed for packing it into rpm headers. If reproducible is set,
+// inodes and devices do not depend on the build file system.
 func getFilesInfo(relPaths []string, dirPath string,
-	pkgFiles map[string]packFileInfo) (filesInfo, error) {
+	rpmDeb *RpmDebCtx, reproducible bool) (filesInfo, error) {
 	info := filesInfo{}
 
-	for _, relPath := range relPaths {
+	for i, relPath := range relPaths {
 		fullFilePath := filepath.Join(dirPath, relPath)
 		fileInfo, err := os.Lstat(fullFilePath)
 		if err != nil {
@@ -231,7 +272,11 @@ func getFilesInfo(relPaths []string, dirPath string,
 		}
 
 		if fileInfo.Mode().IsRegular() {
-			info.FileFlags = append(info.FileFlags, fileFlag)
+			if rpmDeb.isRpmConfigFile(relPath) {
+				info.FileFlags = append(info.FileFlags, fileFlag|configFlag)
+			} else {
+				info.FileFlags = append(info.FileFlags, fileFlag)
+			}
 
 			fileDigest, err := util.FileMD5Hex(fullFilePath)
 			if err != nil {
@@ -252,13 +297,9 @@ func getFilesInfo(relPaths []string, dirPath string,
 		info.BaseNames = append(info.BaseNames, filepath.Base(relPath))
 		info.FileMtimes = append(info.FileMtimes, int32(fileInfo.ModTime().Unix()))
 
-		if packFileInfo, found := pkgFiles[relPath]; found {
-			info.FileUserNames = append(info.FileUserNames, packFileInfo.owner)
-			info.FileGroupNames = append(info.FileGroupNames, packFileInfo.group)
-		} else {
-			info.FileUserNames = append(info.FileUserNames, defaultFileUser)
-			info.FileGroupNames = append(info.FileGroupNames, defaultFileGroup)
-		}
+		packFileInfo := rpmDeb.getPackFileInfo(relPath, fileInfo.Mode())
+		info.FileUserNames = append(info.FileUserNames, packFileInfo.owner)
+		info.FileGroupNames = append(info.FileGroupNames, packFileInfo.group)
 		info.FileLangs = append(info.FileLangs, defaultFileLang)
 
 		sysFileInfo, ok := fileInfo.Sys().(*syscall.Stat_t)
@@ -284,9 +325,15 @@ func getFilesInfo(relPaths []string, dirPath string,
 			info.FileLinkTos = append(info.FileLinkTos, defaultFileLinkTo)
 		}
 		info.FileSizes = append(info.FileSizes, int32(sysFileInfo.Size))
-		info.FileModes = append(info.FileModes, int16(sysFileInfo.Mode))
-		info.FileInodes = append(info.FileInodes, int32(sysFileInfo.Ino))
-		info.FileDevices = append(info.FileDevices, int32(sysFileInfo.Dev))
+		info.FileModes = append(info.FileModes,
+			int16(sysFileInfo.Mode&^uint32(os.ModePerm)|uint32(packFileInfo.perm)))
+		if reproducible {
+			info.FileInodes = append(info.FileInodes, int32(i+1))
+			info.FileDevices = append(info.FileDevices, 1)
+		} else {
+			info.FileInodes = append(info.FileInodes, int32(sysFileInfo.Ino))
+			info.FileDevices = append(info.FileDevices, int32(sysFileInfo.Dev))
+		}
 		info.FileRdevs = append(info.FileRdevs, int16(sysFileInfo.Rdev))
 	}
 
@@ -308,8 +355,11 @@ func addDirAndGetIndex(dirNames *[]string, fileDir string) int {
 }
 
 // getArch returns the architecture for an RPM package.
-// Depends on runtime.GOARCH constant.
-func getArch() string {
+// Depends on runtime.GOARCH constant if the architecture is not set.
+func getArch(packCtx *PackCtx) string {
+	if packCtx.RpmDeb.RpmArch != "" {
+		return packCtx.RpmDeb.RpmArch
+	}
 	switch runtime.GOARCH {
 	case "amd64":
 		return "x86_64"
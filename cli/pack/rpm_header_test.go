@@ -0,0 +1,134 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_addRelationsRPM(t *testing.T) {
+	rpmHeader := rpmTagSetType{}
+	addRelationsRPM(&rpmHeader, nil, tagConflictName, tagConflictFlags, tagConflictVersion)
+	assert.Empty(t, rpmHeader)
+
+	addRelationsRPM(&rpmHeader, PackDependencies{
+		{Name: "old_app", Relations: []DepRelation{{Relation: "<", Version: "2.0"}}},
+		{Name: "legacy"},
+	}, tagConflictName, tagConflictFlags, tagConflictVersion)
+	assert.Equal(t, rpmTagSetType{
+		{ID: tagConflictName, Type: rpmTypeStringArray, Value: []string{"old_app", "legacy"}},
+		{ID: tagConflictFlags, Type: rpmTypeInt32, Value: []int32{rpmSenseLess, 0}},
+		{ID: tagConflictVersion, Type: rpmTypeStringArray, Value: []string{"2.0", ""}},
+	}, rpmHeader)
+}
+
+func Test_addRemoveScriptsRPM(t *testing.T) {
+	rpmHeader := rpmTagSetType{}
+	addRemoveScriptsRPM(&rpmHeader, &RpmDebCtx{})
+	assert.Empty(t, rpmHeader)
+
+	addRemoveScriptsRPM(&rpmHeader, &RpmDebCtx{PreRm: "prerm.sh", PostRm: "postrm.sh",
+		preRmScript: "systemctl stop app", postRmScript: "rm -rf /var/lib/app"})
+	assert.Equal(t, rpmTagSetType{
+		{ID: tagPreunProg, Type: rpmTypeString, Value: "/bin/sh"},
+		{ID: tagPreun, Type: rpmTypeString, Value: "systemctl stop app"},
+		{ID: tagPostunProg, Type: rpmTypeString, Value: "/bin/sh"},
+		{ID: tagPostun, Type: rpmTypeString, Value: "rm -rf /var/lib/app"},
+	}, rpmHeader)
+}
+
+func Test_addRemoveScriptsRPMLua(t *testing.T) {
+	rpmHeader := rpmTagSetType{}
+	addRemoveScriptsRPM(&rpmHeader, &RpmDebCtx{PreRm: "prerm.lua", preRmScript: "print(1)",
+		RpmScriptletInterpreter: rpmScriptletLua})
+	assert.Equal(t, rpmTagSetType{
+		{ID: tagPreunProg, Type: rpmTypeString, Value: "<lua>"},
+		{ID: tagPreun, Type: rpmTypeString, Value: "print(1)"},
+	}, rpmHeader)
+}
+
+func Test_addPreAndPostInstallScriptsRPM(t *testing.T) {
+	rpmHeader := rpmTagSetType{}
+	addPreAndPostInstallScriptsRPM(&rpmHeader, &RpmDebCtx{})
+	assert.Equal(t, rpmTagSetType{
+		{ID: tagPreinProg, Type: rpmTypeString, Value: "/bin/sh"},
+		{ID: tagPostinProg, Type: rpmTypeString, Value: "/bin/sh"},
+		{ID: tagPrein, Type: rpmTypeString, Value: rpmPreInstScriptContent},
+		{ID: tagPostin, Type: rpmTypeString, Value: ""},
+	}, rpmHeader)
+
+	rpmHeader = rpmTagSetType{}
+	addPreAndPostInstallScriptsRPM(&rpmHeader, &RpmDebCtx{PostInst: "postinst.lua",
+		postInstScript: "print(1)", RpmScriptletInterpreter: rpmScriptletLua})
+	assert.Equal(t, rpmTagSetType{
+		{ID: tagPreinProg, Type: rpmTypeString, Value: "<lua>"},
+		{ID: tagPostinProg, Type: rpmTypeString, Value: "<lua>"},
+		{ID: tagPrein, Type: rpmTypeString, Value: rpmPreInstLuaScriptContent},
+		{ID: tagPostin, Type: rpmTypeString, Value: "\nprint(1)"},
+	}, rpmHeader)
+}
+
+func Test_genRpmHeaderEpochAndRelease(t *testing.T) {
+	baseDir := t.TempDir()
+	cpioPath := filepath.Join(baseDir, "payload.cpio")
+	require.NoError(t, os.WriteFile(cpioPath, []byte("cpio"), 0644))
+
+	packCtx := PackCtx{Name: "bundle", Version: "1.2.3",
+		RpmDeb: RpmDebCtx{RpmEpoch: 2, RpmRelease: "5"}}
+	opts := config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	rpmHeader, err := genRpmHeader(nil, cpioPath, cpioPath, baseDir, &cmdcontext.CmdCtx{},
+		&packCtx, &opts)
+	require.NoError(t, err)
+
+	tags := map[int]rpmTagType{}
+	for _, tag := range rpmHeader {
+		tags[tag.ID] = tag
+	}
+	assert.Equal(t, "bundle", tags[tagName].Value)
+	assert.Equal(t, "1.2.3", tags[tagVersion].Value)
+	assert.Equal(t, "5", tags[tagRelease].Value)
+	assert.Equal(t, []int32{2}, tags[tagEpoch].Value)
+
+	// No epoch by default.
+	packCtx.RpmDeb = RpmDebCtx{}
+	rpmHeader, err = genRpmHeader(nil, cpioPath, cpioPath, baseDir, &cmdcontext.CmdCtx{},
+		&packCtx, &opts)
+	require.NoError(t, err)
+	for _, tag := range rpmHeader {
+		assert.NotEqual(t, tagEpoch, tag.ID)
+		if tag.ID == tagRelease {
+			assert.Equal(t, defaultRpmRelease, tag.Value)
+		}
+	}
+}
+
+func Test_genRpmHeaderMetadata(t *testing.T) {
+	baseDir := t.TempDir()
+	cpioPath := filepath.Join(baseDir, "payload.cpio")
+	require.NoError(t, os.WriteFile(cpioPath, []byte("cpio"), 0644))
+
+	packCtx := PackCtx{Name: "bundle", Version: "1.2.3",
+		RpmDeb: RpmDebCtx{Maintainer: "Jane Doe <jane@example.com>",
+			Homepage: "https://example.com", License: "MIT",
+			Description: "Bundle summary\nLong description"}}
+	opts := config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	rpmHeader, err := genRpmHeader(nil, cpioPath, cpioPath, baseDir, &cmdcontext.CmdCtx{},
+		&packCtx, &opts)
+	require.NoError(t, err)
+
+	tags := map[int]rpmTagType{}
+	for _, tag := range rpmHeader {
+		tags[tag.ID] = tag
+	}
+	assert.Equal(t, "Bundle summary", tags[tagSummary].Value)
+	assert.Equal(t, "Bundle summary\nLong description", tags[tagDescription].Value)
+	assert.Equal(t, "MIT", tags[tagLicense].Value)
+	assert.Equal(t, "Jane Doe <jane@example.com>", tags[tagPackager].Value)
+	assert.Equal(t, "https://example.com", tags[tagURL].Value)
+	assert.Equal(t, "tt "+getPackerVersion(), tags[tagRpmVersion].Value)
+}
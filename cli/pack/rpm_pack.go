@@ -2,12 +2,14 @@ package pack
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/tarantool/tt/cli/cmdcontext"
@@ -67,7 +69,14 @@ func packRpm(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, opts *config.CliOpts,
 	resPackagePath string) error {
 	var err error
 
-	relPaths, err := getSortedRelPaths(packageDir)
+	if packCtx.sourceDateEpoch != nil {
+		if err = clampModTimes(packageDir, *packCtx.sourceDateEpoch); err != nil {
+			return err
+		}
+	}
+
+	relPaths, err := getSortedRelPaths(packCtx.operation.context(), packageDir,
+		getInstallPrefix(packCtx), getJobsCount(packCtx))
 	if err != nil {
 		return fmt.Errorf("failed to get sorted package files list: %s", err)
 	}
@@ -75,7 +84,9 @@ func packRpm(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, opts *config.CliOpts,
 	log.Info("Creating data section")
 
 	cpioPath := filepath.Join(packageDir, "cpio")
-	if err := packCpio(relPaths, cpioPath, packageDir); err != nil {
+	err = packCpio(packCtx.operation.context(), relPaths, cpioPath, packageDir,
+		packCtx.sourceDateEpoch != nil, packCtx.progress)
+	if err != nil {
 		return fmt.Errorf("failed to pack CPIO: %s", err)
 	}
 
@@ -118,7 +129,8 @@ func packRpm(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, opts *config.CliOpts,
 	log.Info("Computing a signature")
 
 	// Compute signature.
-	signature, err := genSignature(rpmBodyFilePath, rpmHeaderFilePath, cpioPath)
+	signature, err := genSignature(rpmBodyFilePath, rpmHeaderFilePath, cpioPath,
+		packCtx.RpmDeb.SignKey)
 	if err != nil {
 		return fmt.Errorf("failed to gen RPM signature: %s", err)
 	}
@@ -164,32 +176,32 @@ func packRpm(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, opts *config.CliOpts,
 	return nil
 }
 
+// isSystemDir checks if the relative path is a system directory, which should not
+// be owned by the package. The install prefix and its parent directories are
+// treated as system directories.
+func isSystemDir(relPath, installPrefix string) bool {
+	if _, isSystem := systemDirs[relPath]; isSystem {
+		return true
+	}
+	return relPath == installPrefix || strings.HasPrefix(installPrefix, relPath+"/")
+}
+
 // getSortedRelPaths collect all paths into a slice, starting from the passed directory,
 // sorts it and returns.
-func getSortedRelPaths(srcDir string) ([]string, error) {
+func getSortedRelPaths(ctx context.Context, srcDir, installPrefix string,
+	jobs int) ([]string, error) {
 	var files []string
 
-	err := filepath.Walk(srcDir, func(filePath string, fileInfo os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		filePath, err = filepath.Rel(srcDir, filePath)
-		if err != nil {
-			return err
-		}
-
-		// System dirs shouldn't be added to the paths list.
-		if _, isSystem := systemDirs[filePath]; !isSystem {
-			files = append(files, filePath)
-		}
-
-		return nil
-	})
-
+	collectedFiles, err := collectFiles(ctx, srcDir, jobs)
 	if err != nil {
 		return nil, err
 	}
+	for _, file := range collectedFiles {
+		// System dirs shouldn't be added to the paths list.
+		if !isSystemDir(file.relPath, installPrefix) {
+			files = append(files, file.relPath)
+		}
+	}
 
 	sort.Strings(files)
 	return files, nil
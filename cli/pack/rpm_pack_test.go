@@ -0,0 +1,49 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/util"
+)
+
+func Test_getSortedRelPathsInstallPrefix(t *testing.T) {
+	packageDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(packageDir, "opt", "company", "tarantool",
+		"env", "app"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(packageDir, "usr", "lib", "systemd",
+		"system"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(packageDir, "opt", "company", "tarantool",
+		"env", "tt.yaml"), []byte{}, 0644))
+
+	relPaths, err := getSortedRelPaths(context.Background(), packageDir,
+		"opt/company/tarantool", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"opt/company/tarantool/env",
+		"opt/company/tarantool/env/app",
+		"opt/company/tarantool/env/tt.yaml",
+	}, relPaths)
+}
+
+func Test_getRPMSuffix(t *testing.T) {
+	arch, err := util.GetArch()
+	require.NoError(t, err)
+
+	suffix, err := getRPMSuffix(&PackCtx{})
+	require.NoError(t, err)
+	assert.Equal(t, "-1."+arch+".rpm", suffix)
+
+	suffix, err = getRPMSuffix(&PackCtx{RpmDeb: RpmDebCtx{RpmRelease: "3.el9"}})
+	require.NoError(t, err)
+	assert.Equal(t, "-3.el9."+arch+".rpm", suffix)
+
+	suffix, err = getRPMSuffix(&PackCtx{RpmDeb: RpmDebCtx{RpmArch: "noarch"}})
+	require.NoError(t, err)
+	assert.Equal(t, "-1.noarch.rpm", suffix)
+	assert.Equal(t, "aarch64", getArch(&PackCtx{RpmDeb: RpmDebCtx{RpmArch: "aarch64"}}))
+}
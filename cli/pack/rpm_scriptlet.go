@@ -0,0 +1,56 @@
+package pack
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+const (
+	// rpmScriptletLua is the rpm embedded Lua interpreter of the scriptlets.
+	rpmScriptletLua = "lua"
+	// rpmScriptletLuaProg is the rpm header value of the embedded Lua interpreter.
+	rpmScriptletLuaProg = "<lua>"
+	// defaultRpmScriptletInterpreter is used if the interpreter is not set.
+	defaultRpmScriptletInterpreter = "/bin/sh"
+)
+
+// rpmScriptletInterpreters are the supported interpreters of the RPM scriptlets.
+var rpmScriptletInterpreters = []string{"/bin/sh", "/bin/bash", rpmScriptletLua}
+
+//go:embed templates/rpm_preinst.lua
+var rpmPreInstLuaScriptContent string
+
+// checkRpmScriptletInterpreter checks the RPM scriptlet interpreter is supported.
+func checkRpmScriptletInterpreter(interpreter string) error {
+	if interpreter == "" {
+		return nil
+	}
+	for _, supported := range rpmScriptletInterpreters {
+		if interpreter == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown RPM scriptlet interpreter %q, supported: %s", interpreter,
+		strings.Join(rpmScriptletInterpreters, ", "))
+}
+
+// getRpmScriptletProg returns the rpm header value of the scriptlet interpreter.
+func getRpmScriptletProg(rpmDeb *RpmDebCtx) string {
+	switch rpmDeb.RpmScriptletInterpreter {
+	case "":
+		return defaultRpmScriptletInterpreter
+	case rpmScriptletLua:
+		return rpmScriptletLuaProg
+	}
+	return rpmDeb.RpmScriptletInterpreter
+}
+
+// getRpmPreInstScript returns the built-in pre-install scriptlet creating the tarantool
+// system user in the language of the scriptlet interpreter.
+func getRpmPreInstScript(rpmDeb *RpmDebCtx) string {
+	if rpmDeb.RpmScriptletInterpreter == rpmScriptletLua {
+		return rpmPreInstLuaScriptContent
+	}
+	return rpmPreInstScriptContent
+}
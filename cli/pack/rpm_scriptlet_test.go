@@ -0,0 +1,22 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkRpmScriptletInterpreter(t *testing.T) {
+	for _, interpreter := range []string{"", "/bin/sh", "/bin/bash", "lua"} {
+		assert.NoError(t, checkRpmScriptletInterpreter(interpreter), interpreter)
+	}
+	assert.EqualError(t, checkRpmScriptletInterpreter("python"),
+		`unknown RPM scriptlet interpreter "python", supported: /bin/sh, /bin/bash, lua`)
+}
+
+func Test_getRpmScriptletProg(t *testing.T) {
+	assert.Equal(t, "/bin/sh", getRpmScriptletProg(&RpmDebCtx{}))
+	assert.Equal(t, "/bin/bash",
+		getRpmScriptletProg(&RpmDebCtx{RpmScriptletInterpreter: "/bin/bash"}))
+	assert.Equal(t, "<lua>", getRpmScriptletProg(&RpmDebCtx{RpmScriptletInterpreter: "lua"}))
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/tarantool/tt/cli/util"
 )
 
-// genSignature generates the signature for rpm.
-func genSignature(rpmBodyFilePath, rpmHeaderFilePath, cpioPath string) (*rpmTagSetType, error) {
+// genSignature generates the signature for rpm. If signKey is set, GPG signatures
+// of the header and of the header with payload are added.
+func genSignature(rpmBodyFilePath, rpmHeaderFilePath, cpioPath,
+	signKey string) (*rpmTagSetType, error) {
 	// SHA1
 	sha1, err := util.FileSHA1Hex(rpmHeaderFilePath)
 	if err != nil {
@@ -42,5 +44,28 @@ func genSignature(rpmBodyFilePath, rpmHeaderFilePath, cpioPath string) (*rpmTagS
 		{ID: signatureTagMD5, Type: rpmTypeBin, Value: md5},
 	}
 
+	if signKey != "" {
+		signer, err := newGpgSigner(signKey)
+		if err != nil {
+			return nil, err
+		}
+		defer signer.Close()
+
+		// RSA header signature.
+		headerSignature, err := signer.detachSign(rpmHeaderFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign RPM header: %s", err)
+		}
+		// PGP header and payload signature.
+		bodySignature, err := signer.detachSign(rpmBodyFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign RPM body: %s", err)
+		}
+		signature = append(signature,
+			rpmTagType{ID: signatureTagRSA, Type: rpmTypeBin, Value: headerSignature},
+			rpmTagType{ID: signatureTagPGP, Type: rpmTypeBin, Value: bodySignature},
+		)
+	}
+
 	return &signature, nil
 }
@@ -0,0 +1,323 @@
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/google/uuid"
+	"github.com/tarantool/tt/cli/config"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// sbomFileName is a name of the SBOM file in the bundle root.
+	sbomFileName = "sbom.cdx.json"
+	// sbomFileSuffix is a suffix of the SBOM file written next to the package.
+	sbomFileSuffix = ".cdx.json"
+	// sbomSpecVersion is a version of CycloneDX specification of the SBOM.
+	sbomSpecVersion = "1.5"
+	// installedRocksPath is a path of the installed rocks tree in the application directory.
+	installedRocksPath = ".rocks/share/tarantool/rocks"
+)
+
+// sbomLicense is a CycloneDX license choice.
+type sbomLicense struct {
+	License struct {
+		// Name is a license name as it is declared by the component.
+		Name string `json:"name"`
+	} `json:"license"`
+}
+
+// sbomHash is a CycloneDX component hash.
+type sbomHash struct {
+	// Alg is a hash algorithm.
+	Alg string `json:"alg"`
+	// Content is a hex-encoded hash value.
+	Content string `json:"content"`
+}
+
+// sbomReference is a CycloneDX external reference.
+type sbomReference struct {
+	// Type is a reference type: website or vcs.
+	Type string `json:"type"`
+	// URL is a reference URL.
+	URL string `json:"url"`
+}
+
+// sbomComponent is a CycloneDX component.
+type sbomComponent struct {
+	// Type is a component type: application or library.
+	Type string `json:"type"`
+	// BomRef is the component reference unique within the SBOM.
+	BomRef string `json:"bom-ref,omitempty"`
+	// Name is a component name.
+	Name string `json:"name"`
+	// Version is a component version. It is empty if the version is unknown.
+	Version string `json:"version,omitempty"`
+	// Description is a component summary.
+	Description string `json:"description,omitempty"`
+	// Purl is a package URL of the component.
+	Purl string `json:"purl,omitempty"`
+	// Hashes are the component checksums.
+	Hashes []sbomHash `json:"hashes,omitempty"`
+	// Licenses are the component licenses.
+	Licenses []sbomLicense `json:"licenses,omitempty"`
+	// ExternalReferences are the component home page and source.
+	ExternalReferences []sbomReference `json:"externalReferences,omitempty"`
+}
+
+// sbomTool is a CycloneDX tool created the SBOM.
+type sbomTool struct {
+	// Vendor is a tool vendor.
+	Vendor string `json:"vendor"`
+	// Name is a tool name.
+	Name string `json:"name"`
+	// Version is a tool version.
+	Version string `json:"version"`
+}
+
+// sbomMetadata is a CycloneDX SBOM metadata.
+type sbomMetadata struct {
+	// Timestamp is the SBOM creation time.
+	Timestamp string `json:"timestamp"`
+	// Tools are the tools created the SBOM.
+	Tools []sbomTool `json:"tools"`
+	// Component is the packed bundle.
+	Component sbomComponent `json:"component"`
+}
+
+// sbomDocument is a CycloneDX JSON SBOM.
+type sbomDocument struct {
+	BomFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber,omitempty"`
+	Version      int             `json:"version"`
+	Metadata     sbomMetadata    `json:"metadata"`
+	Components   []sbomComponent `json:"components"`
+}
+
+// rockspecInfo is the component metadata read from the rockspec.
+type rockspecInfo struct {
+	name, version, summary, license, homepage, sourceURL string
+}
+
+// readRockspec reads the rockspec metadata. The rockspec is run without the standard
+// libraries except string and table ones, so it cannot access the system.
+func readRockspec(rockspecPath string) (rockspecInfo, error) {
+	info := rockspecInfo{}
+	content, err := os.ReadFile(rockspecPath)
+	if err != nil {
+		return info, err
+	}
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	defer L.Close()
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{{lua.StringLibName, lua.OpenString}, {lua.TabLibName, lua.OpenTable}} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	if err = L.DoString(string(content)); err != nil {
+		return info, fmt.Errorf("failed to run rockspec %s: %s", rockspecPath, err)
+	}
+
+	toString := func(value lua.LValue) string {
+		if str, ok := value.(lua.LString); ok {
+			return string(str)
+		}
+		return ""
+	}
+	info.name = toString(L.GetGlobal("package"))
+	info.version = toString(L.GetGlobal("version"))
+	if description, ok := L.GetGlobal("description").(*lua.LTable); ok {
+		info.summary = toString(description.RawGetString("summary"))
+		info.license = toString(description.RawGetString("license"))
+		info.homepage = toString(description.RawGetString("homepage"))
+	}
+	if source, ok := L.GetGlobal("source").(*lua.LTable); ok {
+		info.sourceURL = toString(source.RawGetString("url"))
+	}
+	return info, nil
+}
+
+// getRockComponent returns the SBOM component of the installed rock. The rock directory
+// name and version are used if the rockspec is not found or cannot be read.
+func getRockComponent(rockName, rockVersion, versionDir string) sbomComponent {
+	info := rockspecInfo{}
+	rockspecs, _ := filepath.Glob(filepath.Join(versionDir, "*.rockspec"))
+	if len(rockspecs) == 0 {
+		log.Debugf("Rockspec of %s %s is not found", rockName, rockVersion)
+	} else {
+		var err error
+		if info, err = readRockspec(rockspecs[0]); err != nil {
+			log.Warnf("Failed to read rockspec of %s %s for SBOM: %s", rockName, rockVersion,
+				err)
+		}
+	}
+	if info.name == "" {
+		info.name = rockName
+	}
+	if info.version == "" {
+		info.version = rockVersion
+	}
+
+	component := sbomComponent{
+		Type:        "library",
+		Name:        info.name,
+		Version:     info.version,
+		Description: info.summary,
+		Purl:        fmt.Sprintf("pkg:luarocks/%s@%s", info.name, info.version),
+	}
+	component.BomRef = component.Purl
+	if info.license != "" {
+		license := sbomLicense{}
+		license.License.Name = info.license
+		component.Licenses = []sbomLicense{license}
+	}
+	if info.homepage != "" {
+		component.ExternalReferences = append(component.ExternalReferences,
+			sbomReference{Type: "website", URL: info.homepage})
+	}
+	if info.sourceURL != "" {
+		component.ExternalReferences = append(component.ExternalReferences,
+			sbomReference{Type: "vcs", URL: info.sourceURL})
+	}
+	return component
+}
+
+// getRocksComponents returns the SBOM components of the rocks installed into
+// the application directory.
+func getRocksComponents(appPath string) ([]sbomComponent, error) {
+	versionDirs, err := filepath.Glob(filepath.Join(appPath, installedRocksPath, "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	components := []sbomComponent{}
+	for _, versionDir := range versionDirs {
+		stat, err := os.Stat(versionDir)
+		if err != nil || !stat.IsDir() {
+			continue
+		}
+		rockName := filepath.Base(filepath.Dir(versionDir))
+		components = append(components, getRockComponent(rockName, filepath.Base(versionDir),
+			versionDir))
+	}
+	return components, nil
+}
+
+// generateSbom writes CycloneDX JSON SBOM of the bundled applications, their rocks and
+// the bundled binaries into the bundle root. The SBOM content is kept in the pack context
+// to write it next to the package.
+func generateSbom(packCtx *PackCtx, cliOpts *config.CliOpts, bundleEnvPath string) error {
+	if !packCtx.Sbom {
+		return nil
+	}
+	log.Infof("Generate %s file", sbomFileName)
+
+	packageVersion := getVersion(packCtx, cliOpts, defaultVersion)
+	document := sbomDocument{
+		BomFormat:   "CycloneDX",
+		SpecVersion: sbomSpecVersion,
+		Version:     1,
+		Metadata: sbomMetadata{
+			Timestamp: getBuildTime(packCtx).Format(time.RFC3339),
+			Tools: []sbomTool{{Vendor: "Tarantool", Name: "tt",
+				Version: getPackerVersion()}},
+			Component: sbomComponent{Type: "application", Name: packCtx.Name,
+				Version: packageVersion},
+		},
+		Components: []sbomComponent{},
+	}
+
+	binaries, err := getManifestBinaries(packCtx)
+	if err != nil {
+		return err
+	}
+	for _, binary := range binaries {
+		document.Components = append(document.Components, sbomComponent{
+			Type:    "application",
+			BomRef:  "binary:" + binary.Name,
+			Name:    binary.Name,
+			Version: binary.Version,
+			Hashes:  []sbomHash{{Alg: "SHA-256", Content: binary.SHA256}},
+		})
+	}
+
+	appNames := make([]string, 0, len(packCtx.AppsInfo))
+	for appName := range packCtx.AppsInfo {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+	rocks := map[string]bool{}
+	for _, appName := range appNames {
+		instances := packCtx.AppsInfo[appName]
+		document.Components = append(document.Components, sbomComponent{
+			Type:    "application",
+			BomRef:  "app:" + appName,
+			Name:    appName,
+			Version: getAppVersion(packCtx, appName, packageVersion),
+		})
+		if len(instances) == 0 || instances[0].IsFileApp {
+			continue
+		}
+		appPath := getDestAppDir(bundleEnvPath, appName, packCtx, cliOpts)
+		components, err := getRocksComponents(appPath)
+		if err != nil {
+			return fmt.Errorf("failed to find %q application rocks: %s", appName, err)
+		}
+		// The rocks shared by the applications are listed once.
+		for _, component := range components {
+			if !rocks[component.BomRef] {
+				rocks[component.BomRef] = true
+				document.Components = append(document.Components, component)
+			}
+		}
+	}
+
+	content, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %s", sbomFileName, err)
+	}
+	// The serial number is derived from the content, so the reproducible builds have
+	// the same SBOM.
+	document.SerialNumber = "urn:uuid:" + uuid.NewSHA1(uuid.NameSpaceOID, content).String()
+	if content, err = json.MarshalIndent(document, "", "  "); err != nil {
+		return fmt.Errorf("failed to encode %s: %s", sbomFileName, err)
+	}
+	content = append(content, '\n')
+	sbomPath := filepath.Join(bundleEnvPath, sbomFileName)
+	if err = os.WriteFile(sbomPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %s", sbomPath, err)
+	}
+	packCtx.sbom = content
+	return nil
+}
+
+// writeSbomFile writes the SBOM next to the package if it is requested. The SBOM of
+// the split tarball is named after the tarball. The package written to a stream has no
+// path, so the SBOM is not written.
+func writeSbomFile(packCtx *PackCtx, packagePath string) error {
+	if !packCtx.SbomArtifact || packCtx.sbom == nil || packagePath == "" {
+		return nil
+	}
+	if len(packCtx.Archive.volumes) > 0 {
+		packagePath = strings.TrimSuffix(packagePath, splitIndexSuffix)
+	}
+	sbomPath := packagePath + sbomFileSuffix
+	err := writePackageFile(packCtx, sbomPath, func(tmpPath string) error {
+		return os.WriteFile(tmpPath, packCtx.sbom, 0644)
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("SBOM is written to %s.", sbomPath)
+	return nil
+}
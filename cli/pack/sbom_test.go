@@ -0,0 +1,110 @@
+package pack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/running"
+)
+
+const testRockspec = `package = "checks"
+version = "3.1.0-1"
+source = {
+    url = "git+https://github.com/tarantool/checks.git",
+    tag = version:gsub("-1", ""),
+}
+description = {
+    summary = "Easy parameter validation",
+    homepage = "https://github.com/tarantool/checks",
+    license = "BSD",
+}
+`
+
+func Test_generateSbom(t *testing.T) {
+	epoch := time.Unix(1700000000, 0).UTC()
+	bundleDir := t.TempDir()
+	rocksDir := filepath.Join(bundleDir, "app", installedRocksPath)
+	require.NoError(t, os.MkdirAll(filepath.Join(rocksDir, "checks", "3.1.0-1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rocksDir, "checks", "3.1.0-1",
+		"checks-3.1.0-1.rockspec"), []byte(testRockspec), 0644))
+	// The rock without rockspec and the rock with the rockspec accessing the system are
+	// listed with the directory names.
+	require.NoError(t, os.MkdirAll(filepath.Join(rocksDir, "unknown", "1.0-1"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(rocksDir, "evil", "0.1-1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rocksDir, "evil", "0.1-1",
+		"evil-0.1-1.rockspec"), []byte(`os.execute("touch pwned")`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rocksDir, "manifest"), []byte{}, 0644))
+	ttPath := filepath.Join(bundleDir, "tt")
+	require.NoError(t, os.WriteFile(ttPath, []byte("tt binary"), 0755))
+
+	packCtx := &PackCtx{
+		Name:            "bundle",
+		Version:         "1.2.3",
+		Sbom:            true,
+		sourceDateEpoch: &epoch,
+		bundledBinaries: map[string]string{"tt": ttPath},
+		AppsInfo: map[string][]running.InstanceCtx{
+			"script": {{AppName: "script", IsFileApp: true}},
+			"app":    {{AppName: "app"}},
+		},
+	}
+	cliOpts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	require.NoError(t, generateSbom(packCtx, cliOpts, bundleDir))
+
+	content, err := os.ReadFile(filepath.Join(bundleDir, sbomFileName))
+	require.NoError(t, err)
+	assert.Equal(t, packCtx.sbom, content)
+	var document sbomDocument
+	require.NoError(t, json.Unmarshal(content, &document))
+	assert.Equal(t, "CycloneDX", document.BomFormat)
+	assert.Equal(t, sbomSpecVersion, document.SpecVersion)
+	assert.Regexp(t, "^urn:uuid:", document.SerialNumber)
+	assert.Equal(t, "2023-11-14T22:13:20Z", document.Metadata.Timestamp)
+	assert.Equal(t, sbomComponent{Type: "application", Name: "bundle", Version: "1.2.3"},
+		document.Metadata.Component)
+
+	require.Len(t, document.Components, 6)
+	assert.Equal(t, "binary:tt", document.Components[0].BomRef)
+	assert.Equal(t, []sbomHash{{"SHA-256",
+		"1006ac5c1a6e70aa858c4d5f0f73500d8d933c1ba90b6837c94c07897b93fef3"}},
+		document.Components[0].Hashes)
+	assert.Equal(t, "app:app", document.Components[1].BomRef)
+	checks := document.Components[2]
+	assert.Equal(t, "pkg:luarocks/checks@3.1.0-1", checks.Purl)
+	assert.Equal(t, "Easy parameter validation", checks.Description)
+	assert.Equal(t, "BSD", checks.Licenses[0].License.Name)
+	assert.Equal(t, []sbomReference{{"website", "https://github.com/tarantool/checks"},
+		{"vcs", "git+https://github.com/tarantool/checks.git"}}, checks.ExternalReferences)
+	assert.Equal(t, sbomComponent{Type: "library", BomRef: "pkg:luarocks/evil@0.1-1",
+		Name: "evil", Version: "0.1-1", Purl: "pkg:luarocks/evil@0.1-1"},
+		document.Components[3])
+	assert.Equal(t, "pkg:luarocks/unknown@1.0-1", document.Components[4].Purl)
+	assert.Equal(t, "app:script", document.Components[5].BomRef)
+	assert.NoFileExists(t, "pwned")
+
+	// The SBOM is reproducible.
+	require.NoError(t, generateSbom(packCtx, cliOpts, bundleDir))
+	assert.Equal(t, content, packCtx.sbom)
+
+	packagePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NoError(t, writeSbomFile(packCtx, packagePath))
+	assert.NoFileExists(t, packagePath+sbomFileSuffix)
+	packCtx.SbomArtifact = true
+	require.NoError(t, writeSbomFile(packCtx, packagePath))
+	artifactContent, err := os.ReadFile(packagePath + sbomFileSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, content, artifactContent)
+
+	// The SBOM of the split tarball is named after the tarball.
+	require.NoError(t, os.Remove(packagePath+sbomFileSuffix))
+	packCtx.Archive.volumes = []string{packagePath + ".001"}
+	require.NoError(t, writeSbomFile(packCtx, packagePath+splitIndexSuffix))
+	assert.NoFileExists(t, packagePath+splitIndexSuffix+sbomFileSuffix)
+	assert.FileExists(t, packagePath+sbomFileSuffix)
+}
@@ -0,0 +1,101 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// gpgSigner creates detached GPG signatures using the key from the user keyring
+// or the key imported from a file.
+type gpgSigner struct {
+	// keyID is a key id to sign with. It is empty if the key is imported from a file.
+	keyID string
+	// homeDir is a temporary GnuPG home directory for the key imported from a file.
+	homeDir string
+}
+
+// newGpgSigner creates a signer for the passed key id or key file path.
+// It fails if the secret key is not found.
+func newGpgSigner(signKey string) (*gpgSigner, error) {
+	if err := util.CheckRequiredBinaries("gpg"); err != nil {
+		return nil, fmt.Errorf("cannot sign the package: %s", err)
+	}
+
+	signer := gpgSigner{}
+	if stat, err := os.Stat(signKey); err == nil && stat.Mode().IsRegular() {
+		if signer.homeDir, err = os.MkdirTemp("", "tt_pack_gnupg"); err != nil {
+			return nil, err
+		}
+		if output, err := signer.gpgCommand("--import", signKey).CombinedOutput(); err != nil {
+			signer.Close()
+			return nil, fmt.Errorf("failed to import the sign key from %q: %s: %s",
+				signKey, err, strings.TrimSpace(string(output)))
+		}
+	} else {
+		signer.keyID = signKey
+	}
+
+	args := []string{"--with-colons", "--list-secret-keys"}
+	if signer.keyID != "" {
+		args = append(args, signer.keyID)
+	}
+	output, err := signer.gpgCommand(args...).Output()
+	if err != nil || !bytes.Contains(output, []byte("sec:")) {
+		signer.Close()
+		return nil, fmt.Errorf("secret key %q is not found", signKey)
+	}
+	return &signer, nil
+}
+
+// gpgCommand returns gpg command with the passed arguments.
+func (signer *gpgSigner) gpgCommand(args ...string) *exec.Cmd {
+	gpgArgs := []string{"--batch", "--yes"}
+	if signer.homeDir != "" {
+		gpgArgs = append(gpgArgs, "--homedir", signer.homeDir)
+	}
+	return exec.Command("gpg", append(gpgArgs, args...)...)
+}
+
+// detachSign returns binary detached signature of the file.
+func (signer *gpgSigner) detachSign(filePath string) ([]byte, error) {
+	args := []string{"--no-armor", "--digest-algo", "SHA256", "--detach-sign",
+		"--output", "-"}
+	if signer.keyID != "" {
+		args = append(args, "--local-user", signer.keyID)
+	}
+	cmd := signer.gpgCommand(append(args, filePath)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	signature, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %q: %s: %s", filePath, err,
+			strings.TrimSpace(stderr.String()))
+	}
+	return signature, nil
+}
+
+// Close removes the temporary GnuPG home directory.
+func (signer *gpgSigner) Close() {
+	if signer.homeDir == "" {
+		return
+	}
+	if err := os.RemoveAll(signer.homeDir); err != nil {
+		log.Warnf("Failed to remove a temporary directory %s: %s", signer.homeDir, err)
+	}
+}
+
+// checkSignKey checks the sign key can be used for package signing.
+func checkSignKey(signKey string) error {
+	signer, err := newGpgSigner(signKey)
+	if err != nil {
+		return err
+	}
+	signer.Close()
+	return nil
+}
@@ -0,0 +1,90 @@
+package pack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// genTestSignKey generates a secret key without a passphrase and exports it to a file.
+func genTestSignKey(t *testing.T) (string, string) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	homeDir := t.TempDir()
+	gpg := func(args ...string) []byte {
+		cmd := exec.Command("gpg", append([]string{"--batch", "--homedir", homeDir}, args...)...)
+		output, err := cmd.Output()
+		require.NoError(t, err, "gpg %v", args)
+		return output
+	}
+	gpg("--passphrase", "", "--quick-gen-key", "tt-test@example.com", "rsa2048", "sign",
+		"never")
+
+	keyPath := filepath.Join(t.TempDir(), "key.asc")
+	require.NoError(t, os.WriteFile(keyPath,
+		gpg("--armor", "--export-secret-keys", "tt-test@example.com"), 0600))
+	return homeDir, keyPath
+}
+
+func Test_gpgSigner(t *testing.T) {
+	homeDir, keyPath := genTestSignKey(t)
+
+	signer, err := newGpgSigner(keyPath)
+	require.NoError(t, err)
+	defer signer.Close()
+
+	filePath := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(filePath, []byte("data"), 0644))
+	signature, err := signer.detachSign(filePath)
+	require.NoError(t, err)
+	require.NotEmpty(t, signature)
+
+	sigPath := filePath + ".sig"
+	require.NoError(t, os.WriteFile(sigPath, signature, 0644))
+	output, err := exec.Command("gpg", "--batch", "--homedir", homeDir,
+		"--verify", sigPath, filePath).CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	// Key from the keyring.
+	t.Setenv("GNUPGHOME", homeDir)
+	require.NoError(t, checkSignKey("tt-test@example.com"))
+}
+
+func Test_checkSignKeyMissing(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	t.Setenv("GNUPGHOME", t.TempDir())
+	assert.EqualError(t, checkSignKey("missing@example.com"),
+		`secret key "missing@example.com" is not found`)
+
+	keyPath := filepath.Join(t.TempDir(), "key.asc")
+	require.NoError(t, os.WriteFile(keyPath, []byte("not a key"), 0600))
+	assert.ErrorContains(t, checkSignKey(keyPath), "failed to import the sign key")
+}
+
+func Test_genSignatureWithSignKey(t *testing.T) {
+	_, keyPath := genTestSignKey(t)
+
+	dir := t.TempDir()
+	for _, name := range []string{"body", "header", "cpio"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+	signature, err := genSignature(filepath.Join(dir, "body"), filepath.Join(dir, "header"),
+		filepath.Join(dir, "cpio"), keyPath)
+	require.NoError(t, err)
+
+	tags := map[int]rpmTagType{}
+	for _, tag := range *signature {
+		tags[tag.ID] = tag
+	}
+	require.Contains(t, tags, signatureTagRSA)
+	require.Contains(t, tags, signatureTagPGP)
+	assert.EqualValues(t, rpmTypeBin, tags[signatureTagRSA].Type)
+	assert.NotEmpty(t, tags[signatureTagPGP].Value)
+}
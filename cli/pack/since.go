@@ -0,0 +1,96 @@
+package pack
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// splitGitPaths splits the NUL-separated paths printed by git with -z option.
+func splitGitPaths(output string, paths map[string]bool) {
+	for _, path := range strings.Split(output, "\x00") {
+		if path != "" {
+			paths[path] = true
+		}
+	}
+}
+
+// getChangedFiles returns the slash-separated paths relative to the directory of the
+// files changed since the git ref: committed, staged, modified in the working tree or
+// untracked and not ignored.
+func getChangedFiles(dir, ref string) (map[string]bool, error) {
+	if _, err := gitCommand(dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, fmt.Errorf("%q is not in a git working tree: %s", dir, err)
+	}
+	if _, err := gitCommand(dir, "rev-parse", "--verify", "--quiet",
+		ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown git ref %q in %q", ref, dir)
+	}
+	changed := map[string]bool{}
+	output, err := gitCommand(dir, "diff", "--name-only", "--relative", "-z", ref, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get files changed since %q in %q: %s", ref, dir, err)
+	}
+	splitGitPaths(output, changed)
+	output, err = gitCommand(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get untracked files in %q: %s", dir, err)
+	}
+	splitGitPaths(output, changed)
+	return changed, nil
+}
+
+// initSinceFiles collects the files of the applications changed since the Since git ref.
+// The single file applications are packed as is, so they are not checked.
+func initSinceFiles(packCtx *PackCtx) error {
+	if err := util.CheckRequiredBinaries("git"); err != nil {
+		return fmt.Errorf("--since flag requires git: %s", err)
+	}
+	packCtx.sinceFiles = map[string]map[string]bool{}
+	for appName, instances := range packCtx.AppsInfo {
+		if len(instances) == 0 || instances[0].IsFileApp {
+			continue
+		}
+		changed, err := getChangedFiles(instances[0].AppDir, packCtx.Since)
+		if err != nil {
+			return fmt.Errorf("cannot apply --since to application %q: %s", appName, err)
+		}
+		packCtx.sinceFiles[appName] = changed
+	}
+	return nil
+}
+
+// removeUnchangedFiles removes the application bundle files which are not changed since
+// the git ref. The directories left empty are removed too.
+func removeUnchangedFiles(bundleAppPath string, changed map[string]bool) error {
+	err := filepath.WalkDir(bundleAppPath, func(path string, entry fs.DirEntry,
+		err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(bundleAppPath, path)
+		if err != nil {
+			return err
+		}
+		if changed[filepath.ToSlash(relPath)] {
+			return nil
+		}
+		log.Debugf("Skip packing of %q: not changed", relPath)
+		return os.Remove(path)
+	})
+	if err == nil {
+		err = pruneEmptyDirs(bundleAppPath, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove unchanged files: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package pack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func Test_getChangedFiles(t *testing.T) {
+	repoDir := initTestGitRepo(t)
+	appDir := filepath.Join(repoDir, "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(appDir, "lib"), 0755))
+	for _, name := range []string{"init.lua", "lib/old.lua", "lib/changed.lua"} {
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, name), nil, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, ".gitignore"), []byte("*.log\n"),
+		0644))
+	cmd := exec.Command("git", "-C", repoDir, "-c", "user.name=tt", "-c",
+		"user.email=tt@example.com", "commit", "-q", "--allow-empty", "-m", "app")
+	require.NoError(t, exec.Command("git", "-C", repoDir, "add", ".").Run())
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "lib", "changed.lua"),
+		[]byte("-- fix"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "lib", "new.lua"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "app.log"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "init.lua"), []byte("-- other"),
+		0644))
+
+	changed, err := getChangedFiles(appDir, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"lib/changed.lua": true, "lib/new.lua": true}, changed)
+
+	packCtx := PackCtx{Since: "HEAD", AppsInfo: map[string][]running.InstanceCtx{
+		"app": {{AppDir: appDir}}, "script": {{IsFileApp: true}}}}
+	require.NoError(t, initSinceFiles(&packCtx))
+	assert.Equal(t, map[string]map[string]bool{"app": changed}, packCtx.sinceFiles)
+
+	changed, err = getChangedFiles(appDir, "v1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{".gitignore": true, "init.lua": true, "lib/old.lua": true,
+		"lib/changed.lua": true, "lib/new.lua": true}, changed)
+
+	_, err = getChangedFiles(appDir, "v9.9.9")
+	assert.EqualError(t, err, `unknown git ref "v9.9.9" in "`+appDir+`"`)
+
+	nonGitDir := t.TempDir()
+	packCtx.AppsInfo["app"][0].AppDir = nonGitDir
+	assert.ErrorContains(t, initSinceFiles(&packCtx), `cannot apply --since to application `+
+		`"app": "`+nonGitDir+`" is not in a git working tree`)
+}
+
+func Test_removeUnchangedFiles(t *testing.T) {
+	appPath := t.TempDir()
+	for _, path := range []string{"init.lua", "lib/changed.lua", "lib/old.lua",
+		"docs/README.md"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(appPath, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(appPath, path), nil, 0644))
+	}
+	require.NoError(t, removeUnchangedFiles(appPath,
+		map[string]bool{"lib/changed.lua": true, "lib/deleted.lua": true}))
+
+	var paths []string
+	require.NoError(t, filepath.Walk(appPath, func(path string, _ os.FileInfo, err error) error {
+		relPath, _ := filepath.Rel(appPath, path)
+		paths = append(paths, filepath.ToSlash(relPath))
+		return err
+	}))
+	sort.Strings(paths)
+	assert.Equal(t, []string{".", "lib", "lib/changed.lua"}, paths)
+}
@@ -0,0 +1,73 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+)
+
+// sizeRe matches the human-readable size: a number with optional decimal or binary
+// unit suffix.
+var sizeRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([kmgt]i?)?b?$`)
+
+// sizeUnits contains the multipliers of the size unit prefixes.
+var sizeUnits = map[string]float64{
+	"":   1,
+	"k":  1e3,
+	"m":  1e6,
+	"g":  1e9,
+	"t":  1e12,
+	"ki": 1 << 10,
+	"mi": 1 << 20,
+	"gi": 1 << 30,
+	"ti": 1 << 40,
+}
+
+// parseSize parses the human-readable size, e.g. 500MB or 1.5GiB. The number without
+// suffix is a size in bytes.
+func parseSize(size string) (int64, error) {
+	match := sizeRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(size)))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q: expected a number with optional B, KB, MB,"+
+			" GB, TB, KiB, MiB, GiB or TiB suffix", size)
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %s", size, err)
+	}
+	bytes := int64(number * sizeUnits[match[2]])
+	if bytes <= 0 {
+		return 0, fmt.Errorf("invalid size %q: must be greater than zero", size)
+	}
+	return bytes, nil
+}
+
+// checkPackageSize checks the written package file does not exceed the size limits.
+// The warning is logged if the package exceeds WarnSize and an error is returned if it
+// exceeds MaxSize. The tarball split into volumes is not checked, its volumes are.
+func checkPackageSize(packCtx *PackCtx, packagePath, filePath string) error {
+	if packCtx.warnSize == 0 && packCtx.maxSize == 0 {
+		return nil
+	}
+	if packCtx.Archive.splitSize > 0 && packagePath == packCtx.Archive.splitTarballPath {
+		return nil
+	}
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	if packCtx.maxSize > 0 && stat.Size() > packCtx.maxSize {
+		return fmt.Errorf("package %s size %d bytes exceeds the maximum size %s, check"+
+			" the data files are not packed by mistake", packagePath, stat.Size(),
+			packCtx.MaxSize)
+	}
+	if packCtx.warnSize > 0 && stat.Size() > packCtx.warnSize {
+		log.Warnf("Package %s size %d bytes exceeds %s. Check the data files are not"+
+			" packed by mistake.", packagePath, stat.Size(), packCtx.WarnSize)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSize(t *testing.T) {
+	tests := map[string]int64{
+		"100":     100,
+		"100B":    100,
+		"1kb":     1000,
+		"500MB":   500_000_000,
+		"1.5 GB":  1_500_000_000,
+		"2TB":     2_000_000_000_000,
+		"1KiB":    1024,
+		"10MiB":   10 << 20,
+		"0.5GiB":  1 << 29,
+		" 1Ti ":   1 << 40,
+		"3gib":    3 << 30,
+		"7 bytes": 0,
+	}
+	for size, expected := range tests {
+		t.Run(size, func(t *testing.T) {
+			bytes, err := parseSize(size)
+			if expected == 0 {
+				assert.ErrorContains(t, err, "invalid size")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, expected, bytes)
+		})
+	}
+
+	_, err := parseSize("0MB")
+	assert.EqualError(t, err, `invalid size "0MB": must be greater than zero`)
+	_, err = parseSize("-1MB")
+	assert.EqualError(t, err, `invalid size "-1MB": expected a number with optional B, KB,`+
+		` MB, GB, TB, KiB, MiB, GiB or TiB suffix`)
+}
+
+func Test_writePackageFileSizeLimits(t *testing.T) {
+	handler := memory.New()
+	logger := log.Log.(*log.Logger)
+	oldHandler := logger.Handler
+	logger.Handler = handler
+	defer func() { logger.Handler = oldHandler }()
+
+	packagePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	write := func(path string) error {
+		return os.WriteFile(path, []byte(strings.Repeat("x", 2048)), 0644)
+	}
+
+	packCtx := PackCtx{WarnSize: "1KiB", warnSize: 1024, MaxSize: "4KiB", maxSize: 4096}
+	require.NoError(t, writePackageFile(&packCtx, packagePath, write))
+	assert.FileExists(t, packagePath)
+	require.Len(t, handler.Entries, 1)
+	assert.Equal(t, log.WarnLevel, handler.Entries[0].Level)
+	assert.Equal(t, "Package "+packagePath+" size 2048 bytes exceeds 1KiB. Check the data"+
+		" files are not packed by mistake.", handler.Entries[0].Message)
+
+	require.NoError(t, os.Remove(packagePath))
+	packCtx = PackCtx{MaxSize: "2KB", maxSize: 2000}
+	err := writePackageFile(&packCtx, packagePath, write)
+	assert.EqualError(t, err, "package "+packagePath+" size 2048 bytes exceeds the maximum"+
+		" size 2KB, check the data files are not packed by mistake")
+	assert.NoFileExists(t, packagePath)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(packagePath), ".bundle.tar.gz.tmp"))
+}
@@ -0,0 +1,244 @@
+package pack
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/util"
+)
+
+const (
+	// splitIndexSuffix is a suffix of the index file of the split tarball.
+	splitIndexSuffix = ".index"
+	// minVolumeDigits is the smallest width of the volume number in the volume name.
+	minVolumeDigits = 3
+)
+
+// splitVolume describes a volume of the split tarball.
+type splitVolume struct {
+	// Name is a volume file name.
+	Name string `json:"name"`
+	// Size is a volume size in bytes.
+	Size int64 `json:"size"`
+	// SHA256 is a checksum of the volume.
+	SHA256 string `json:"sha256"`
+}
+
+// splitIndex describes the tarball split into volumes. The tarball is the concatenation
+// of the volumes in the index order.
+type splitIndex struct {
+	// Name is the tarball file name.
+	Name string `json:"name"`
+	// Size is the tarball size in bytes.
+	Size int64 `json:"size"`
+	// SHA256 is a checksum of the tarball.
+	SHA256 string `json:"sha256"`
+	// Volumes are the tarball volumes.
+	Volumes []splitVolume `json:"volumes"`
+}
+
+// getVolumeDigits returns the width of the volume number for the volumes count.
+func getVolumeDigits(count int) int {
+	if digits := len(strconv.Itoa(count)); digits > minVolumeDigits {
+		return digits
+	}
+	return minVolumeDigits
+}
+
+// getReassemblyCommand returns the shell command concatenating the volumes into
+// the tarball.
+func getReassemblyCommand(tarballPath string, volumesCount int) string {
+	return fmt.Sprintf("cat %s.%s > %s", tarballPath,
+		strings.Repeat("[0-9]", getVolumeDigits(volumesCount)), tarballPath)
+}
+
+// splitTarball splits the tarball into the volumes of at most the split size bytes and
+// writes the index next to them. The compressed stream is split as is, so the tarball
+// is reassembled by the volumes concatenation. The volumes are written starting from the
+// last one and the tarball is truncated after each volume, so only one volume size of
+// the disk space is needed in addition to the tarball. The tarball is removed after
+// splitting. Returns the index path and the volume paths.
+func splitTarball(packCtx *PackCtx, tarballPath string) (string, []string, error) {
+	stat, err := os.Stat(tarballPath)
+	if err != nil {
+		return "", nil, err
+	}
+	splitSize := packCtx.Archive.splitSize
+	count := int((stat.Size() + splitSize - 1) / splitSize)
+	if count == 0 {
+		count = 1
+	}
+	digits := getVolumeDigits(count)
+
+	tarballDigest, err := util.FileSHA256Hex(tarballPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to compute checksum of %q: %s", tarballPath, err)
+	}
+	tarball, err := os.OpenFile(tarballPath, os.O_RDWR, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tarball.Close()
+
+	index := splitIndex{Name: filepath.Base(tarballPath), Size: stat.Size(),
+		SHA256: tarballDigest, Volumes: make([]splitVolume, count)}
+	volumes := make([]string, count)
+	for i := count; i >= 1; i-- {
+		offset := int64(i-1) * splitSize
+		volumePath := fmt.Sprintf("%s.%0*d", tarballPath, digits, i)
+		volume := splitVolume{Name: filepath.Base(volumePath)}
+		err = writePackageFile(packCtx, volumePath, func(tmpPath string) error {
+			file, err := os.Create(tmpPath)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			volumeHash := sha256.New()
+			volume.Size, err = io.Copy(io.MultiWriter(file, volumeHash),
+				io.NewSectionReader(tarball, offset, splitSize))
+			if err != nil {
+				return err
+			}
+			volume.SHA256 = fmt.Sprintf("%x", volumeHash.Sum(nil))
+			return file.Close()
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to write volume %s: %s", volumePath, err)
+		}
+		if err = tarball.Truncate(offset); err != nil {
+			return "", nil, fmt.Errorf("failed to truncate the split tarball: %s", err)
+		}
+		volumes[i-1] = volumePath
+		index.Volumes[i-1] = volume
+	}
+
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode the split index: %s", err)
+	}
+	indexPath := tarballPath + splitIndexSuffix
+	err = writePackageFile(packCtx, indexPath, func(tmpPath string) error {
+		return os.WriteFile(tmpPath, append(content, '\n'), 0644)
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write the split index: %s", err)
+	}
+
+	tarball.Close()
+	if err = os.Remove(tarballPath); err != nil {
+		log.Warnf("Failed to remove the split tarball %s: %s", tarballPath, err)
+	}
+	log.Infof("Bundle is packed successfully to %d volumes %s..%s, the index is written"+
+		" to %s. Reassemble the tarball with `%s` or `tt pack join %s`.", count,
+		volumes[0], filepath.Base(volumes[count-1]), indexPath,
+		getReassemblyCommand(tarballPath, count), indexPath)
+	return indexPath, volumes, nil
+}
+
+// readSplitIndex reads the index of the split tarball.
+func readSplitIndex(indexPath string) (splitIndex, error) {
+	index := splitIndex{}
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return index, fmt.Errorf("cannot read the split index: %s", err)
+	}
+	if err = json.Unmarshal(content, &index); err != nil {
+		return index, fmt.Errorf("failed to parse the split index %q: %s", indexPath, err)
+	}
+	if index.Name == "" || len(index.Volumes) == 0 {
+		return index, fmt.Errorf("the split index %q has no tarball name or volumes",
+			indexPath)
+	}
+	// The names are joined with the index directory, so they must not point outside it.
+	if err = checkSplitFileName(index.Name); err != nil {
+		return index, fmt.Errorf("the split index %q has invalid tarball name: %s",
+			indexPath, err)
+	}
+	for _, volume := range index.Volumes {
+		if err = checkSplitFileName(volume.Name); err != nil {
+			return index, fmt.Errorf("the split index %q has invalid volume name: %s",
+				indexPath, err)
+		}
+	}
+	return index, nil
+}
+
+// checkSplitFileName checks the file name of the split index is a plain file name.
+func checkSplitFileName(name string) error {
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return fmt.Errorf("%q is not a file name", name)
+	}
+	return nil
+}
+
+// JoinVolumes reassembles the split tarball from the volumes listed in the index. The volumes
+// are looked up next to the index, their sizes and checksums are checked. The tarball is
+// written to the output path or next to the index with the original tarball name.
+// Returns the tarball path.
+func JoinVolumes(indexPath, outputPath string) (string, error) {
+	index, err := readSplitIndex(indexPath)
+	if err != nil {
+		return "", err
+	}
+	if outputPath == "" {
+		outputPath = filepath.Join(filepath.Dir(indexPath), index.Name)
+	}
+
+	packCtx := &PackCtx{}
+	if err = lockArtifact(packCtx, outputPath); err != nil {
+		return "", err
+	}
+	defer unlockArtifact(packCtx)
+	err = writePackageFile(packCtx, outputPath, func(tmpPath string) error {
+		output, err := os.Create(tmpPath)
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+		tarballHash := sha256.New()
+		writer := io.MultiWriter(output, tarballHash)
+		for _, volume := range index.Volumes {
+			if err = appendVolume(writer, filepath.Join(filepath.Dir(indexPath),
+				volume.Name), volume); err != nil {
+				return err
+			}
+		}
+		if digest := fmt.Sprintf("%x", tarballHash.Sum(nil)); digest != index.SHA256 {
+			return fmt.Errorf("checksum mismatch of the joined tarball %s", index.Name)
+		}
+		return output.Close()
+	})
+	if err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// appendVolume writes the volume content to the writer checking its size and checksum.
+func appendVolume(writer io.Writer, volumePath string, volume splitVolume) error {
+	file, err := os.Open(volumePath)
+	if err != nil {
+		return fmt.Errorf("cannot open the volume: %s", err)
+	}
+	defer file.Close()
+	volumeHash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(writer, volumeHash), file)
+	if err != nil {
+		return fmt.Errorf("failed to read the volume %s: %s", volumePath, err)
+	}
+	if size != volume.Size {
+		return fmt.Errorf("the volume %s size is %d, expected %d", volumePath, size,
+			volume.Size)
+	}
+	if fmt.Sprintf("%x", volumeHash.Sum(nil)) != volume.SHA256 {
+		return fmt.Errorf("checksum mismatch of the volume %s", volumePath)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package pack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+)
+
+func Test_splitTarball(t *testing.T) {
+	outDir := t.TempDir()
+	tarballPath := filepath.Join(outDir, "bundle.tar.gz")
+	content := strings.Repeat("0123456789", 25)
+	require.NoError(t, os.WriteFile(tarballPath, []byte(content), 0644))
+
+	packCtx := &PackCtx{Archive: ArchiveCtx{splitSize: 100}}
+	indexPath, volumes, err := splitTarball(packCtx, tarballPath)
+	require.NoError(t, err)
+	assert.Equal(t, tarballPath+splitIndexSuffix, indexPath)
+	assert.Equal(t, []string{tarballPath + ".001", tarballPath + ".002",
+		tarballPath + ".003"}, volumes)
+	assert.NoFileExists(t, tarballPath)
+
+	// The volumes concatenation is the tarball.
+	joined := ""
+	for _, volume := range volumes {
+		volumeContent, err := os.ReadFile(volume)
+		require.NoError(t, err)
+		joined += string(volumeContent)
+	}
+	assert.Equal(t, content, joined)
+
+	indexContent, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	var index splitIndex
+	require.NoError(t, json.Unmarshal(indexContent, &index))
+	assert.Equal(t, "bundle.tar.gz", index.Name)
+	assert.EqualValues(t, 250, index.Size)
+	require.Len(t, index.Volumes, 3)
+	assert.Equal(t, "bundle.tar.gz.003", index.Volumes[2].Name)
+	assert.EqualValues(t, 50, index.Volumes[2].Size)
+
+	tarballPath, err = JoinVolumes(indexPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(outDir, "bundle.tar.gz"), tarballPath)
+	joinedContent, err := os.ReadFile(tarballPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(joinedContent))
+}
+
+func TestPackSplitTarballResult(t *testing.T) {
+	logger := log.Log.(*log.Logger)
+	prevHandler, prevLevel := logger.Handler, logger.Level
+	defer func() {
+		logger.Handler, logger.Level = prevHandler, prevLevel
+	}()
+	handler := memory.New()
+	logger.Handler, logger.Level = handler, log.InfoLevel
+
+	sourceDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "init.lua"),
+		[]byte(strings.Repeat("print(1)\n", 100)), 0644))
+	outputDir := t.TempDir()
+	packCtx := PackCtx{Type: Tgz, Name: "bundle", Version: "1.0.0", SourceDir: sourceDir,
+		OutputDir: outputDir, Archive: ArchiveCtx{CompressionLevel: DefaultCompressionLevel,
+			SplitSize: "100B", splitSize: 100}}
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: "instances.enabled"}}
+	indexPath, err := Pack(&cmdcontext.CmdCtx{}, &packCtx, opts)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(indexPath, splitIndexSuffix))
+	tarballPath := strings.TrimSuffix(indexPath, splitIndexSuffix)
+	assert.NoFileExists(t, tarballPath)
+
+	// The removed tarball is not reported as the result.
+	var messages []string
+	for _, entry := range handler.Entries {
+		messages = append(messages, entry.Message)
+	}
+	assert.NotContains(t, messages, "Bundle is packed successfully to "+tarballPath+".")
+	last := messages[len(messages)-1]
+	assert.True(t, strings.HasPrefix(last, "Bundle is packed successfully to "), last)
+	assert.Contains(t, last, tarballPath+".001..")
+	assert.Contains(t, last, "the index is written to "+indexPath)
+}
+
+func TestJoinVolumesErrors(t *testing.T) {
+	outDir := t.TempDir()
+	tarballPath := filepath.Join(outDir, "bundle.tar.gz")
+	require.NoError(t, os.WriteFile(tarballPath, []byte(strings.Repeat("a", 30)), 0644))
+	indexPath, volumes, err := splitTarball(&PackCtx{Archive: ArchiveCtx{splitSize: 10}},
+		tarballPath)
+	require.NoError(t, err)
+	outputPath := filepath.Join(t.TempDir(), "out.tar.gz")
+
+	require.NoError(t, os.WriteFile(volumes[1], []byte("bbbbbbbbbb"), 0644))
+	_, err = JoinVolumes(indexPath, outputPath)
+	assert.ErrorContains(t, err, "checksum mismatch of the volume "+volumes[1])
+	assert.NoFileExists(t, outputPath)
+
+	require.NoError(t, os.WriteFile(volumes[1], []byte("aaaaa"), 0644))
+	_, err = JoinVolumes(indexPath, outputPath)
+	assert.ErrorContains(t, err, "size is 5, expected 10")
+
+	require.NoError(t, os.Remove(volumes[0]))
+	_, err = JoinVolumes(indexPath, outputPath)
+	assert.ErrorContains(t, err, "cannot open the volume")
+
+	require.NoError(t, os.WriteFile(indexPath, []byte("{}"), 0644))
+	_, err = JoinVolumes(indexPath, outputPath)
+	assert.ErrorContains(t, err, "has no tarball name or volumes")
+
+	// The names pointing outside the index directory are rejected.
+	for _, index := range []string{
+		`{"name": "../bundle.tar.gz", "volumes": [{"name": "bundle.tar.gz.001"}]}`,
+		`{"name": "bundle.tar.gz", "volumes": [{"name": "/etc/passwd"}]}`,
+		`{"name": "..", "volumes": [{"name": "bundle.tar.gz.001"}]}`,
+	} {
+		require.NoError(t, os.WriteFile(indexPath, []byte(index), 0644))
+		_, err = JoinVolumes(indexPath, "")
+		assert.ErrorContains(t, err, "is not a file name")
+	}
+}
+
+func Test_splitTarballMaxSize(t *testing.T) {
+	outDir := t.TempDir()
+	tarballPath := filepath.Join(outDir, "bundle.tar.gz")
+	content := strings.Repeat("a", 3000)
+
+	// The maximum size is checked for the volumes, not for the tarball to split.
+	packCtx := &PackCtx{MaxSize: "1000B", maxSize: 1000,
+		Archive: ArchiveCtx{splitSize: 1000, splitTarballPath: tarballPath}}
+	require.NoError(t, writePackageFile(packCtx, tarballPath, func(tmpPath string) error {
+		return os.WriteFile(tmpPath, []byte(content), 0644)
+	}))
+	_, volumes, err := splitTarball(packCtx, tarballPath)
+	require.NoError(t, err)
+	assert.Len(t, volumes, 3)
+
+	packCtx.Archive.splitSize = 1500
+	require.NoError(t, os.WriteFile(tarballPath, []byte(content), 0644))
+	_, _, err = splitTarball(packCtx, tarballPath)
+	assert.ErrorContains(t, err, "exceeds the maximum size 1000B")
+}
+
+func Test_getReassemblyCommand(t *testing.T) {
+	assert.Equal(t, "cat out/b.tar.gz.[0-9][0-9][0-9] > out/b.tar.gz",
+		getReassemblyCommand("out/b.tar.gz", 12))
+	assert.Equal(t, "cat b.tar.gz.[0-9][0-9][0-9][0-9] > b.tar.gz",
+		getReassemblyCommand("b.tar.gz", 1200))
+}
@@ -0,0 +1,27 @@
+package pack
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/apex/log"
+)
+
+// stripDebug removes debug symbols from the binaries copied into the bundle using strip
+// utility. Binaries are kept as is with a warning if strip is not available or fails.
+func stripDebug(binaries ...string) {
+	stripPath, err := exec.LookPath("strip")
+	if err != nil {
+		log.Warnf("Debug symbols are not stripped from the binaries: %s", err)
+		return
+	}
+	for _, binary := range binaries {
+		log.Infof("Stripping debug symbols from %s", binary)
+		stripCmd := exec.Command(stripPath, "--strip-debug", binary)
+		stripCmd.Stderr = os.Stderr
+		if err = stripCmd.Run(); err != nil {
+			log.Warnf("Failed to strip debug symbols from %s, the binary is kept as is: %s",
+				binary, err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_stripDebug(t *testing.T) {
+	binDir := t.TempDir()
+	outputPath := filepath.Join(binDir, "strip_args")
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "strip"), []byte(`#!/bin/sh
+echo "$@" >> `+outputPath+`
+[ "$2" != "broken" ]
+`), 0755))
+	t.Setenv("PATH", binDir)
+
+	// Strip failure is not fatal.
+	stripDebug("bin/tarantool", "broken", "bin/tt")
+	output, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "--strip-debug bin/tarantool\n--strip-debug broken\n--strip-debug bin/tt\n",
+		string(output))
+}
+
+func Test_stripDebugNoStrip(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	binPath := filepath.Join(t.TempDir(), "tt")
+	require.NoError(t, os.WriteFile(binPath, []byte("binary"), 0755))
+
+	stripDebug(binPath)
+	content, err := os.ReadFile(binPath)
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(content))
+}
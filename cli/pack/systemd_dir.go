@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/tarantool/tt/cli/configure"
@@ -16,8 +19,15 @@ import (
 const (
 	defaultInstanceFdLimit = 65535
 	unitParamsFileName     = "systemd-unit-params.yml"
+	// maxSystemdUnitNameLen is a maximum length of systemd unit name including suffix.
+	maxSystemdUnitNameLen = 255
 )
 
+// systemdAppNameRe matches the application names allowed in systemd unit names.
+// Dots, colons and backslashes are allowed by systemd, but they have special meaning
+// in unit names or tt instance names.
+var systemdAppNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 //go:embed templates/app-inst-unit-template.txt
 var appInstUnitContentTemplate string
 
@@ -30,6 +40,29 @@ func systemdUnitFileName(inst running.InstanceCtx) string {
 	}
 }
 
+// checkSystemdAppNames checks all applications names can be used in systemd unit names.
+func checkSystemdAppNames(packCtx *PackCtx) error {
+	var invalidNames []string
+	for appName, instances := range packCtx.AppsInfo {
+		unitName := appName
+		if len(instances) > 0 {
+			unitName = systemdUnitFileName(instances[0])
+			appName = instances[0].AppName
+		}
+		if !systemdAppNameRe.MatchString(appName) || len(unitName) > maxSystemdUnitNameLen {
+			invalidNames = append(invalidNames, fmt.Sprintf("%q", appName))
+		}
+	}
+	if len(invalidNames) == 0 {
+		return nil
+	}
+	sort.Strings(invalidNames)
+	return fmt.Errorf("invalid application names for systemd units: %s. "+
+		"Application name may contain only ASCII letters, digits, \"-\" and \"_\", "+
+		"rename the applications directories or use tgz package type",
+		strings.Join(invalidNames, ", "))
+}
+
 // systemdDescriptionAppName generates an app name to use in description line in systemd unit.
 func systemdDescriptionAppName(inst running.InstanceCtx) string {
 	if inst.SingleApp {
@@ -42,7 +75,8 @@ func systemdDescriptionAppName(inst running.InstanceCtx) string {
 // initSystemdDir generates systemd unit files for every application in the current bundle.
 // pathToEnv is a path to environment in the target system.
 // baseDirPath is a root of the directory which will get packed.
-func initSystemdDir(packCtx *PackCtx, baseDirPath, pathToEnv string) error {
+// version is a version of the package.
+func initSystemdDir(packCtx *PackCtx, baseDirPath, pathToEnv, version string) error {
 	log.Infof("Initializing systemd directory.")
 
 	systemdBaseDir := filepath.Join(baseDirPath, "usr", "lib", "systemd", "system")
@@ -51,6 +85,11 @@ func initSystemdDir(packCtx *PackCtx, baseDirPath, pathToEnv string) error {
 		return err
 	}
 
+	unitTemplate := appInstUnitContentTemplate
+	if packCtx.RpmDeb.systemdUnitTemplate != "" {
+		unitTemplate = packCtx.RpmDeb.systemdUnitTemplate
+	}
+
 	for appName, instances := range packCtx.AppsInfo {
 		if len(instances) == 0 {
 			return fmt.Errorf("missing instances list for %q application", appName)
@@ -66,8 +105,13 @@ func initSystemdDir(packCtx *PackCtx, baseDirPath, pathToEnv string) error {
 			return err
 		}
 
-		if err = util.InstantiateFileFromTemplate(appInstUnitPath, appInstUnitContentTemplate,
-			unitParams); err != nil {
+		templateParams := systemdUnitTemplateParams{
+			systemdUnitParams: unitParams,
+			Name:              packCtx.Name,
+			Version:           version,
+		}
+		if err = util.InstantiateFileFromTemplate(appInstUnitPath, unitTemplate,
+			templateParams); err != nil {
 			return fmt.Errorf("failed to create systemd unit file: %s", err)
 		}
 	}
@@ -95,6 +139,31 @@ type systemdUnitParams struct {
 	InstanceEnv map[string]string `yaml:"instance-env"`
 }
 
+// systemdUnitTemplateParams contains the parameters for systemd unit template rendering.
+type systemdUnitTemplateParams struct {
+	systemdUnitParams
+	// Name is a package name.
+	Name string
+	// Version is a package version.
+	Version string
+}
+
+// loadSystemdUnitTemplate loads systemd unit template from the file set in pack context
+// and checks it can be rendered.
+func loadSystemdUnitTemplate(packCtx *PackCtx) error {
+	templatePath := packCtx.RpmDeb.SystemdUnitTemplateFile
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return fmt.Errorf("cannot read systemd unit template %q: %s", templatePath, err)
+	}
+	unitTemplate := string(content)
+	if _, err = util.GetTextTemplatedStr(&unitTemplate, systemdUnitTemplateParams{}); err != nil {
+		return fmt.Errorf("invalid systemd unit template %q: %s", templatePath, err)
+	}
+	packCtx.RpmDeb.systemdUnitTemplate = unitTemplate
+	return nil
+}
+
 func loadUserUnitParams(unitParams *systemdUnitParams, packCtx *PackCtx,
 	inst running.InstanceCtx) error {
 	// First check systemd params file in application directory and if it does not exist, check
@@ -193,7 +193,8 @@ func Test_initSystemdDir(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			baseTestDir := t.TempDir()
-			tt.wantErr(t, initSystemdDir(tt.args.packCtx, baseTestDir, tt.args.pathToEnv),
+			tt.wantErr(t, initSystemdDir(tt.args.packCtx, baseTestDir, tt.args.pathToEnv,
+				"1.0.0"),
 				fmt.Sprintf("initSystemdDir(%v, %v, %v)",
 					baseTestDir, tt.args.pathToEnv, tt.args.packCtx))
 
@@ -427,3 +428,82 @@ instance-env:
 		})
 	}
 }
+
+func Test_initSystemdDirCustomTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "unit.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte(`[Unit]
+Description={{ .Name }} {{ .Version }} {{ .AppName }}
+
+[Service]
+ExecStart={{ .TT }} -L {{ .ConfigPath }} start {{ .ExecArgs }}
+EnvironmentFile=/etc/sysconfig/{{ .Name | ToLower }}
+LimitNOFILE={{ .FdLimit }}
+`), 0644))
+
+	packCtx := &PackCtx{
+		Name:            "Pack",
+		WithoutBinaries: true,
+		RpmDeb:          RpmDebCtx{SystemdUnitTemplateFile: templatePath},
+		AppsInfo: map[string][]running.InstanceCtx{
+			"app": {running.InstanceCtx{AppName: "app", SingleApp: true}},
+		},
+	}
+	require.NoError(t, loadSystemdUnitTemplate(packCtx))
+
+	baseTestDir := t.TempDir()
+	require.NoError(t, initSystemdDir(packCtx, baseTestDir, "/path/to/env", "1.2.3"))
+
+	buf, err := os.ReadFile(filepath.Join(baseTestDir, "usr", "lib", "systemd", "system",
+		"app.service"))
+	require.NoError(t, err)
+	assert.Equal(t, `[Unit]
+Description=Pack 1.2.3 app
+
+[Service]
+ExecStart=tt -L /path/to/env start app
+EnvironmentFile=/etc/sysconfig/pack
+LimitNOFILE=65535
+`, string(buf))
+}
+
+func Test_loadSystemdUnitTemplateInvalid(t *testing.T) {
+	testDir := t.TempDir()
+
+	packCtx := &PackCtx{RpmDeb: RpmDebCtx{
+		SystemdUnitTemplateFile: filepath.Join(testDir, "missing.tmpl")}}
+	assert.ErrorContains(t, loadSystemdUnitTemplate(packCtx),
+		"cannot read systemd unit template")
+
+	tests := map[string]string{
+		"syntax.tmpl":  "ExecStart={{ .TT ",
+		"unknown.tmpl": "ExecStart={{ .Unknown }}",
+	}
+	for fileName, content := range tests {
+		t.Run(fileName, func(t *testing.T) {
+			templatePath := filepath.Join(testDir, fileName)
+			require.NoError(t, os.WriteFile(templatePath, []byte(content), 0644))
+			packCtx := &PackCtx{RpmDeb: RpmDebCtx{SystemdUnitTemplateFile: templatePath}}
+			assert.ErrorContains(t, loadSystemdUnitTemplate(packCtx),
+				"invalid systemd unit template")
+			assert.Empty(t, packCtx.RpmDeb.systemdUnitTemplate)
+		})
+	}
+}
+
+func Test_checkSystemdAppNames(t *testing.T) {
+	packCtx := PackCtx{AppsInfo: map[string][]running.InstanceCtx{
+		"app1":   {{AppName: "app1", SingleApp: true}},
+		"my_app": {{AppName: "my_app"}},
+		"my-app": {{AppName: "my-app"}},
+	}}
+	require.NoError(t, checkSystemdAppNames(&packCtx))
+
+	packCtx.AppsInfo["my.app"] = []running.InstanceCtx{{AppName: "my.app"}}
+	packCtx.AppsInfo["app@1"] = []running.InstanceCtx{{AppName: "app@1", SingleApp: true}}
+	longName := strings.Repeat("a", maxSystemdUnitNameLen)
+	packCtx.AppsInfo[longName] = []running.InstanceCtx{{AppName: longName}}
+	err := checkSystemdAppNames(&packCtx)
+	assert.ErrorContains(t, err, fmt.Sprintf(
+		`invalid application names for systemd units: "%s", "app@1", "my.app". `, longName))
+	assert.ErrorContains(t, err, "rename the applications directories")
+}
@@ -0,0 +1,137 @@
+package pack
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/search"
+	"github.com/tarantool/tt/cli/util"
+	"github.com/tarantool/tt/cli/version"
+	"gopkg.in/yaml.v2"
+)
+
+// tarantoolPinFileName is a name of the application file pinning the tarantool version.
+const tarantoolPinFileName = ".tarantool.yml"
+
+// tarantoolPin is the content of the tarantool version pin file.
+type tarantoolPin struct {
+	// Tarantool is the tarantool version the application is run with.
+	Tarantool string `yaml:"tarantool"`
+}
+
+// findInstalledTarantool returns the path of the tarantool binary of the specified version
+// installed in the environment binaries directory by tt install.
+func findInstalledTarantool(binDir, tntVersion string) (string, error) {
+	versionStr := strings.TrimPrefix(tntVersion, "v")
+	if binDir != "" {
+		for _, program := range []string{search.ProgramCe, search.ProgramEe} {
+			for _, prefix := range []string{"", "v"} {
+				binPath := filepath.Join(binDir,
+					program+version.FsSeparator+prefix+versionStr)
+				if util.IsRegularFile(binPath) {
+					return binPath, nil
+				}
+			}
+		}
+	}
+	return "", &binaryError{fmt.Errorf("tarantool %s is not installed in the environment, "+
+		"install it with: tt install tarantool %s", tntVersion, versionStr)}
+}
+
+// selectTarantoolVersion makes the installed tarantool of the requested version to be
+// used for packing instead of the active one.
+func selectTarantoolVersion(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx, binDir string) error {
+	tntPath, err := findInstalledTarantool(binDir, packCtx.TarantoolVersion)
+	if err != nil {
+		return err
+	}
+	cmdCtx.Cli.TarantoolCli = cmdcontext.TarantoolCli{Executable: tntPath}
+	cmdCtx.Cli.IsSystem = false
+	return nil
+}
+
+// readTarantoolPin returns the tarantool version pinned in the application directory.
+// An empty version is returned if the application does not have the pin file.
+func readTarantoolPin(appDir string) (string, error) {
+	pinPath := filepath.Join(appDir, tarantoolPinFileName)
+	content, err := os.ReadFile(pinPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("cannot read %s: %s", pinPath, err)
+	}
+	var pin tarantoolPin
+	if err = yaml.Unmarshal(content, &pin); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %s", pinPath, err)
+	}
+	if pin.Tarantool == "" {
+		return "", fmt.Errorf("failed to parse %s: tarantool version is not set", pinPath)
+	}
+	return pin.Tarantool, nil
+}
+
+// getPinnedTarantoolVersion returns the tarantool version pinned by the packed
+// applications. The applications must not pin different versions. The single file
+// applications do not have own directories, so they are not checked.
+func getPinnedTarantoolVersion(packCtx *PackCtx) (string, error) {
+	appNames := make([]string, 0, len(packCtx.AppsInfo))
+	for appName := range packCtx.AppsInfo {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+
+	pinnedVersion, pinnedBy := "", ""
+	for _, appName := range appNames {
+		instances := packCtx.AppsInfo[appName]
+		if len(instances) == 0 || instances[0].IsFileApp {
+			continue
+		}
+		tntVersion, err := readTarantoolPin(instances[0].AppDir)
+		if err != nil {
+			return "", err
+		}
+		if tntVersion == "" {
+			continue
+		}
+		if pinnedVersion != "" && strings.TrimPrefix(tntVersion, "v") !=
+			strings.TrimPrefix(pinnedVersion, "v") {
+			return "", fmt.Errorf("applications pin different tarantool versions: %s in "+
+				"%q and %s in %q, use --tarantool-version to select one", pinnedVersion,
+				pinnedBy, tntVersion, appName)
+		}
+		pinnedVersion, pinnedBy = tntVersion, appName
+	}
+	return pinnedVersion, nil
+}
+
+// isTarantoolBundled returns true if the tarantool binary is copied into the bundle:
+// the binaries are not excluded and the tarantool is not the system one or it is
+// requested with --with-binaries.
+func isTarantoolBundled(packCtx *PackCtx) bool {
+	return !packCtx.WithoutBinaries && (!packCtx.TarantoolIsSystem || packCtx.WithBinaries)
+}
+
+// selectPinnedTarantoolVersion makes the installed tarantool of the version pinned by
+// the applications to be bundled instead of the active one.
+func selectPinnedTarantoolVersion(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
+	binDir string) error {
+	tntVersion, err := getPinnedTarantoolVersion(packCtx)
+	if err != nil || tntVersion == "" {
+		return err
+	}
+	log.Debugf("Using tarantool %s pinned in %s", tntVersion, tarantoolPinFileName)
+	tntPath, err := findInstalledTarantool(binDir, tntVersion)
+	if err != nil {
+		return fmt.Errorf("tarantool version is pinned in %s: %w", tarantoolPinFileName, err)
+	}
+	cmdCtx.Cli.TarantoolCli = cmdcontext.TarantoolCli{Executable: tntPath}
+	packCtx.TarantoolExecutable = tntPath
+	return nil
+}
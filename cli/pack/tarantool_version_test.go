@@ -0,0 +1,129 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func Test_findInstalledTarantool(t *testing.T) {
+	binDir := t.TempDir()
+	for _, name := range []string{"tarantool_2.11.1", "tarantool-ee_v3.0.0"} {
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, name), nil, 0755))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(binDir, "tarantool_2.10.0"), 0755))
+
+	binPath, err := findInstalledTarantool(binDir, "2.11.1")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(binDir, "tarantool_2.11.1"), binPath)
+
+	binPath, err = findInstalledTarantool(binDir, "v3.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(binDir, "tarantool-ee_v3.0.0"), binPath)
+
+	_, err = findInstalledTarantool(binDir, "2.10.0")
+	assert.EqualError(t, err, "tarantool 2.10.0 is not installed in the environment, "+
+		"install it with: tt install tarantool 2.10.0")
+	_, err = findInstalledTarantool("", "v2.11.1")
+	assert.EqualError(t, err, "tarantool v2.11.1 is not installed in the environment, "+
+		"install it with: tt install tarantool 2.11.1")
+}
+
+func Test_selectTarantoolVersion(t *testing.T) {
+	binDir := t.TempDir()
+	binPath := filepath.Join(binDir, "tarantool_2.11.1")
+	require.NoError(t, os.WriteFile(binPath, nil, 0755))
+
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.IsSystem = true
+	cmdCtx.Cli.TarantoolCli = cmdcontext.TarantoolCli{Executable: "/usr/bin/tarantool"}
+	packCtx := PackCtx{TarantoolVersion: "3.0.0"}
+	assert.ErrorContains(t, selectTarantoolVersion(&cmdCtx, &packCtx, binDir),
+		"tarantool 3.0.0 is not installed")
+	assert.Equal(t, "/usr/bin/tarantool", cmdCtx.Cli.TarantoolCli.Executable)
+
+	packCtx.TarantoolVersion = "2.11.1"
+	require.NoError(t, selectTarantoolVersion(&cmdCtx, &packCtx, binDir))
+	assert.Equal(t, binPath, cmdCtx.Cli.TarantoolCli.Executable)
+	assert.False(t, cmdCtx.Cli.IsSystem)
+}
+
+func Test_readTarantoolPin(t *testing.T) {
+	appDir := t.TempDir()
+	tntVersion, err := readTarantoolPin(appDir)
+	require.NoError(t, err)
+	assert.Empty(t, tntVersion)
+
+	pinPath := filepath.Join(appDir, tarantoolPinFileName)
+	require.NoError(t, os.WriteFile(pinPath, []byte("tarantool: 3.0\n"), 0644))
+	tntVersion, err = readTarantoolPin(appDir)
+	require.NoError(t, err)
+	assert.Equal(t, "3.0", tntVersion)
+
+	require.NoError(t, os.WriteFile(pinPath, []byte("version: 3.0.0\n"), 0644))
+	_, err = readTarantoolPin(appDir)
+	assert.ErrorContains(t, err, "tarantool version is not set")
+
+	require.NoError(t, os.WriteFile(pinPath, []byte("tarantool: [\n"), 0644))
+	_, err = readTarantoolPin(appDir)
+	assert.ErrorContains(t, err, "failed to parse "+pinPath)
+}
+
+func Test_selectPinnedTarantoolVersion(t *testing.T) {
+	baseDir := t.TempDir()
+	binDir := filepath.Join(baseDir, "bin")
+	require.NoError(t, os.Mkdir(binDir, 0755))
+	binPath := filepath.Join(binDir, "tarantool_2.11.1")
+	require.NoError(t, os.WriteFile(binPath, nil, 0755))
+
+	appDirs := map[string]string{}
+	for _, appName := range []string{"app1", "app2", "app3"} {
+		appDirs[appName] = filepath.Join(baseDir, appName)
+		require.NoError(t, os.Mkdir(appDirs[appName], 0755))
+	}
+	writePin := func(appName, tntVersion string) {
+		require.NoError(t, os.WriteFile(filepath.Join(appDirs[appName], tarantoolPinFileName),
+			[]byte("tarantool: "+tntVersion+"\n"), 0644))
+	}
+	writePin("app1", "2.11.1")
+	writePin("app2", "v2.11.1")
+
+	packCtx := PackCtx{AppsInfo: map[string][]running.InstanceCtx{
+		"app1": {{AppDir: appDirs["app1"]}},
+		"app2": {{AppDir: appDirs["app2"]}},
+		"app3": {{AppDir: appDirs["app3"]}},
+	}}
+	cmdCtx := cmdcontext.CmdCtx{}
+	cmdCtx.Cli.IsSystem = true
+	packCtx.TarantoolIsSystem, packCtx.WithBinaries = true, true
+	require.NoError(t, selectPinnedTarantoolVersion(&cmdCtx, &packCtx, binDir))
+	assert.Equal(t, binPath, cmdCtx.Cli.TarantoolCli.Executable)
+	assert.Equal(t, binPath, packCtx.TarantoolExecutable)
+	// The pinned tarantool is bundled as the system one requested by --with-binaries.
+	assert.True(t, packCtx.TarantoolIsSystem)
+	assert.True(t, cmdCtx.Cli.IsSystem)
+
+	writePin("app3", "3.0.0")
+	err := selectPinnedTarantoolVersion(&cmdCtx, &packCtx, binDir)
+	assert.EqualError(t, err, `applications pin different tarantool versions: v2.11.1 in `+
+		`"app2" and 3.0.0 in "app3", use --tarantool-version to select one`)
+
+	delete(packCtx.AppsInfo, "app1")
+	delete(packCtx.AppsInfo, "app2")
+	err = selectPinnedTarantoolVersion(&cmdCtx, &packCtx, binDir)
+	assert.EqualError(t, err, "tarantool version is pinned in .tarantool.yml: tarantool "+
+		"3.0.0 is not installed in the environment, install it with: tt install tarantool 3.0.0")
+	assert.ErrorIs(t, err, ErrBinaryNotFound)
+}
+
+func Test_isTarantoolBundled(t *testing.T) {
+	assert.True(t, isTarantoolBundled(&PackCtx{}))
+	assert.False(t, isTarantoolBundled(&PackCtx{TarantoolIsSystem: true}))
+	assert.True(t, isTarantoolBundled(&PackCtx{TarantoolIsSystem: true, WithBinaries: true}))
+	assert.False(t, isTarantoolBundled(&PackCtx{WithoutBinaries: true}))
+}
@@ -0,0 +1,22 @@
+local sysuser = "tarantool"
+
+local function user_exists(name)
+    local passwd = io.open("/etc/passwd", "r")
+    if passwd == nil then
+        return false
+    end
+    for line in passwd:lines() do
+        if line:match("^([^:]*):") == name then
+            passwd:close()
+            return true
+        end
+    end
+    passwd:close()
+    return false
+end
+
+if not user_exists(sysuser) then
+    rpm.execute("/usr/sbin/groupadd", "-r", sysuser)
+    rpm.execute("/usr/sbin/useradd", "-M", "-N", "-g", sysuser, "-r", "-d", "/var/lib/tarantool",
+        "-s", "/sbin/nologin", "-c", "Tarantool Server", sysuser)
+end
@@ -8,90 +8,148 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/tarantool/tt/cli/configure"
 )
 
-// writeTgzArchive creates TGZ archive of specified path.
-func writeTgzArchive(srcDirPath string, destFilePath string, packCtx PackCtx) error {
+// DefaultCompressionLevel is a default gzip compression level used for tgz packing.
+const DefaultCompressionLevel = 6
+
+// writeTgzArchive creates TGZ archive of specified path using passed gzip compression level.
+func writeTgzArchive(srcDirPath string, destFilePath string, packCtx PackCtx,
+	compressionLevel int) error {
 	destFile, err := os.Create(destFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to create result TGZ file %s: %s", destFilePath, err)
 	}
+	defer destFile.Close()
 
-	gzipWriter := gzip.NewWriter(destFile)
-	defer gzipWriter.Close()
+	if err = writeTgz(srcDirPath, destFile, &packCtx, compressionLevel); err != nil {
+		return err
+	}
+	return destFile.Close()
+}
 
-	err = WriteTarArchive(srcDirPath, gzipWriter, packCtx.RpmDeb.pkgFilesInfo)
+// writeTgz writes TGZ archive of specified path to the writer using passed gzip
+// compression level.
+func writeTgz(srcDirPath string, writer io.Writer, packCtx *PackCtx,
+	compressionLevel int) error {
+	gzipWriter, err := gzip.NewWriterLevel(writer, compressionLevel)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to create GZIP writer: %s", err)
 	}
 
-	return nil
+	if err = WriteTarArchive(srcDirPath, gzipWriter, packCtx); err != nil {
+		gzipWriter.Close()
+		return err
+	}
+	return gzipWriter.Close()
 }
 
 // WriteTarArchive creates Tar archive of specified path
-// using specified writer
-func WriteTarArchive(srcDirPath string, compressWriter io.Writer,
-	pkgFiles map[string]packFileInfo) error {
+// using specified writer. Entries are sorted by relative path components, a directory goes
+// before its content. The duplicate files are written as hardlinks if dedup is enabled.
+// If SOURCE_DATE_EPOCH is set in pack context, file modification
+// times are clamped to it and numeric owner ids are reset for reproducible result.
+func WriteTarArchive(srcDirPath string, compressWriter io.Writer, packCtx *PackCtx) error {
+	files, err := collectPackFiles(packCtx, srcDirPath)
+	if err != nil {
+		return err
+	}
+	return writeTarFiles(NewOSFileSystem(srcDirPath), files, compressWriter, packCtx)
+}
+
+// WriteTarArchiveFS creates Tar archive of the file system content the same way as
+// WriteTarArchive does for the directory. Symlinks are written as is without checking
+// their targets.
+func WriteTarArchiveFS(fsys FileSystem, compressWriter io.Writer, packCtx *PackCtx) error {
+	files, err := collectFilesFS(packCtx.operation.context(), fsys, getJobsCount(packCtx))
+	if err != nil {
+		return err
+	}
+	return writeTarFiles(fsys, files, compressWriter, packCtx)
+}
+
+// writeTarFiles writes the collected files of the file system as Tar archive.
+func writeTarFiles(fsys FileSystem, files []collectedFile, compressWriter io.Writer,
+	packCtx *PackCtx) error {
+	sourceDateEpoch := packCtx.sourceDateEpoch
 	tarWriter := tar.NewWriter(compressWriter)
 	defer tarWriter.Close()
 
-	err := filepath.Walk(srcDirPath, func(filePath string, fileInfo os.FileInfo, err error) error {
-		if err != nil {
+	var err error
+	duplicates := map[string]string{}
+	if isDedupEnabled(packCtx) {
+		if duplicates, err = findDuplicateFiles(packCtx, fsys, files); err != nil {
 			return err
 		}
+	}
 
-		var tarHeader *tar.Header
-		if fileInfo.Mode().Type() == os.ModeSymlink &&
-			strings.Contains(filePath, configure.InstancesEnabledDirName) {
-			// If we have found a symlink while making tarball
-			// we should make it relative. Apriori it is known,
-			// that the source path of the link will be located
-			// in a directory higher.
-			srcPath, _ := filepath.EvalSymlinks(filePath)
-			resolvedPath := filepath.Join("..", filepath.Base(srcPath))
-			tarHeader, err = tar.FileInfoHeader(fileInfo, resolvedPath)
-			if err != nil {
+	writeEntry := func(file collectedFile) error {
+		var err error
+		fileInfo := file.info
+		linkTarget := ""
+		if fileInfo.Mode().Type() == os.ModeSymlink {
+			if linkTarget, err = fsys.ReadLink(file.name); err != nil {
 				return err
 			}
-		} else {
-			tarHeader, err = tar.FileInfoHeader(fileInfo, filePath)
-			if err != nil {
-				return err
+			if strings.Contains(getCollectedFilePath(file), configure.InstancesEnabledDirName) {
+				// If we have found a symlink while making tarball
+				// we should make it relative. Apriori it is known,
+				// that the source path of the link will be located
+				// in a directory higher.
+				srcBase, _ := getLinkTargetBase(fsys, file.name)
+				linkTarget = filepath.Join("..", srcBase)
 			}
 		}
-
-		relPath, err := filepath.Rel(srcDirPath, filePath)
+		tarHeader, err := tar.FileInfoHeader(fileInfo, linkTarget)
 		if err != nil {
-			return fmt.Errorf("failed to get relative path of %q: %s", filePath, err)
+			return err
 		}
-		if packFileInfo, found := pkgFiles[relPath]; found {
-			tarHeader.Uname = packFileInfo.owner
-			tarHeader.Gname = packFileInfo.group
-		} else {
-			tarHeader.Uname = defaultFileUser
-			tarHeader.Gname = defaultFileGroup
+
+		relPath := file.relPath
+		packFileInfo := packCtx.RpmDeb.getPackFileInfo(relPath, fileInfo.Mode())
+		tarHeader.Uname = packFileInfo.owner
+		tarHeader.Gname = packFileInfo.group
+		tarHeader.Mode = tarHeader.Mode&^int64(os.ModePerm) | int64(packFileInfo.perm)
+
+		tarHeader.Name = relPath
+		if target, found := duplicates[relPath]; found {
+			tarHeader.Typeflag = tar.TypeLink
+			tarHeader.Linkname = target
+			tarHeader.Size = 0
 		}
 
-		tarHeader.Name, err = filepath.Rel(srcDirPath, filePath)
-		if err != nil {
-			return err
+		if sourceDateEpoch != nil {
+			tarHeader.ModTime = clampModTime(tarHeader.ModTime, *sourceDateEpoch)
+			tarHeader.AccessTime = time.Time{}
+			tarHeader.ChangeTime = time.Time{}
+			tarHeader.Uid = 0
+			tarHeader.Gid = 0
 		}
 
 		if err := tarWriter.WriteHeader(tarHeader); err != nil {
 			return err
 		}
 
-		if fileInfo.Mode().IsRegular() {
-			if err := writeFileToWriter(filePath, tarWriter); err != nil {
+		if tarHeader.Typeflag == tar.TypeReg {
+			if err := writeFSFileToWriter(fsys, file.name, tarWriter); err != nil {
 				return err
 			}
 		}
+		if !fileInfo.IsDir() {
+			packCtx.progress.fileAdded(tarHeader.Name, fileInfo.Size())
+		}
 		return nil
-	})
-	if err != nil {
-		return err
+	}
+	for _, file := range files {
+		if err = packCtx.operation.processFile(getCollectedFilePath(file)); err != nil {
+			return err
+		}
+		if err = writeEntry(file); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -129,6 +187,18 @@ func CompressGzip(srcFilePath string, destFilePath string) error {
 	return nil
 }
 
+// writeFSFileToWriter copies the content of the file system file into the writer.
+func writeFSFileToWriter(fsys FileSystem, name string, writer io.Writer) error {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+	return err
+}
+
 func writeFileToWriter(filePath string, writer io.Writer) error {
 	file, err := os.Open(filePath)
 	if err != nil {
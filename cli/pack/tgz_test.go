@@ -0,0 +1,156 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTgzArchiveCompressionLevel(t *testing.T) {
+	srcDir := t.TempDir()
+	content := bytes.Repeat([]byte("tarantool "), 1000)
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "init.lua"), content, 0644))
+
+	sizes := map[int]int64{}
+	for _, level := range []int{0, DefaultCompressionLevel, 9} {
+		tgzPath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+		require.NoError(t, writeTgzArchive(srcDir, tgzPath, PackCtx{}, level))
+
+		file, err := os.Open(tgzPath)
+		require.NoError(t, err)
+		defer file.Close()
+		gzipReader, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		tarReader := tar.NewReader(gzipReader)
+
+		found := false
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			if header.Name == "init.lua" {
+				data, err := io.ReadAll(tarReader)
+				require.NoError(t, err)
+				assert.Equal(t, content, data)
+				found = true
+			}
+		}
+		assert.True(t, found)
+
+		stat, err := os.Stat(tgzPath)
+		require.NoError(t, err)
+		sizes[level] = stat.Size()
+	}
+	// Stored archive is bigger than the file itself.
+	assert.Greater(t, sizes[0], int64(len(content)))
+	assert.Less(t, sizes[DefaultCompressionLevel], sizes[0])
+}
+
+func TestWriteTgzArchiveReproducible(t *testing.T) {
+	epoch := time.Unix(1700000000, 0)
+	packCtx := PackCtx{sourceDateEpoch: &epoch}
+
+	var archives [][]byte
+	for i := 0; i < 2; i++ {
+		srcDir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(srcDir, "app"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "app", "init.lua"),
+			[]byte("return 1"), 0644))
+
+		tgzPath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+		require.NoError(t, writeTgzArchive(srcDir, tgzPath, packCtx, DefaultCompressionLevel))
+		content, err := os.ReadFile(tgzPath)
+		require.NoError(t, err)
+		archives = append(archives, content)
+
+		gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+		require.NoError(t, err)
+		tarReader := tar.NewReader(gzipReader)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			assert.True(t, epoch.Equal(header.ModTime), header.Name)
+			assert.Equal(t, 0, header.Uid)
+			assert.Equal(t, 0, header.Gid)
+			assert.Equal(t, defaultFileUser, header.Uname)
+		}
+	}
+	assert.Equal(t, archives[0], archives[1])
+}
+
+func TestWriteTgzArchiveOrder(t *testing.T) {
+	// Entries are created in the reverse order to not depend on the file system order.
+	relPaths := []string{"z.lua", "b.lua", "a-b.lua", "a/c/d.lua", "a/b.lua"}
+	expected := []string{".", "a", "a/b.lua", "a/c", "a/c/d.lua", "a-b.lua", "b.lua", "z.lua"}
+
+	var archives [][]byte
+	for _, jobs := range []int{1, 4} {
+		srcDir := t.TempDir()
+		for _, relPath := range relPaths {
+			path := filepath.Join(srcDir, relPath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(relPath), 0644))
+		}
+		epoch := time.Unix(1700000000, 0)
+		packCtx := PackCtx{Jobs: jobs, sourceDateEpoch: &epoch}
+
+		var buf bytes.Buffer
+		require.NoError(t, writeTgz(srcDir, &buf, &packCtx, DefaultCompressionLevel))
+		archives = append(archives, buf.Bytes())
+
+		gzipReader, err := gzip.NewReader(&buf)
+		require.NoError(t, err)
+		tarReader := tar.NewReader(gzipReader)
+		var names []string
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, header.Name)
+		}
+		assert.Equal(t, expected, names)
+	}
+	assert.Equal(t, archives[0], archives[1])
+}
+
+func TestWriteTgzArchiveSymlink(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "app", "lib"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "app", "lib", "mod.lua"), nil, 0644))
+	require.NoError(t, os.Symlink(filepath.Join("lib", "mod.lua"),
+		filepath.Join(srcDir, "app", "mod.lua")))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTgz(srcDir, &buf, &PackCtx{}, DefaultCompressionLevel))
+
+	gzipReader, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	links := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeSymlink {
+			links[header.Name] = header.Linkname
+		}
+	}
+	assert.Equal(t, map[string]string{"app/mod.lua": "lib/mod.lua"}, links)
+}
@@ -0,0 +1,88 @@
+package pack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/apex/log"
+)
+
+// getTmpDir returns the directory for the intermediate files: TmpDir if it is set or
+// the system temporary directory, TMPDIR if it is set.
+func getTmpDir(packCtx *PackCtx) string {
+	if packCtx.TmpDir != "" {
+		return packCtx.TmpDir
+	}
+	return os.TempDir()
+}
+
+// checkDirWritable checks a file can be created in the directory.
+func checkDirWritable(dir string) error {
+	checkFile, err := os.CreateTemp(dir, ".tt_pack")
+	if err != nil {
+		return err
+	}
+	checkFile.Close()
+	if err = os.Remove(checkFile.Name()); err != nil {
+		log.Warnf("Failed to remove a file %s: %s", checkFile.Name(), err)
+	}
+	return nil
+}
+
+// isSameFileSystem returns true if the paths are on the same file system.
+func isSameFileSystem(path1, path2 string) (bool, error) {
+	devices := [2]uint64{}
+	for i, path := range []string{path1, path2} {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		sysStat, ok := stat.Sys().(*syscall.Stat_t)
+		if !ok {
+			return false, fmt.Errorf("failed to get file system of %q", path)
+		}
+		devices[i] = uint64(sysStat.Dev)
+	}
+	return devices[0] == devices[1], nil
+}
+
+// initTmpDir checks the temporary directory set by --tmp-dir is writable. The temporary
+// package files are written to it if it is on the same file system as the output directory,
+// so the package is still moved to the result path atomically. They are written next to
+// the result package otherwise or if --tmp-dir is not set, so the stale temporary file left
+// by the killed pack is replaced by the next one.
+func initTmpDir(packCtx *PackCtx) error {
+	if packCtx.TmpDir == "" {
+		return nil
+	}
+	tmpDir, err := filepath.Abs(packCtx.TmpDir)
+	if err != nil {
+		return fmt.Errorf("cannot get absolute path of temporary directory %q: %s",
+			packCtx.TmpDir, err)
+	}
+	if stat, err := os.Stat(tmpDir); err != nil || !stat.IsDir() {
+		return fmt.Errorf("temporary directory %q is not a directory", tmpDir)
+	}
+	if err = checkDirWritable(tmpDir); err != nil {
+		return fmt.Errorf("temporary directory %q is not writable: %s", tmpDir, err)
+	}
+	packCtx.TmpDir = tmpDir
+
+	outputDir := packCtx.OutputDir
+	if outputDir == "" {
+		if outputDir, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+	sameFileSystem, err := isSameFileSystem(tmpDir, outputDir)
+	if err != nil || !sameFileSystem {
+		log.Warnf("Temporary directory %s is not on the file system of the output directory %s. "+
+			"The temporary package file is written to the output directory to move it "+
+			"atomically", tmpDir, outputDir)
+		return nil
+	}
+	packCtx.artifactTmpDir = tmpDir
+	return nil
+}
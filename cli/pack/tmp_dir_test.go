@@ -0,0 +1,64 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_initTmpDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "out")
+	require.NoError(t, os.Mkdir(outputDir, 0755))
+	packCtx := &PackCtx{TmpDir: tmpDir, OutputDir: outputDir}
+	require.NoError(t, initTmpDir(packCtx))
+	assert.Equal(t, tmpDir, packCtx.TmpDir)
+	assert.Equal(t, tmpDir, getTmpDir(packCtx))
+	// The directories are on the same file system.
+	assert.Equal(t, tmpDir, packCtx.artifactTmpDir)
+
+	packCtx = &PackCtx{TmpDir: filepath.Join(tmpDir, "missing"), OutputDir: outputDir}
+	assert.ErrorContains(t, initTmpDir(packCtx), "is not a directory")
+
+	filePath := filepath.Join(tmpDir, "file")
+	require.NoError(t, os.WriteFile(filePath, []byte{}, 0644))
+	packCtx = &PackCtx{TmpDir: filePath, OutputDir: outputDir}
+	assert.ErrorContains(t, initTmpDir(packCtx), "is not a directory")
+
+	if os.Getuid() != 0 {
+		readOnlyDir := filepath.Join(tmpDir, "read-only")
+		require.NoError(t, os.Mkdir(readOnlyDir, 0555))
+		packCtx = &PackCtx{TmpDir: readOnlyDir, OutputDir: outputDir}
+		assert.ErrorContains(t, initTmpDir(packCtx), "is not writable")
+	}
+
+	// The system temporary directory is used by default. The temporary package file is
+	// written next to the package in this case.
+	assert.Equal(t, os.TempDir(), getTmpDir(&PackCtx{}))
+	packCtx = &PackCtx{OutputDir: outputDir}
+	require.NoError(t, initTmpDir(packCtx))
+	assert.Empty(t, packCtx.artifactTmpDir)
+	assert.Empty(t, packCtx.TmpDir)
+}
+
+func Test_writePackageFileTmpDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	packagePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	packCtx := PackCtx{artifactTmpDir: tmpDir}
+
+	require.NoError(t, writePackageFile(&packCtx, packagePath, func(path string) error {
+		assert.Equal(t, tmpDir, filepath.Dir(path))
+		assert.True(t, strings.HasPrefix(filepath.Base(path), ".bundle.tar.gz."))
+		return os.WriteFile(path, []byte("package"), 0644)
+	}))
+	content, err := os.ReadFile(packagePath)
+	require.NoError(t, err)
+	assert.Equal(t, "package", string(content))
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,646 @@
+package pack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tarantool/tt/cli/configure"
+)
+
+// PackageApp describes an application of the verified package.
+type PackageApp struct {
+	// Name is an application name.
+	Name string `json:"name"`
+	// Files is a count of application files. It is zero if the package has no manifest.
+	Files int `json:"files,omitempty"`
+}
+
+// PackageBinary describes a binary of the verified package recorded in the manifest.
+type PackageBinary struct {
+	// Name is a binary name.
+	Name string `json:"name"`
+	// Version is a detected binary version.
+	Version string `json:"version,omitempty"`
+	// SHA256 is a checksum of the binary.
+	SHA256 string `json:"sha256"`
+}
+
+// PackageInfo describes the content of the verified package.
+type PackageInfo struct {
+	// Path is a path of the package file.
+	Path string `json:"path"`
+	// Type is a package type: tgz, zip, deb or rpm.
+	Type string `json:"type"`
+	// HasManifest is set if the package contains the bundle manifest. The package
+	// structure is inferred from the file names otherwise.
+	HasManifest bool `json:"has_manifest"`
+	// Name is a package name from the manifest.
+	Name string `json:"name,omitempty"`
+	// Version is a package version from the manifest.
+	Version string `json:"version,omitempty"`
+	// TtVersion is a version of tt packed the bundle from the manifest or, if it is
+	// not recorded there, from the Deb or RPM metadata.
+	TtVersion string `json:"tt_version,omitempty"`
+	// TarantoolVersion is a version of the bundled tarantool from the manifest.
+	TarantoolVersion string `json:"tarantool_version,omitempty"`
+	// BuildTime is a bundle build time from the manifest.
+	BuildTime string `json:"build_time,omitempty"`
+	// Labels are the key=value metadata from the manifest.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Apps are the packed applications.
+	Apps []PackageApp `json:"apps"`
+	// TarantoolBundled is set if the package contains the tarantool binary.
+	TarantoolBundled bool `json:"tarantool_bundled"`
+	// TtBundled is set if the package contains the tt binary.
+	TtBundled bool `json:"tt_bundled"`
+	// Binaries are the bundled binaries recorded in the manifest.
+	Binaries []PackageBinary `json:"binaries,omitempty"`
+	// Files is a count of non-directory package entries.
+	Files int `json:"files"`
+}
+
+// packageEntry is an entry of the verified package.
+type packageEntry struct {
+	// name is a slash-separated entry path relative to the package root.
+	name string
+	// isDir is set for the directory entry.
+	isDir bool
+	// size is a size of the entry content.
+	size int64
+	// mode is the entry file mode.
+	mode fs.FileMode
+	// content is a reader of the regular file content. It is nil for the other entries.
+	content io.Reader
+}
+
+// entryVisitor is called for each package entry. The unread entry content is
+// read after the call to check the package integrity.
+type entryVisitor func(entry packageEntry) error
+
+// Package file signatures.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic  = []byte("PK\x03\x04")
+	arMagic   = []byte("!<arch>\n")
+	rpmMagic  = []byte{0xed, 0xab, 0xee, 0xdb}
+)
+
+// rpmLeadSize is a size of RPM package lead section.
+const rpmLeadSize = 96
+
+// detectPackageType returns the package type by the file signature.
+func detectPackageType(file io.ReaderAt) (string, error) {
+	signature := make([]byte, len(arMagic))
+	count, err := file.ReadAt(signature, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	signature = signature[:count]
+	switch {
+	case bytes.HasPrefix(signature, gzipMagic), bytes.HasPrefix(signature, zstdMagic):
+		return Tgz, nil
+	case bytes.HasPrefix(signature, zipMagic):
+		return Zip, nil
+	case bytes.HasPrefix(signature, arMagic):
+		return Deb, nil
+	case bytes.HasPrefix(signature, rpmMagic):
+		return Rpm, nil
+	}
+	return "", fmt.Errorf("unknown package format, expected one of: %s, %s, %s, %s",
+		Tgz, Zip, Deb, Rpm)
+}
+
+// newDecompressor returns a reader of the compressed stream. Compressor is detected
+// by the stream signature.
+func newDecompressor(reader io.Reader) (io.ReadCloser, error) {
+	bufReader := bufio.NewReader(reader)
+	signature, err := bufReader.Peek(len(zstdMagic))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(signature, zstdMagic) {
+		return newZstdReader(bufReader)
+	}
+	return gzip.NewReader(bufReader)
+}
+
+// visitEntry calls the visitor and reads the rest of the entry content.
+func visitEntry(visit entryVisitor, entry packageEntry) error {
+	if err := visit(entry); err != nil {
+		return err
+	}
+	if entry.content != nil {
+		if _, err := io.Copy(io.Discard, entry.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkTarEntries visits the entries of the compressed tarball.
+func walkTarEntries(reader io.Reader, visit entryVisitor) error {
+	decompressor, err := newDecompressor(reader)
+	if err != nil {
+		return err
+	}
+	defer decompressor.Close()
+	tarReader := tar.NewReader(decompressor)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		entry := packageEntry{name: header.Name, isDir: header.Typeflag == tar.TypeDir,
+			size: header.Size, mode: header.FileInfo().Mode()}
+		if header.Typeflag == tar.TypeReg {
+			entry.content = tarReader
+		}
+		if err = visitEntry(visit, entry); err != nil {
+			return err
+		}
+	}
+	// Read the stream till the end to check the compressed data checksum.
+	_, err = io.Copy(io.Discard, decompressor)
+	return err
+}
+
+// walkZipEntries visits the entries of the zip archive.
+func walkZipEntries(file io.ReaderAt, size int64, visit entryVisitor) error {
+	zipReader, err := zip.NewReader(file, size)
+	if err != nil {
+		return err
+	}
+	for _, zipFile := range zipReader.File {
+		entry := packageEntry{name: zipFile.Name, isDir: zipFile.FileInfo().IsDir(),
+			size: int64(zipFile.UncompressedSize64), mode: zipFile.Mode()}
+		if zipFile.Mode().IsRegular() {
+			content, err := zipFile.Open()
+			if err != nil {
+				return err
+			}
+			entry.content = content
+			err = visitEntry(visit, entry)
+			content.Close()
+		} else {
+			err = visitEntry(visit, entry)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", zipFile.Name, err)
+		}
+	}
+	return nil
+}
+
+// readDebControlField returns the field value of the Deb control file.
+func readDebControlField(reader io.Reader, field string) (string, error) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if name, value, found := strings.Cut(scanner.Text(), ":"); found && name == field {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// walkDebEntries visits the entries of the Deb package data archive. Returns the version
+// of tt packed the package from the control file.
+func walkDebEntries(reader io.Reader, visit entryVisitor) (string, error) {
+	if _, err := io.CopyN(io.Discard, reader, int64(len(arMagic))); err != nil {
+		return "", err
+	}
+	ttVersion := ""
+	dataFound := false
+	header := make([]byte, 60)
+	for {
+		if _, err := io.ReadFull(reader, header); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("failed to read ar member header: %s", err)
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || string(header[58:60]) != "`\n" {
+			return "", fmt.Errorf("invalid ar member header")
+		}
+		member := io.LimitReader(reader, size)
+		if strings.HasPrefix(name, "control.tar") {
+			err = walkTarEntries(member, func(entry packageEntry) error {
+				if entry.content == nil || getEntryPath(entry) != "control" {
+					return nil
+				}
+				var readErr error
+				ttVersion, readErr = readDebControlField(entry.content, debTtVersionField)
+				return readErr
+			})
+			if err != nil {
+				return "", fmt.Errorf("%s: %s", name, err)
+			}
+		}
+		if strings.HasPrefix(name, "data.tar") {
+			if err = walkTarEntries(member, visit); err != nil {
+				return "", fmt.Errorf("%s: %s", name, err)
+			}
+			dataFound = true
+		}
+		if _, err = io.Copy(io.Discard, member); err != nil {
+			return "", err
+		}
+		// Members are aligned to 2 bytes.
+		if size%2 != 0 {
+			if _, err = io.CopyN(io.Discard, reader, 1); err != nil {
+				return "", err
+			}
+		}
+	}
+	if !dataFound {
+		return "", fmt.Errorf("data archive is not found")
+	}
+	return ttVersion, nil
+}
+
+// readRpmHeader reads RPM header structure. The size is aligned to the passed alignment.
+// Returns the string tags values.
+func readRpmHeader(reader io.Reader, alignment int64) (map[int]string, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(reader, intro); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(intro[0:3], []byte{0x8e, 0xad, 0xe8}) {
+		return nil, fmt.Errorf("invalid RPM header magic")
+	}
+	indexCount := int64(binary.BigEndian.Uint32(intro[8:12]))
+	dataSize := int64(binary.BigEndian.Uint32(intro[12:16]))
+	size := indexCount*16 + dataSize
+	if padding := (16 + size) % alignment; padding != 0 {
+		size += alignment - padding
+	}
+	header := make([]byte, size)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+
+	data := header[indexCount*16 : indexCount*16+dataSize]
+	tags := map[int]string{}
+	for i := int64(0); i < indexCount; i++ {
+		entry := header[i*16 : i*16+16]
+		if binary.BigEndian.Uint32(entry[4:8]) != rpmTypeString {
+			continue
+		}
+		offset := int64(binary.BigEndian.Uint32(entry[8:12]))
+		if offset >= dataSize {
+			return nil, fmt.Errorf("invalid RPM header tag offset")
+		}
+		value, _, _ := bytes.Cut(data[offset:], []byte{0})
+		tags[int(binary.BigEndian.Uint32(entry[0:4]))] = string(value)
+	}
+	return tags, nil
+}
+
+// walkRpmEntries visits the entries of the RPM package payload. Returns the version
+// of tt packed the package from the header.
+func walkRpmEntries(reader io.Reader, visit entryVisitor) (string, error) {
+	if _, err := io.CopyN(io.Discard, reader, rpmLeadSize); err != nil {
+		return "", err
+	}
+	// Signature header is aligned to 8 bytes.
+	if _, err := readRpmHeader(reader, 8); err != nil {
+		return "", fmt.Errorf("signature: %s", err)
+	}
+	tags, err := readRpmHeader(reader, 1)
+	if err != nil {
+		return "", fmt.Errorf("header: %s", err)
+	}
+	ttVersion := ""
+	if packer, found := tags[tagRpmVersion]; found {
+		ttVersion = strings.TrimPrefix(packer, rpmPackerPrefix)
+		if ttVersion == packer {
+			// The package is built by another tool.
+			ttVersion = ""
+		}
+	}
+	decompressor, err := newDecompressor(reader)
+	if err != nil {
+		return "", fmt.Errorf("payload: %s", err)
+	}
+	defer decompressor.Close()
+	if err = walkCpioEntries(decompressor, visit); err != nil {
+		return "", fmt.Errorf("payload: %s", err)
+	}
+	_, err = io.Copy(io.Discard, decompressor)
+	return ttVersion, err
+}
+
+// cpioHeaderSize is a size of newc cpio entry header.
+const cpioHeaderSize = 110
+
+// cpioTrailer is a name of the last cpio archive entry.
+const cpioTrailer = "TRAILER!!!"
+
+// getCpioFileMode converts the cpio entry mode containing the file type bits of stat
+// structure to the file mode.
+func getCpioFileMode(mode uint64) fs.FileMode {
+	fileMode := fs.FileMode(mode & 0777)
+	switch mode & 0170000 {
+	case 0100000:
+	case 0040000:
+		fileMode |= fs.ModeDir
+	case 0120000:
+		fileMode |= fs.ModeSymlink
+	default:
+		fileMode |= fs.ModeIrregular
+	}
+	return fileMode
+}
+
+// walkCpioEntries visits the entries of newc format cpio archive.
+func walkCpioEntries(reader io.Reader, visit entryVisitor) error {
+	header := make([]byte, cpioHeaderSize)
+	// skipPadding skips the padding aligning the data to 4 bytes.
+	skipPadding := func(size int64) error {
+		_, err := io.CopyN(io.Discard, reader, (4-size%4)%4)
+		return err
+	}
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return fmt.Errorf("failed to read cpio header: %s", err)
+		}
+		if magic := string(header[0:6]); magic != "070701" && magic != "070702" {
+			return fmt.Errorf("invalid cpio header magic %q", magic)
+		}
+		mode, err1 := strconv.ParseUint(string(header[14:22]), 16, 32)
+		fileSize, err2 := strconv.ParseInt(string(header[54:62]), 16, 64)
+		nameSize, err3 := strconv.ParseInt(string(header[94:102]), 16, 64)
+		if err := errors.Join(err1, err2, err3); err != nil {
+			return fmt.Errorf("invalid cpio header: %s", err)
+		}
+		name := make([]byte, nameSize)
+		if _, err := io.ReadFull(reader, name); err != nil {
+			return err
+		}
+		if err := skipPadding(cpioHeaderSize + nameSize); err != nil {
+			return err
+		}
+		entryName := strings.TrimRight(string(name), "\x00")
+		if entryName == cpioTrailer {
+			return nil
+		}
+
+		content := io.LimitReader(reader, fileSize)
+		// The mode contains the file type bits of stat structure.
+		entry := packageEntry{name: entryName, isDir: mode&0170000 == 0040000,
+			size: fileSize, mode: getCpioFileMode(mode)}
+		if mode&0170000 == 0100000 {
+			entry.content = content
+		}
+		if err := visitEntry(visit, entry); err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, content); err != nil {
+			return err
+		}
+		if err := skipPadding(fileSize); err != nil {
+			return err
+		}
+	}
+}
+
+// packageContent collects the package structure while visiting the entries.
+type packageContent struct {
+	// files are the paths of non-directory entries.
+	files []string
+	// manifest is the shallowest bundle manifest found.
+	manifest *bundleManifest
+	// manifestDir is a directory of the manifest.
+	manifestDir string
+	// ttVersion is a version of tt packed the package from the Deb or RPM metadata.
+	ttVersion string
+}
+
+// pathDepth returns a count of the path components.
+func pathDepth(entryPath string) int {
+	return strings.Count(entryPath, "/")
+}
+
+// getEntryPath returns the clean entry path relative to the package root.
+func getEntryPath(entry packageEntry) string {
+	return path.Clean(strings.TrimPrefix(entry.name, "./"))
+}
+
+// visit records the package entry.
+func (content *packageContent) visit(entry packageEntry) error {
+	name := getEntryPath(entry)
+	if entry.isDir {
+		return nil
+	}
+	content.files = append(content.files, name)
+	if entry.content == nil || path.Base(name) != manifestFileName {
+		return nil
+	}
+	if content.manifest != nil && pathDepth(content.manifestDir) <= pathDepth(path.Dir(name)) {
+		return nil
+	}
+	manifest := bundleManifest{}
+	if err := json.NewDecoder(entry.content).Decode(&manifest); err != nil {
+		return fmt.Errorf("invalid %s: %s", name, err)
+	}
+	content.manifest = &manifest
+	content.manifestDir = path.Dir(name)
+	return nil
+}
+
+// envRoot returns the directory of the tt environment in the package. It is a directory
+// containing the manifest or the shallowest tt configuration file.
+func (content *packageContent) envRoot() string {
+	if content.manifest != nil {
+		return content.manifestDir
+	}
+	root := ""
+	for _, file := range content.files {
+		if path.Base(file) != configure.ConfigName {
+			continue
+		}
+		if dir := path.Dir(file); root == "" || pathDepth(dir) < pathDepth(root) {
+			root = dir
+		}
+	}
+	if root == "" {
+		return "."
+	}
+	return root
+}
+
+// fillInfo fills the package info from the collected content.
+func (content *packageContent) fillInfo(info *PackageInfo) {
+	info.Files = len(content.files)
+	root := content.envRoot()
+	binDir := path.Join(root, configure.BinPath)
+	fileSet := make(map[string]bool, len(content.files))
+	for _, file := range content.files {
+		fileSet[file] = true
+	}
+	info.TarantoolBundled = fileSet[path.Join(binDir, "tarantool")]
+	info.TtBundled = fileSet[path.Join(binDir, "tt")]
+
+	info.TtVersion = content.ttVersion
+	info.Apps = []PackageApp{}
+	if content.manifest != nil {
+		info.HasManifest = true
+		info.Name = content.manifest.Name
+		info.Version = content.manifest.Version
+		if content.manifest.TtVersion != "" {
+			info.TtVersion = content.manifest.TtVersion
+		}
+		info.TarantoolVersion = content.manifest.TarantoolVersion
+		info.BuildTime = content.manifest.BuildTime
+		info.Labels = content.manifest.Labels
+		for _, app := range content.manifest.Apps {
+			info.Apps = append(info.Apps, PackageApp{Name: app.Name, Files: app.Files})
+		}
+		for _, binary := range content.manifest.Binaries {
+			info.Binaries = append(info.Binaries, PackageBinary(binary))
+		}
+		return
+	}
+
+	// Applications are inferred from the instances enabled directory entries.
+	instancesEnabled := path.Join(root, configure.InstancesEnabledDirName) + "/"
+	apps := map[string]bool{}
+	for _, file := range content.files {
+		if !strings.HasPrefix(file, instancesEnabled) {
+			continue
+		}
+		appName := strings.Split(strings.TrimPrefix(file, instancesEnabled), "/")[0]
+		apps[strings.TrimSuffix(appName, ".lua")] = true
+	}
+	for appName := range apps {
+		info.Apps = append(info.Apps, PackageApp{Name: appName})
+	}
+	sort.Slice(info.Apps, func(i, j int) bool {
+		return info.Apps[i].Name < info.Apps[j].Name
+	})
+}
+
+// VerifyPackage reads the whole tgz, zip, deb or rpm package to check its integrity and
+// describes the package content. The embedded manifest is used if it exists.
+func VerifyPackage(packagePath string) (PackageInfo, error) {
+	return verifyPackage(packagePath, nil)
+}
+
+// verifyPackage verifies the package like VerifyPackage. The inventory is called for
+// each package entry if it is set.
+func verifyPackage(packagePath string, inventory func(file PackageFile) error) (
+	PackageInfo, error) {
+	info := PackageInfo{Path: packagePath}
+	file, err := os.Open(packagePath)
+	if err != nil {
+		return info, fmt.Errorf("cannot open package: %s", err)
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return info, fmt.Errorf("cannot open package: %s", err)
+	}
+
+	if info.Type, err = detectPackageType(file); err != nil {
+		return info, fmt.Errorf("cannot verify %q: %s", packagePath, err)
+	}
+	content := packageContent{}
+	visit := content.visit
+	if inventory != nil {
+		visit = func(entry packageEntry) error {
+			return visitInventoryEntry(entry, content.visit, inventory)
+		}
+	}
+	switch info.Type {
+	case Tgz:
+		err = walkTarEntries(file, visit)
+	case Zip:
+		err = walkZipEntries(file, stat.Size(), visit)
+	case Deb:
+		content.ttVersion, err = walkDebEntries(bufio.NewReader(file), visit)
+	case Rpm:
+		content.ttVersion, err = walkRpmEntries(bufio.NewReader(file), visit)
+	}
+	if err != nil {
+		return info, fmt.Errorf("package %q is corrupted: %s", packagePath, err)
+	}
+	content.fillInfo(&info)
+	return info, nil
+}
+
+// PrintPackageInfo writes the human-readable package description.
+func PrintPackageInfo(info PackageInfo, writer io.Writer) {
+	fmt.Fprintf(writer, "Package: %s\n", info.Path)
+	fmt.Fprintf(writer, "Type: %s\n", info.Type)
+	if info.HasManifest {
+		fmt.Fprintf(writer, "Name: %s\n", info.Name)
+		fmt.Fprintf(writer, "Version: %s\n", info.Version)
+		fmt.Fprintf(writer, "Packed by tt: %s\n", info.TtVersion)
+		fmt.Fprintf(writer, "Build time: %s\n", info.BuildTime)
+		if len(info.Labels) > 0 {
+			fmt.Fprintf(writer, "Labels:\n")
+			keys := make([]string, 0, len(info.Labels))
+			for key := range info.Labels {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Fprintf(writer, "  %s=%s\n", key, info.Labels[key])
+			}
+		}
+	} else {
+		fmt.Fprintf(writer, "Manifest: not found, the structure is inferred\n")
+		if info.TtVersion != "" {
+			fmt.Fprintf(writer, "Packed by tt: %s\n", info.TtVersion)
+		}
+	}
+	fmt.Fprintf(writer, "Files: %d\n", info.Files)
+	fmt.Fprintf(writer, "Applications:\n")
+	for _, app := range info.Apps {
+		if app.Files > 0 {
+			fmt.Fprintf(writer, "  %s (%d files)\n", app.Name, app.Files)
+		} else {
+			fmt.Fprintf(writer, "  %s\n", app.Name)
+		}
+	}
+	tarantool := "not bundled"
+	if info.TarantoolBundled {
+		tarantool = "bundled"
+		if info.TarantoolVersion != "" {
+			tarantool += ", version " + info.TarantoolVersion
+		}
+	}
+	fmt.Fprintf(writer, "Tarantool: %s\n", tarantool)
+	tt := "not bundled"
+	if info.TtBundled {
+		tt = "bundled"
+	}
+	fmt.Fprintf(writer, "tt: %s\n", tt)
+	if len(info.Binaries) > 0 {
+		fmt.Fprintf(writer, "Binaries:\n")
+		for _, binary := range info.Binaries {
+			binVersion := binary.Version
+			if binVersion == "" {
+				binVersion = "unknown version"
+			}
+			fmt.Fprintf(writer, "  %s %s, sha256 %s\n", binary.Name, binVersion, binary.SHA256)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{"name": "bundle", "version": "1.2.3", "tt_version": "2.4.0",
+"tarantool_version": "3.0.1", "build_time": "2024-01-02T03:04:05Z",
+"apps": [{"name": "app1", "files": 3}, {"name": "app2", "files": 1}]}`
+
+// testBundleEntries are the entries of the bundle with the manifest.
+var testBundleEntries = [][2]string{
+	{"bin/", ""},
+	{"bin/tarantool", "tarantool"},
+	{"bin/tt", "tt"},
+	{"instances.enabled/", ""},
+	{"instances.enabled/app1", "->../app1"},
+	{"manifest.json", testManifest},
+	{"tt.yaml", "env:\n"},
+}
+
+// writeTestCpio writes the newc cpio archive with the regular files.
+func writeTestCpio(t *testing.T, entries [][2]string) []byte {
+	buffer := bytes.Buffer{}
+	pad := func() {
+		for buffer.Len()%4 != 0 {
+			buffer.WriteByte(0)
+		}
+	}
+	for i, entry := range append(entries, [2]string{cpioTrailer, ""}) {
+		name, content := entry[0], entry[1]
+		mode := 0100644
+		if strings.HasSuffix(name, "/") {
+			name, mode = strings.TrimSuffix(name, "/"), 040755
+		}
+		fmt.Fprintf(&buffer, "070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+			i, mode, 0, 0, 1, 0, len(content), 0, 0, 0, 0, len(name)+1, 0)
+		buffer.WriteString(name + "\x00")
+		pad()
+		buffer.WriteString(content)
+		pad()
+	}
+	return buffer.Bytes()
+}
+
+// emptyRpmHeader is the RPM header structure without tags.
+var emptyRpmHeader = []byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+// writeTestRpm writes the RPM package with empty headers and the passed payload files.
+func writeTestRpm(t *testing.T, entries [][2]string) string {
+	return writeTestRpmWithHeader(t, emptyRpmHeader, entries)
+}
+
+// writeTestRpmWithHeader writes the RPM package with the empty signature, the passed
+// header and payload files.
+func writeTestRpmWithHeader(t *testing.T, header []byte, entries [][2]string) string {
+	buffer := bytes.Buffer{}
+	lead := make([]byte, rpmLeadSize)
+	copy(lead, rpmMagic)
+	buffer.Write(lead)
+	buffer.Write(emptyRpmHeader)
+	buffer.Write(header)
+	gzipWriter := gzip.NewWriter(&buffer)
+	_, err := gzipWriter.Write(writeTestCpio(t, entries))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	rpmPath := filepath.Join(t.TempDir(), "bundle-1.2.3-1.x86_64.rpm")
+	require.NoError(t, os.WriteFile(rpmPath, buffer.Bytes(), 0644))
+	return rpmPath
+}
+
+// writeTestDeb writes the Deb package with the data archive.
+func writeTestDeb(t *testing.T, dataTgz string) string {
+	return writeTestDebWithControl(t, "", dataTgz)
+}
+
+// writeTestDebWithControl writes the Deb package with the control archive if it is set
+// and the data archive.
+func writeTestDebWithControl(t *testing.T, controlTgz, dataTgz string) string {
+	type arMember struct {
+		name    string
+		content []byte
+	}
+	members := []arMember{{"debian-binary", []byte("2.0\n")}}
+	if controlTgz != "" {
+		control, err := os.ReadFile(controlTgz)
+		require.NoError(t, err)
+		members = append(members, arMember{"control.tar.gz", control})
+	}
+	data, err := os.ReadFile(dataTgz)
+	require.NoError(t, err)
+	members = append(members, arMember{"data.tar.gz", data})
+
+	buffer := bytes.Buffer{}
+	buffer.Write(arMagic)
+	for _, member := range members {
+		fmt.Fprintf(&buffer, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", member.name, 0, 0, 0, "100644",
+			len(member.content))
+		buffer.Write(member.content)
+		if len(member.content)%2 != 0 {
+			buffer.WriteByte('\n')
+		}
+	}
+	debPath := filepath.Join(t.TempDir(), "bundle_1.2.3-1_amd64.deb")
+	require.NoError(t, os.WriteFile(debPath, buffer.Bytes(), 0644))
+	return debPath
+}
+
+// writeTestZip writes the zip archive with the regular files.
+func writeTestZip(t *testing.T, entries [][2]string) string {
+	zipPath := filepath.Join(t.TempDir(), "bundle.zip")
+	file, err := os.Create(zipPath)
+	require.NoError(t, err)
+	defer file.Close()
+	zipWriter := zip.NewWriter(file)
+	for _, entry := range entries {
+		writer, err := zipWriter.Create(entry[0])
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(entry[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	return zipPath
+}
+
+func TestVerifyPackage(t *testing.T) {
+	expected := PackageInfo{
+		HasManifest:      true,
+		Name:             "bundle",
+		Version:          "1.2.3",
+		TtVersion:        "2.4.0",
+		TarantoolVersion: "3.0.1",
+		BuildTime:        "2024-01-02T03:04:05Z",
+		Apps:             []PackageApp{{"app1", 3}, {"app2", 1}},
+		TarantoolBundled: true,
+		TtBundled:        true,
+		Files:            5,
+	}
+	tgzPath := writeTestTgz(t, testBundleEntries)
+
+	// Deb and RPM packages contain the bundle in the install prefix directory.
+	prefixedEntries := [][2]string{{"./usr/", ""}, {"./usr/share/", ""}}
+	for _, entry := range testBundleEntries {
+		prefixedEntries = append(prefixedEntries, [2]string{"./usr/share/" + entry[0], entry[1]})
+	}
+	regularEntries := [][2]string{}
+	for _, entry := range testBundleEntries {
+		if !strings.HasSuffix(entry[0], "/") && !strings.HasPrefix(entry[1], "->") {
+			regularEntries = append(regularEntries, entry)
+		}
+	}
+
+	tests := []struct {
+		packageType string
+		path        string
+		files       int
+	}{
+		{Tgz, tgzPath, 5},
+		{Zip, writeTestZip(t, regularEntries), 4},
+		{Deb, writeTestDeb(t, writeTestTgz(t, prefixedEntries)), 5},
+		{Rpm, writeTestRpm(t, regularEntries), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.packageType, func(t *testing.T) {
+			info, err := VerifyPackage(tt.path)
+			require.NoError(t, err)
+			expectedInfo := expected
+			expectedInfo.Path = tt.path
+			expectedInfo.Type = tt.packageType
+			expectedInfo.Files = tt.files
+			assert.Equal(t, expectedInfo, info)
+		})
+	}
+}
+
+func TestVerifyPackageWithoutManifest(t *testing.T) {
+	tgzPath := writeTestTgz(t, [][2]string{
+		{"env/", ""},
+		{"env/tt.yaml", "env:\n"},
+		{"env/bin/tt", "tt"},
+		{"env/instances.enabled/app1", "->../app1"},
+		{"env/instances.enabled/script.lua", "->../script.lua"},
+		{"env/app1/tt.yaml", "app config"},
+	})
+	info, err := VerifyPackage(tgzPath)
+	require.NoError(t, err)
+	assert.Equal(t, PackageInfo{
+		Path:      tgzPath,
+		Type:      Tgz,
+		Apps:      []PackageApp{{Name: "app1"}, {Name: "script"}},
+		TtBundled: true,
+		Files:     5,
+	}, info)
+
+	output := bytes.Buffer{}
+	PrintPackageInfo(info, &output)
+	assert.Equal(t, "Package: "+tgzPath+"\nType: tgz\n"+
+		"Manifest: not found, the structure is inferred\nFiles: 5\n"+
+		"Applications:\n  app1\n  script\nTarantool: not bundled\ntt: bundled\n",
+		output.String())
+}
+
+func TestVerifyPackageTtVersionMetadata(t *testing.T) {
+	entries := [][2]string{{"tt.yaml", "env:\n"}}
+	header, err := packTagSet(rpmTagSetType{
+		{ID: tagName, Type: rpmTypeString, Value: "bundle"},
+		{ID: tagRpmVersion, Type: rpmTypeString, Value: rpmPackerPrefix + "2.5.0"},
+	}, headerImmutable)
+	require.NoError(t, err)
+	controlTgz := writeTestTgz(t, [][2]string{
+		{"./control", "Package: bundle\nDepends: \n" + debTtVersionField + ": 2.5.0\n"},
+	})
+
+	// The version is taken from the package metadata if the package has no manifest.
+	tests := []struct {
+		packageType string
+		path        string
+	}{
+		{Deb, writeTestDebWithControl(t, controlTgz, writeTestTgz(t, entries))},
+		{Rpm, writeTestRpmWithHeader(t, header.Bytes(), entries)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.packageType, func(t *testing.T) {
+			info, err := VerifyPackage(tt.path)
+			require.NoError(t, err)
+			assert.False(t, info.HasManifest)
+			assert.Equal(t, "2.5.0", info.TtVersion)
+
+			output := bytes.Buffer{}
+			PrintPackageInfo(info, &output)
+			assert.Contains(t, output.String(),
+				"Manifest: not found, the structure is inferred\nPacked by tt: 2.5.0\n")
+		})
+	}
+
+	// RPMVERSION of the package built by another tool is not the tt version.
+	header, err = packTagSet(rpmTagSetType{
+		{ID: tagRpmVersion, Type: rpmTypeString, Value: "4.18.0"},
+	}, headerImmutable)
+	require.NoError(t, err)
+	info, err := VerifyPackage(writeTestRpmWithHeader(t, header.Bytes(), entries))
+	require.NoError(t, err)
+	assert.Empty(t, info.TtVersion)
+}
+
+func TestVerifyPackageCorrupted(t *testing.T) {
+	tgzPath := writeTestTgz(t, [][2]string{{"init.lua",
+		strings.Repeat("box.cfg{}\n", 1000)}})
+	content, err := os.ReadFile(tgzPath)
+	require.NoError(t, err)
+	truncatedPath := filepath.Join(t.TempDir(), "truncated.tar.gz")
+	require.NoError(t, os.WriteFile(truncatedPath, content[:len(content)-10], 0644))
+	_, err = VerifyPackage(truncatedPath)
+	assert.ErrorContains(t, err, `package "`+truncatedPath+`" is corrupted`)
+
+	// Damaged gzip checksum.
+	content[len(content)-6] ^= 0xff
+	damagedPath := filepath.Join(t.TempDir(), "damaged.tar.gz")
+	require.NoError(t, os.WriteFile(damagedPath, content, 0644))
+	_, err = VerifyPackage(damagedPath)
+	assert.ErrorContains(t, err, "is corrupted: gzip: invalid checksum")
+
+	unknownPath := filepath.Join(t.TempDir(), "bundle.txt")
+	require.NoError(t, os.WriteFile(unknownPath, []byte("text"), 0644))
+	_, err = VerifyPackage(unknownPath)
+	assert.ErrorContains(t, err, "unknown package format")
+
+	debPath := writeTestDeb(t, tgzPath)
+	content, err = os.ReadFile(debPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(debPath, content[:200], 0644))
+	_, err = VerifyPackage(debPath)
+	assert.ErrorContains(t, err, "is corrupted")
+}
+
+func TestVerifyPackageLabels(t *testing.T) {
+	tgzPath := writeTestTgz(t, [][2]string{
+		{"manifest.json", `{"name": "bundle", "version": "1.2.3", "tt_version": "2.4.0",
+"build_time": "2024-01-02T03:04:05Z", "apps": [],
+"labels": {"git.sha": "abc123", "build": "42"}}`},
+	})
+	info, err := VerifyPackage(tgzPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"git.sha": "abc123", "build": "42"}, info.Labels)
+
+	output := bytes.Buffer{}
+	PrintPackageInfo(info, &output)
+	assert.Contains(t, output.String(), "Build time: 2024-01-02T03:04:05Z\n"+
+		"Labels:\n  build=42\n  git.sha=abc123\nFiles: 1\n")
+}
+
+func TestVerifyPackageBinaries(t *testing.T) {
+	tgzPath := writeTestTgz(t, [][2]string{
+		{"manifest.json", `{"name": "bundle", "version": "1.2.3", "tt_version": "2.4.0",
+"build_time": "2024-01-02T03:04:05Z", "apps": [],
+"binaries": [{"name": "tarantool", "version": "3.1.0", "sha256": "aaa"},
+{"name": "tt", "sha256": "bbb"}]}`},
+	})
+	info, err := VerifyPackage(tgzPath)
+	require.NoError(t, err)
+	assert.Equal(t, []PackageBinary{{"tarantool", "3.1.0", "aaa"}, {"tt", "", "bbb"}},
+		info.Binaries)
+
+	output := bytes.Buffer{}
+	PrintPackageInfo(info, &output)
+	assert.Contains(t, output.String(), "Binaries:\n  tarantool 3.1.0, sha256 aaa\n"+
+		"  tt unknown version, sha256 bbb\n")
+}
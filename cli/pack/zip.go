@@ -0,0 +1,174 @@
+package pack
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/configure"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// zipPacker is a structure that implements Packer interface
+// with specific zip archive packing behavior.
+type zipPacker struct {
+}
+
+// Run of zipPacker packs the bundle into zip archive.
+func (packer *zipPacker) Run(cmdCtx *cmdcontext.CmdCtx, packCtx *PackCtx,
+	opts *config.CliOpts) error {
+	bundlePath, err := prepareBundle(cmdCtx, packCtx, opts, true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := os.RemoveAll(bundlePath)
+		if err != nil {
+			log.Warnf("Failed to remove a temporary directory %s: %s",
+				bundlePath, err.Error())
+		}
+	}()
+
+	log.Debugf("The package structure is created in: %s", bundlePath)
+
+	zipSuffix, err := getZipSuffix()
+	if err != nil {
+		return err
+	}
+	zipName, err := getPackageFileName(packCtx, opts, zipSuffix, true)
+	if err != nil {
+		return err
+	}
+
+	if bundlePath, err = applyBundleRoot(packCtx, opts, bundlePath); err != nil {
+		return err
+	}
+
+	log.Infof("Creating zip archive.")
+
+	if zipName, err = getPackageFilePath(packCtx, zipName); err != nil {
+		return err
+	}
+	if err = lockArtifact(packCtx, zipName); err != nil {
+		return err
+	}
+
+	err = writePackageFile(packCtx, zipName, func(tmpPath string) error {
+		return writeZipArchive(bundlePath, tmpPath, packCtx)
+	})
+	if err != nil {
+		return err
+	}
+	packCtx.progress.done()
+	packCtx.artifactPath = zipName
+	log.Infof("Bundle is packed successfully to %s.", zipName)
+
+	if packCtx.WithChecksum {
+		return writeChecksumFile(zipName)
+	}
+	return nil
+}
+
+// writeZipArchive creates deflate-compressed zip archive of specified path.
+func writeZipArchive(srcDirPath string, destFilePath string, packCtx *PackCtx) error {
+	destFile, err := os.Create(destFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create result zip file %s: %s", destFilePath, err)
+	}
+	defer destFile.Close()
+
+	if err = writeZip(srcDirPath, destFile, packCtx); err != nil {
+		return err
+	}
+	return destFile.Close()
+}
+
+// writeZip writes deflate-compressed zip archive of specified path to the writer. The files
+// matching the store globs are stored uncompressed.
+func writeZip(srcDirPath string, writer io.Writer, packCtx *PackCtx) error {
+	zipWriter := zip.NewWriter(writer)
+
+	files, err := collectPackFiles(packCtx, srcDirPath)
+	if err != nil {
+		return err
+	}
+
+	writeEntry := func(filePath, relPath string, fileInfo os.FileInfo) error {
+		if relPath == "." {
+			return nil
+		}
+
+		zipHeader, err := zip.FileInfoHeader(fileInfo)
+		if err != nil {
+			return err
+		}
+		zipHeader.Name = filepath.ToSlash(relPath)
+		if fileInfo.IsDir() {
+			zipHeader.Name += "/"
+		} else if isIncluded(packCtx.Archive.storePatterns, zipHeader.Name) {
+			zipHeader.Method = zip.Store
+		} else {
+			zipHeader.Method = zip.Deflate
+		}
+
+		entryWriter, err := zipWriter.CreateHeader(zipHeader)
+		if err != nil {
+			return err
+		}
+
+		if fileInfo.Mode().Type() == os.ModeSymlink {
+			// Symlink target is stored as an entry content.
+			linkTarget, err := os.Readlink(filePath)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(filePath, configure.InstancesEnabledDirName) {
+				// Make instances enabled symlinks relative the same way as for tarball.
+				srcPath, _ := filepath.EvalSymlinks(filePath)
+				linkTarget = filepath.Join("..", filepath.Base(srcPath))
+			}
+			if _, err = entryWriter.Write([]byte(filepath.ToSlash(linkTarget))); err != nil {
+				return err
+			}
+			packCtx.progress.fileAdded(zipHeader.Name, fileInfo.Size())
+			return nil
+		}
+
+		if fileInfo.Mode().IsRegular() {
+			if err := writeFileToWriter(filePath, entryWriter); err != nil {
+				return err
+			}
+			packCtx.progress.fileAdded(zipHeader.Name, fileInfo.Size())
+		}
+		return nil
+	}
+	for _, file := range files {
+		if err = packCtx.operation.processFile(file.path); err != nil {
+			return err
+		}
+		if err = writeEntry(file.path, file.relPath, file.info); err != nil {
+			return err
+		}
+	}
+	// The central directory is written on close, the archive is corrupted if it fails.
+	if err = zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finish zip archive: %s", err)
+	}
+	return nil
+}
+
+// getZipSuffix returns suffix for a zip archive.
+func getZipSuffix() (string, error) {
+	arch, err := util.GetArch()
+	if err != nil {
+		return "", err
+	}
+	zipSuffix := strings.Join([]string{"", arch, "zip"}, ".")
+	return zipSuffix, nil
+}
@@ -0,0 +1,125 @@
+package pack
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/configure"
+	"github.com/tarantool/tt/cli/pack/test_helpers"
+	"github.com/tarantool/tt/cli/util"
+)
+
+func TestGetZipPackageName(t *testing.T) {
+	testDir, err := filepath.Abs(".")
+	require.NoErrorf(t, err, "failed to get the test directory absolute path")
+
+	arch, err := util.GetArch()
+	require.NoError(t, err)
+
+	suffix, err := getZipSuffix()
+	require.NoError(t, err)
+
+	packCtx := &PackCtx{Type: Zip, Name: "test", Version: "2.1.1"}
+	opts := &config.CliOpts{Env: &config.TtEnvOpts{InstancesEnabled: testDir}}
+	packageName, err := getPackageFileName(packCtx, opts, suffix, true)
+	require.NoError(t, err)
+	assert.Equal(t, "test-2.1.1."+arch+".zip", packageName)
+}
+
+func TestWriteZipArchive(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(srcDir, []string{
+		"app", configure.InstancesEnabledDirName}))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "app", "init.lua"),
+		[]byte("print('hello')"), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(srcDir, "app"),
+		filepath.Join(srcDir, configure.InstancesEnabledDirName, "app")))
+
+	zipPath := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, writeZipArchive(srcDir, zipPath, &PackCtx{}))
+
+	reader, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	entries := map[string]*zip.File{}
+	for _, file := range reader.File {
+		entries[file.Name] = file
+	}
+	require.Len(t, entries, 4)
+
+	require.Contains(t, entries, "app/")
+	assert.True(t, entries["app/"].FileInfo().IsDir())
+
+	require.Contains(t, entries, "app/init.lua")
+	assert.Equal(t, zip.Deflate, entries["app/init.lua"].Method)
+	content := readZipEntry(t, entries["app/init.lua"])
+	assert.Equal(t, "print('hello')", content)
+
+	linkName := configure.InstancesEnabledDirName + "/app"
+	require.Contains(t, entries, linkName)
+	assert.Equal(t, os.ModeSymlink, entries[linkName].Mode().Type())
+	assert.Equal(t, "../app", readZipEntry(t, entries[linkName]))
+}
+
+func TestWriteZipArchiveStoreGlobs(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, test_helpers.CreateDirs(srcDir, []string{"app/data"}))
+	for _, name := range []string{"app/init.lua", "app/data/set.gz", "app/data/readme.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(name), 0644))
+	}
+
+	packCtx := &PackCtx{}
+	var err error
+	packCtx.Archive.storePatterns, err = parsePathGlobs("store", []string{"**/*.gz"})
+	require.NoError(t, err)
+	zipPath := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, writeZipArchive(srcDir, zipPath, packCtx))
+
+	reader, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+	defer reader.Close()
+	methods := map[string]uint16{}
+	for _, file := range reader.File {
+		methods[file.Name] = file.Method
+		if !file.FileInfo().IsDir() {
+			assert.Equal(t, file.Name, readZipEntry(t, file))
+		}
+	}
+	assert.Equal(t, zip.Store, methods["app/data/set.gz"])
+	assert.Equal(t, zip.Deflate, methods["app/data/readme.txt"])
+	assert.Equal(t, zip.Deflate, methods["app/init.lua"])
+}
+
+func readZipEntry(t *testing.T, file *zip.File) string {
+	entryReader, err := file.Open()
+	require.NoError(t, err)
+	defer entryReader.Close()
+	content, err := io.ReadAll(entryReader)
+	require.NoError(t, err)
+	return string(content)
+}
+
+// failingWriter fails all writes.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk is broken")
+}
+
+func TestWriteZipCloseError(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "init.lua"), []byte("x"), 0644))
+
+	// The small archive is buffered, so the write fails when the central directory
+	// is flushed on close.
+	err := writeZip(srcDir, failingWriter{}, &PackCtx{})
+	assert.ErrorContains(t, err, "failed to finish zip archive: disk is broken")
+}
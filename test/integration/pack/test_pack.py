@@ -591,7 +591,7 @@ def test_pack_tgz_missing_app(tt_cmd, tmp_path):
         [tt_cmd, "pack", "tgz", "--app-list", "unexisting-app"],
         cwd=base_dir, env=dict(os.environ, PWD=base_dir))
 
-    assert rc == 1
+    assert rc == 2
 
 
 @pytest.mark.slow
@@ -780,7 +780,7 @@ def test_pack_tgz_multiple_apps_compat(tt_cmd, tmp_path):
         [tt_cmd, "pack", "tgz", "--cartridge-compat"],
         cwd=base_dir, env=dict(os.environ, PWD=base_dir))
 
-    assert rc == 1
+    assert rc == 2
 
 
 def test_pack_deb_compat(tt_cmd, tmp_path):
@@ -795,7 +795,7 @@ def test_pack_deb_compat(tt_cmd, tmp_path):
         [tt_cmd, "pack", "dep", "--cartridge-compat"],
         cwd=base_dir, env=dict(os.environ, PWD=base_dir))
 
-    assert rc == 1
+    assert rc == 3
 
 
 def test_pack_rpm_compat(tt_cmd, tmp_path):
@@ -810,7 +810,7 @@ def test_pack_rpm_compat(tt_cmd, tmp_path):
         [tt_cmd, "pack", "rpm", "--cartridge-compat"],
         cwd=base_dir, env=dict(os.environ, PWD=base_dir))
 
-    assert rc == 1
+    assert rc == 2
 
 
 def prepare_deb_test_cases(tt_cmd) -> list:
@@ -920,14 +920,14 @@ def test_pack_tgz_empty_app_directory(tt_cmd, tmp_path):
         [tt_cmd, "pack", "tgz", "--app-list", "empty_app"],
         cwd=base_dir, env=dict(os.environ, PWD=base_dir))
 
-    assert rc == 1
+    assert rc == 2
 
     base_dir = tmp_path
     rc, output = run_command_and_get_output(
         [tt_cmd, "pack", "tgz"],
         cwd=base_dir, env=dict(os.environ, PWD=base_dir))
 
-    assert rc == 1
+    assert rc == 2
 
 
 def test_pack_tgz_empty_enabled(tt_cmd, tmp_path):
@@ -945,7 +945,7 @@ def test_pack_tgz_empty_enabled(tt_cmd, tmp_path):
         [tt_cmd, "pack", "tgz"],
         cwd=base_dir, env=dict(os.environ, PWD=base_dir))
 
-    assert rc == 1
+    assert rc == 2
 
 
 def test_pack_tgz_links_to_binaries(tt_cmd, tmp_path):
@@ -993,7 +993,8 @@ def test_pack_incorrect_pack_type(tt_cmd, tmp_path):
                     dirs_exist_ok=True)
 
     expected_output = "incorrect combination of command parameters: " \
-                      "invalid argument \"de\" for \"tt pack\""
+                      "unknown package type \"de\" for \"tt pack\", supported types: " \
+                      "tgz, zip, deb, rpm, docker, appimage"
 
     rc, output = run_command_and_get_output(
         [tt_cmd, "pack", "de"],